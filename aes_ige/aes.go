@@ -54,6 +54,24 @@ func Decrypt(msg, key, checkData []byte) ([]byte, error) {
 	return out, nil
 }
 
+// EncryptIGE и DecryptIGE работают с уже готовыми ключом и вектором, например в RSA_PAD при
+// обмене ключами. длина msg должна делиться на 16
+func EncryptIGE(msg, key, iv []byte) ([]byte, error) {
+	out := make([]byte, len(msg))
+	if err := doAES256IGEencrypt(msg, out, key, iv); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func DecryptIGE(msg, key, iv []byte) ([]byte, error) {
+	out := make([]byte, len(msg))
+	if err := doAES256IGEdecrypt(msg, out, key, iv); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func doAES256IGEencrypt(data, out, key, iv []byte) error {
 	c, err := NewCipher(key, iv)
 	if err != nil {
@@ -0,0 +1,272 @@
+package mtproto
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+// запросы, которые сервер должен выполнить строго по порядку (отправить сообщение, потом закрепить
+// его), можно отправлять не дожидаясь ответов: каждый следующий оборачивается в invokeAfterMsg с
+// msg_id предыдущего, и сервер сам начнет его только когда выполнится предыдущий. если предыдущий
+// запрос завершился ошибкой, то зависящие от него сервер не выполняет, а отвечает MSG_WAIT_FAILED
+// https://core.telegram.org/api/invoking#invokeaftermsg
+
+// InvokeAfterMsgParams это invokeAfterMsg#cb9f372d {X:Type} msg_id:long query:!X = X
+type InvokeAfterMsgParams struct {
+	MsgID int64
+	Query serialize.TLEncoder
+}
+
+func (_ *InvokeAfterMsgParams) CRC() uint32 {
+	return 0xcb9f372d
+}
+
+func (t *InvokeAfterMsgParams) Encode() []byte {
+	buf := serialize.NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutLong(t.MsgID)
+	buf.PutRawBytes(t.Query.Encode())
+	return buf.Result()
+}
+
+func (t *InvokeAfterMsgParams) DecodeFrom(d *serialize.Decoder) {
+	panic("makes no sense")
+}
+
+// InvokeAfterMsgsParams это invokeAfterMsgs#3dc4b4f0 {X:Type} msg_ids:Vector<long> query:!X = X
+type InvokeAfterMsgsParams struct {
+	MsgIDs []int64
+	Query  serialize.TLEncoder
+}
+
+func (_ *InvokeAfterMsgsParams) CRC() uint32 {
+	return 0x3dc4b4f0
+}
+
+func (t *InvokeAfterMsgsParams) Encode() []byte {
+	buf := serialize.NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutVector(t.MsgIDs)
+	buf.PutRawBytes(t.Query.Encode())
+	return buf.Result()
+}
+
+func (t *InvokeAfterMsgsParams) DecodeFrom(d *serialize.Decoder) {
+	panic("makes no sense")
+}
+
+// ChainError возвращается из Chain.Run, если один из запросов цепочки не выполнился
+type ChainError struct {
+	// Step это номер шага, который вернул ошибку. шаги, которые от него зависят, не выполнялись
+	Step int
+	Err  error
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("step %v of chain: %v", e.Step, e.Err)
+}
+
+func (e *ChainError) Cause() error  { return e.Err }
+func (e *ChainError) Unwrap() error { return e.Err }
+
+// ErrChainDependencyResent лежит в ChainError, если шаг, от которого зависит этот, пришлось
+// отправить заново с новым msg_id. этот шаг ушел раньше и ждет старый msg_id, так что сервер мог
+// выполнить его не по порядку. повторять его небезопасно, сервер мог его уже выполнить
+var ErrChainDependencyResent = errors.New("step it depends on was resent, order is not guaranteed")
+
+// chainStep оборачивает запрос в invokeAfterMsg(s) только при отправке, с теми msg_id, которые у
+// зависимостей в этот момент. если зависимость отправляется заново (новая соль, bad_msg_notification
+// и т.п.), то ее новый msg_id получат только те, кто уйдет после этого, в том числе повторно. шаги,
+// которые уже отправлены, ждут старый msg_id, поэтому Run их больше не ждет, см. Chain
+type chainStep struct {
+	query serialize.TL
+	// как декодировать ответ, если это вектор, см. MakeRequestAsSlice
+	as    reflect.Type
+	after []*chainStep
+	msgID int64
+}
+
+func (s *chainStep) request() serialize.TL {
+	switch len(s.after) {
+	case 0:
+		return s.query
+	case 1:
+		return &InvokeAfterMsgParams{MsgID: s.after[0].msgID, Query: s.query}
+	default:
+		ids := make([]int64, len(s.after))
+		for i, step := range s.after {
+			ids[i] = step.msgID
+		}
+		return &InvokeAfterMsgsParams{MsgIDs: ids, Query: s.query}
+	}
+}
+
+func (s *chainStep) CRC() uint32 {
+	return s.request().CRC()
+}
+
+func (s *chainStep) Encode() []byte {
+	return s.request().Encode()
+}
+
+func (s *chainStep) DecodeFrom(d *serialize.Decoder) {
+	panic("makes no sense")
+}
+
+func (s *chainStep) setMsgID(msgID int64) {
+	s.msgID = msgID
+}
+
+// Chain это запросы, которые сервер выполняет в заданном порядке. создается через NewChain,
+// заполняется через Then и After и выполняется через Run. использовать одну цепочку из нескольких
+// горутин нельзя.
+//
+// если шаг пришлось отправить заново с новым msg_id (обычно после смены соли или обрыва соединения),
+// то шаги, которые уже ушли и зависят от него, ссылаются на старый msg_id, и сервер может выполнить
+// их не по порядку. такие шаги завершаются ошибкой ErrChainDependencyResent
+type Chain struct {
+	m     *MTProto
+	steps []*chainStep
+	err   error
+}
+
+func (m *MTProto) NewChain() *Chain {
+	return &Chain{m: m}
+}
+
+// Then добавляет запрос, который выполнится после предыдущего шага. возвращает номер шага
+func (c *Chain) Then(query serialize.TL) int {
+	return c.ThenAsSlice(query, nil)
+}
+
+// ThenAsSlice то же самое, что и Then, но ответ декодируется как слайс as, см. MakeRequestAsSlice
+func (c *Chain) ThenAsSlice(query serialize.TL, as reflect.Type) int {
+	if len(c.steps) == 0 {
+		return c.AfterAsSlice(query, as)
+	}
+	return c.AfterAsSlice(query, as, len(c.steps)-1)
+}
+
+// After добавляет запрос, который выполнится после всех шагов steps (номера, которые вернули Then и
+// After). если steps не указаны, запрос ни от чего не зависит. возвращает номер шага
+func (c *Chain) After(query serialize.TL, steps ...int) int {
+	return c.AfterAsSlice(query, nil, steps...)
+}
+
+// AfterAsSlice то же самое, что и After, но ответ декодируется как слайс as, см. MakeRequestAsSlice
+func (c *Chain) AfterAsSlice(query serialize.TL, as reflect.Type, steps ...int) int {
+	step := &chainStep{query: query, as: as}
+	for _, i := range steps {
+		if i < 0 || i >= len(c.steps) {
+			if c.err == nil {
+				c.err = errors.Errorf("step %v depends on unknown step %v", len(c.steps), i)
+			}
+			continue
+		}
+		step.after = append(step.after, c.steps[i])
+	}
+	c.steps = append(c.steps, step)
+	return len(c.steps) - 1
+}
+
+// Run отправляет все запросы сразу и ждет ответы. результаты возвращаются по номерам шагов. если
+// какой-то запрос не выполнился, возвращается *ChainError с первым таким шагом, а результаты шагов,
+// которые от него зависят, будут nil
+func (c *Chain) Run(ctx context.Context) ([]serialize.TL, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type pending struct {
+		resp  chan serialize.TL
+		msgID int64
+	}
+	sent := make([]pending, len(c.steps))
+	dropPending := func(from int) {
+		for i := from; i < len(c.steps); i++ {
+			if sent[i].resp != nil {
+				c.m.dropAnswer(sent[i].msgID, c.steps[i])
+			}
+		}
+	}
+
+	// отправляются строго по порядку, иначе msg_id шагов, от которых зависит запрос, еще не известны
+	for i, step := range c.steps {
+		resp, msgID, err := c.m.sendPacketNew(ctx, step, step.as)
+		if err != nil {
+			dropPending(0)
+			return nil, &ChainError{Step: i, Err: errors.Wrap(err, "sending message")}
+		}
+		sent[i] = pending{resp: resp, msgID: msgID}
+	}
+
+	results := make([]serialize.TL, len(c.steps))
+	failed := make(map[*chainStep]bool)
+	resent := make(map[*chainStep]bool)
+	var chainErr *ChainError
+	for i, step := range c.steps {
+		if dependsOn(step, failed) {
+			// сервер такой запрос не выполнит и ответит MSG_WAIT_FAILED, ждать нечего
+			failed[step] = true
+			continue
+		}
+		if dependsOn(step, resent) {
+			// шаг ждет msg_id, которого сервер уже не выполнит, и порядок ему не гарантирован
+			c.m.dropAnswer(sent[i].msgID, step)
+			failed[step] = true
+			if chainErr == nil {
+				chainErr = &ChainError{Step: i, Err: ErrChainDependencyResent}
+			}
+			continue
+		}
+
+		res, err := c.m.waitResponse(ctx, sent[i].resp, sent[i].msgID, step, step.as)
+		if step.msgID != sent[i].msgID {
+			resent[step] = true
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				dropPending(i + 1)
+				return results, &ChainError{Step: i, Err: err}
+			}
+			failed[step] = true
+			if chainErr == nil {
+				chainErr = &ChainError{Step: i, Err: err}
+			}
+			continue
+		}
+		results[i] = res
+	}
+
+	if chainErr != nil {
+		return results, chainErr
+	}
+	return results, nil
+}
+
+func dependsOn(step *chainStep, steps map[*chainStep]bool) bool {
+	for _, dep := range step.after {
+		if steps[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+// Sequence выполняет запросы строго по порядку: каждый следующий начинается только после того, как
+// выполнился предыдущий. ошибка, если есть, это *ChainError с номером запроса
+func (m *MTProto) Sequence(ctx context.Context, queries ...serialize.TL) ([]serialize.TL, error) {
+	chain := m.NewChain()
+	for _, query := range queries {
+		chain.Then(query)
+	}
+	return chain.Run(ctx)
+}
@@ -0,0 +1,193 @@
+package mtproto
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+// takeMessages ждет, пока в очереди окажется n сообщений
+func takeMessages(t *testing.T, m *MTProto, n int) []*outgoingMessage {
+	t.Helper()
+	var messages []*outgoingMessage
+	deadline := time.Now().Add(5 * time.Second)
+	for len(messages) < n && time.Now().Before(deadline) {
+		taken, _ := m.outgoing.take()
+		messages = append(messages, taken...)
+		time.Sleep(time.Millisecond)
+	}
+	if len(messages) != n {
+		t.Fatalf("expected %v messages, got %v", n, len(messages))
+	}
+	return messages
+}
+
+type chainResult struct {
+	results []serialize.TL
+	err     error
+}
+
+func runChain(c *Chain) chan chainResult {
+	done := make(chan chainResult, 1)
+	go func() {
+		results, err := c.Run(context.Background())
+		done <- chainResult{results, err}
+	}()
+	return done
+}
+
+func TestSequence(t *testing.T) {
+	m := newTestClockMTProto()
+	done := make(chan chainResult, 1)
+	go func() {
+		results, err := m.Sequence(context.Background(), &PingParams{PingID: 0}, &PingParams{PingID: 1}, &PingParams{PingID: 2})
+		done <- chainResult{results, err}
+	}()
+
+	// все запросы уходят сразу, каждый следующий ждет предыдущий
+	messages := takeMessages(t, m, 3)
+	for i, msg := range messages {
+		var expected []byte
+		if i == 0 {
+			expected = (&PingParams{PingID: 0}).Encode()
+		} else {
+			expected = (&InvokeAfterMsgParams{MsgID: messages[i-1].msgID, Query: &PingParams{PingID: int64(i)}}).Encode()
+		}
+		if !bytes.Equal(msg.body, expected) {
+			t.Errorf("step %v sent as %x, want %x", i, msg.body, expected)
+		}
+	}
+
+	for i, msg := range messages {
+		if err := m.writeRPCResponse(int(msg.msgID), &serialize.Pong{PingID: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res := <-done
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	for i, r := range res.results {
+		if pong, ok := r.(*serialize.Pong); !ok || pong.PingID != int64(i) {
+			t.Errorf("step %v: got %#v", i, r)
+		}
+	}
+}
+
+func TestChainAfterSeveral(t *testing.T) {
+	m := newTestClockMTProto()
+	c := m.NewChain()
+	first := c.After(&PingParams{PingID: 0})
+	second := c.After(&PingParams{PingID: 1})
+	c.After(&PingParams{PingID: 2}, first, second)
+	done := runChain(c)
+
+	messages := takeMessages(t, m, 3)
+	if !bytes.Equal(messages[1].body, (&PingParams{PingID: 1}).Encode()) {
+		t.Error("independent step must be sent as is")
+	}
+	expected := (&InvokeAfterMsgsParams{
+		MsgIDs: []int64{messages[0].msgID, messages[1].msgID},
+		Query:  &PingParams{PingID: 2},
+	}).Encode()
+	if !bytes.Equal(messages[2].body, expected) {
+		t.Errorf("last step sent as %x, want %x", messages[2].body, expected)
+	}
+
+	for _, msg := range messages {
+		_ = m.writeRPCResponse(int(msg.msgID), &serialize.Pong{})
+	}
+	if res := <-done; res.err != nil {
+		t.Fatal(res.err)
+	}
+}
+
+func TestChainError(t *testing.T) {
+	m := newTestClockMTProto()
+	c := m.NewChain()
+	c.Then(&PingParams{PingID: 0})
+	c.Then(&PingParams{PingID: 1})
+	c.Then(&PingParams{PingID: 2})
+	independent := c.After(&PingParams{PingID: 3})
+	done := runChain(c)
+
+	messages := takeMessages(t, m, 4)
+	_ = m.writeRPCResponse(int(messages[0].msgID), &serialize.Pong{})
+	_ = m.writeRPCResponse(int(messages[1].msgID), &serialize.RpcError{ErrorCode: 400, ErrorMessage: "MESSAGE_ID_INVALID"})
+	// последний шаг от упавшего не зависит, его ответ все равно нужен
+	_ = m.writeRPCResponse(int(messages[independent].msgID), &serialize.Pong{})
+
+	res := <-done
+	chainErr, ok := res.err.(*ChainError)
+	if !ok || chainErr.Step != 1 {
+		t.Fatalf("expected error of step 1, got %v", res.err)
+	}
+	if e, ok := chainErr.Err.(*ErrResponseCode); !ok || e.Message != "MESSAGE_ID_INVALID" {
+		t.Errorf("unexpected cause: %v", chainErr.Err)
+	}
+	if res.results[0] == nil || res.results[1] != nil || res.results[2] != nil || res.results[independent] == nil {
+		t.Errorf("unexpected results: %#v", res.results)
+	}
+}
+
+func TestChainUnknownStep(t *testing.T) {
+	m := newTestClockMTProto()
+	c := m.NewChain()
+	c.After(&PingParams{}, 5)
+	if _, err := c.Run(context.Background()); err == nil {
+		t.Error("expected error for unknown step")
+	}
+}
+
+func TestChainDependencyResent(t *testing.T) {
+	m := newTestClockMTProto()
+	c := m.NewChain()
+	c.Then(&PingParams{PingID: 0})
+	c.Then(&PingParams{PingID: 1})
+	done := runChain(c)
+
+	messages := takeMessages(t, m, 2)
+	// первый шаг уходит заново с новым msg_id, а второй уже ждет старый
+	_ = m.writeRPCResponse(int(messages[0].msgID), &serialize.ErrorSessionConfigsChanged{})
+	resent := takeMessages(t, m, 1)
+	if resent[0].msgID == messages[0].msgID {
+		t.Fatal("step was resent with the same msg_id")
+	}
+	_ = m.writeRPCResponse(int(resent[0].msgID), &serialize.Pong{})
+
+	res := <-done
+	chainErr, ok := res.err.(*ChainError)
+	if !ok || chainErr.Step != 1 || chainErr.Err != ErrChainDependencyResent {
+		t.Fatalf("expected resent dependency error of step 1, got %v", res.err)
+	}
+	if res.results[0] == nil || res.results[1] != nil {
+		t.Errorf("unexpected results: %#v", res.results)
+	}
+}
+
+func TestChainAsSlice(t *testing.T) {
+	m := newTestClockMTProto()
+	c := m.NewChain()
+	as := reflect.TypeOf([]int64{})
+	c.Then(&PingParams{})
+	c.ThenAsSlice(&PingParams{}, as)
+	done := runChain(c)
+
+	messages := takeMessages(t, m, 2)
+	m.mutex.Lock()
+	got := m.msgsIdDecodeAsVector[messages[1].msgID]
+	m.mutex.Unlock()
+	if got != as {
+		t.Errorf("step must be decoded as %v, got %v", as, got)
+	}
+
+	_ = m.writeRPCResponse(int(messages[0].msgID), &serialize.Pong{})
+	_ = m.writeRPCResponse(int(messages[1].msgID), &serialize.Pong{})
+	if res := <-done; res.err != nil {
+		t.Fatal(res.err)
+	}
+}
@@ -0,0 +1,128 @@
+package mtproto
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+// msg_id это время отправки, и сервер отклоняет сообщения, время которых отличается от его
+// собственного больше чем на 300 секунд в прошлое или 30 в будущее (bad_msg_notification с кодами 16
+// и 17). поэтому msg_id считаются не по локальным часам, а по часам сервера: разница между ними
+// запоминается при создании ключа, по первому пакету каждого соединения и по new_session_created, и
+// уточняется по msg_id уведомлений об ошибке. разница нигде не сохраняется, ее всегда можно узнать
+// заново
+// https://core.telegram.org/mtproto/service_messages_about_messages#notice-of-ignored-error-message
+
+// maxMsgAge это сколько сервер помнит отправленные ему сообщения. про контейнеры старше этого можно
+// забыть: уведомлений об ошибках в них уже не придет
+const maxMsgAge = 300 * time.Second
+
+// TimeOffset возвращает, насколько часы сервера идут впереди локальных
+func (m *MTProto) TimeOffset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&m.timeOffset))
+}
+
+// serverTime возвращает текущее время по часам сервера
+func (m *MTProto) serverTime() time.Time {
+	return time.Now().Add(m.TimeOffset())
+}
+
+// syncTime запоминает разницу между часами сервера и локальными. serverTime это время, которое
+// сервер считал текущим, когда отправлял сообщение
+func (m *MTProto) syncTime(serverTime time.Time) {
+	atomic.StoreInt64(&m.timeOffset, int64(time.Until(serverTime)))
+	atomic.StoreInt32(&m.timeSynced, 1)
+}
+
+// syncTimeFromPacket сверяет часы по msg_id пакета от сервера, если в этом соединении они еще не
+// сверялись. пакет к этому моменту уже расшифрован и проверен, так что подделать время нельзя
+func (m *MTProto) syncTimeFromPacket(msgID int64) {
+	if atomic.LoadInt32(&m.timeSynced) == 0 {
+		m.syncTime(utils.MessageIDTime(msgID))
+	}
+}
+
+// isTimeSynced показывает, сверены ли часы с сервером в текущем соединении
+func (m *MTProto) isTimeSynced() bool {
+	return atomic.LoadInt32(&m.timeSynced) != 0
+}
+
+// rememberContainer запоминает, какие сообщения ушли в контейнере containerID: если сервер
+// отклонит контейнер целиком, заново нужно отправить каждое из них
+func (m *MTProto) rememberContainer(containerID int64, msgIDs []int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.sentContainers == nil {
+		m.sentContainers = make(map[int64][]int64)
+	}
+	oldest := utils.MessageIDAt(m.serverTime().Add(-maxMsgAge))
+	for id := range m.sentContainers {
+		if id < oldest {
+			delete(m.sentContainers, id)
+		}
+	}
+	m.sentContainers[containerID] = msgIDs
+}
+
+// affectedMessages возвращает id сообщений, которых касается уведомление про msgID: сам msgID, или
+// все, что было в контейнере с таким id
+func (m *MTProto) affectedMessages(msgID int64) []int64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if ids, ok := m.sentContainers[msgID]; ok {
+		delete(m.sentContainers, msgID)
+		return ids
+	}
+	return []int64{msgID}
+}
+
+// handleBadMsg разбирает bad_msg_notification. если сообщение отклонено из-за времени, то часы
+// подводятся, и сообщение уходит заново. остальные коды означают ошибку в самом клиенте, ее получит
+// тот, кто ждет ответа. serverMsgID это msg_id самого уведомления, по нему видно время сервера
+func (m *MTProto) handleBadMsg(serverMsgID int64, message *serialize.BadMsgNotification) error {
+	switch BadSystemMessageCode(message.Code) {
+	case ErrBadMsgIdTooLow, ErrBadMsgIdTooHigh:
+		m.syncTime(utils.MessageIDTime(serverMsgID))
+		m.resendMessages(m.affectedMessages(message.BadMsgID))
+		return nil
+
+	case ErrBadMsgMessageTooOld:
+		// сообщение слишком долго пролежало у клиента, с новым msg_id сервер его примет
+		m.resendMessages(m.affectedMessages(message.BadMsgID))
+		return nil
+	}
+
+	badMsgErr := BadMsgErrorFromNative(message)
+	delivered := false
+	for _, id := range m.affectedMessages(message.BadMsgID) {
+		if m.writeRPCResponse(int(id), badMsgErr) == nil {
+			delivered = true
+		}
+	}
+	if !delivered {
+		// ошибку некому отдать, хотя бы предупредим
+		return errors.Wrapf(badMsgErr, "message %v", message.BadMsgID)
+	}
+	return nil
+}
+
+// resendMessages будит тех, кто ждет ответа на сообщения msgIDs: им приходит
+// ErrorSessionConfigsChanged, и запросы уходят заново с новыми msg_id. остальные запросы не
+// трогаются, т.к. сервер их уже получил
+func (m *MTProto) resendMessages(msgIDs []int64) {
+	for _, id := range msgIDs {
+		m.mutex.Lock()
+		delete(m.msgsIdDecodeAsVector, id)
+		m.mutex.Unlock()
+
+		// если ответа никто не ждет (например, это был msgs_ack), то и отправлять заново нечего
+		_ = m.writeRPCResponse(int(id), &serialize.ErrorSessionConfigsChanged{})
+	}
+}
@@ -0,0 +1,160 @@
+package mtproto
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+func newTestClockMTProto() *MTProto {
+	m := &MTProto{
+		encrypted:            true,
+		mutex:                &sync.Mutex{},
+		outgoing:             newOutgoingQueue(),
+		responseChannels:     make(map[int64]chan serialize.TL),
+		msgsIdDecodeAsVector: make(map[int64]reflect.Type),
+	}
+	m.resetAck()
+	return m
+}
+
+func TestMessageIDTime(t *testing.T) {
+	now := time.Now()
+	got := utils.MessageIDTime(utils.MessageIDAt(now))
+	if got.Unix() != now.Unix() {
+		t.Errorf("time from msg_id %v, want %v", got, now)
+	}
+}
+
+// takeMessage ждет, пока в очереди появится сообщение
+func takeMessage(t *testing.T, m *MTProto) *outgoingMessage {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if messages, _ := m.outgoing.take(); len(messages) > 0 {
+			return messages[0]
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("no message was sent")
+	return nil
+}
+
+func TestBadMsgTimeSync(t *testing.T) {
+	m := newTestClockMTProto()
+	skew := time.Hour
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := m.makeRequest(context.Background(), &PingParams{PingID: 1}, nil)
+		result <- err
+	}()
+	first := takeMessage(t, m)
+
+	// сервер отвечает, что msg_id слишком маленький, и в msg_id уведомления его время
+	serverMsgID := utils.MessageIDAt(time.Now().Add(skew)) | 1
+	err := m.processResponse(int(serverMsgID), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.BadMsgNotification{BadMsgID: first.msgID, Code: int32(ErrBadMsgIdTooLow)}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if offset := m.TimeOffset(); offset < skew-time.Minute || offset > skew+time.Minute {
+		t.Errorf("time offset %v, want about %v", offset, skew)
+	}
+
+	second := takeMessage(t, m)
+	sentAt := utils.MessageIDTime(second.msgID)
+	if d := time.Until(sentAt); d < skew-time.Minute || d > skew+time.Minute {
+		t.Errorf("resent message has time %v, want about now + %v", sentAt, skew)
+	}
+
+	_ = m.writeRPCResponse(int(second.msgID), &serialize.Pong{MsgID: second.msgID, PingID: 1})
+	if err := <-result; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBadMsgInContainer(t *testing.T) {
+	m := newTestClockMTProto()
+	waiting := map[int64]chan serialize.TL{1: make(chan serialize.TL, 1), 2: make(chan serialize.TL, 1), 3: make(chan serialize.TL, 1)}
+	for id, ch := range waiting {
+		m.responseChannels[id] = ch
+	}
+	containerID := m.newMsgID()
+	m.rememberContainer(containerID, []int64{1, 2})
+
+	err := m.handleBadMsg(m.newMsgID(), &serialize.BadMsgNotification{BadMsgID: containerID, Code: int32(ErrBadMsgIdTooHigh)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []int64{1, 2} {
+		select {
+		case resp := <-waiting[id]:
+			if _, ok := resp.(*serialize.ErrorSessionConfigsChanged); !ok {
+				t.Errorf("message %v: expected resend, got %#v", id, resp)
+			}
+		default:
+			t.Errorf("message %v from container was not resent", id)
+		}
+	}
+	if len(waiting[3]) != 0 {
+		t.Error("message outside of container must not be resent")
+	}
+}
+
+func TestBadMsgError(t *testing.T) {
+	m := newTestClockMTProto()
+	waiting := make(chan serialize.TL, 1)
+	m.responseChannels[1] = waiting
+
+	notification := &serialize.BadMsgNotification{BadMsgID: 1, Code: int32(ErrBadMsgIncorrectMsgIdBits)}
+	if err := m.handleBadMsg(m.newMsgID(), notification); err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := (<-waiting).(*BadMsgError); !ok || e.Code != int32(ErrBadMsgIncorrectMsgIdBits) {
+		t.Error("waiting request must get BadMsgError")
+	}
+
+	// ответа на это сообщение никто не ждет, ошибку нужно хотя бы вернуть
+	notification.BadMsgID = 2
+	if err := m.handleBadMsg(m.newMsgID(), notification); err == nil {
+		t.Error("expected error for message nobody waits for")
+	}
+}
+
+func TestTimeSyncFromServerMessages(t *testing.T) {
+	m := newTestClockMTProto()
+	m.sessionStorage = NewMemorySessionStorage()
+	skew := -2 * time.Minute
+
+	// сессия загружена из хранилища, часы еще не сверялись: сверяем по первому пакету
+	m.syncTimeFromPacket(utils.MessageIDAt(time.Now().Add(skew)) | 1)
+	if offset := m.TimeOffset(); offset < skew-time.Second || offset > skew+time.Second {
+		t.Errorf("time offset %v, want about %v", offset, skew)
+	}
+	// следующие пакеты того же соединения часы уже не трогают
+	m.syncTimeFromPacket(utils.MessageIDAt(time.Now()) | 1)
+	if offset := m.TimeOffset(); offset > skew+time.Second {
+		t.Errorf("time offset changed to %v by second packet", offset)
+	}
+
+	// а new_session_created сверяет их всегда
+	msgID := utils.MessageIDAt(time.Now().Add(time.Hour)) | 1
+	err := m.processResponse(int(msgID), 1, &serialize.EncryptedMessage{
+		Msg: (&serialize.NewSessionCreated{FirstMsgID: 1, UniqueID: 2, ServerSalt: 3}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset := m.TimeOffset(); offset < time.Hour-time.Second || offset > time.Hour+time.Second {
+		t.Errorf("time offset %v after new_session_created, want about 1h", offset)
+	}
+}
@@ -260,9 +260,22 @@ func GenerateMethods(file *jen.File, data *FileStructure) error {
 			// firstErrorReturn = jen.Lit(0)
 		}
 
+		// метод без контекста просто вызывает вариант с context.Background()
+		callParameters := []jen.Code{jen.Qual("context", "Background").Call()}
+		if argsAsSingleItem {
+			callParameters = append(callParameters, jen.Id("params"))
+		}
+
+		f = jen.Func().Params(jen.Id("c").Id("*Client")).Id(methodName).Params(funcParameters...).Params(jen.Id(assertedType), jen.Error()).Block(
+			jen.Return(jen.Id("c." + methodName + "Context").Call(callParameters...)),
+		)
+
+		file.Add(f)
+		file.Add(jen.Line())
+
 		calls = make([]jen.Code, 0)
 		calls = append(calls,
-			jen.List(jen.Id("data"), jen.Err()).Op(":=").Id("c.MakeRequest").Call(requestStruct),
+			jen.List(jen.Id("data"), jen.Err()).Op(":=").Id("c.MakeRequestContext").Call(jen.Id("ctx"), requestStruct),
 			jen.If(jen.Err().Op("!=").Nil()).Block(
 				jen.Return(firstErrorReturn, jen.Qual("github.com/pkg/errors", "Wrap").Call(jen.Err(), jen.Lit("sedning "+methodName))),
 			),
@@ -275,7 +288,8 @@ func GenerateMethods(file *jen.File, data *FileStructure) error {
 			jen.Return(jen.Id("resp"), jen.Nil()),
 		)
 
-		f = jen.Func().Params(jen.Id("c").Id("*Client")).Id(methodName).Params(funcParameters...).Params(jen.Id(assertedType), jen.Error()).Block(
+		ctxParameters := append([]jen.Code{jen.Id("ctx").Qual("context", "Context")}, funcParameters...)
+		f = jen.Func().Params(jen.Id("c").Id("*Client")).Id(methodName+"Context").Params(ctxParameters...).Params(jen.Id(assertedType), jen.Error()).Block(
 			calls...,
 		)
 
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto"
+	"github.com/lonesta/mtproto/importer"
+)
+
+const helpMsg = `import-session
+usage: import-session -from telethon|pyrogram|tdesktop -in <path> [-out dir] [flags]
+
+Converts sessions of other clients into mtproto sessions. Every imported
+account is written into -out as <account>/<dc>.json (see
+mtproto.FileSessionStorage): the main session goes to 0.json, keys for other
+datacenters (tdesktop only) to their own numbers.
+
+  telethon  -in is a path to the .session file
+  pyrogram  -in is a session string, or a path to file with it
+  tdesktop  -in is a path to tdata directory, local passcode is -passcode
+
+Flags:
+`
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, helpMsg)
+		flag.PrintDefaults()
+	}
+	from := flag.String("from", "", "source format: telethon, pyrogram or tdesktop")
+	in := flag.String("in", "", "source file, directory or string")
+	passcode := flag.String("passcode", "", "tdesktop local passcode")
+	out := flag.String("out", "sessions", "directory to write sessions into")
+	account := flag.String("account", "", "account name for the storage (default is user id, or dc for unknown users)")
+	passphrase := flag.String("passphrase", "", "encrypt written sessions with this passphrase")
+	printString := flag.Bool("string", false, "print session strings instead of writing files")
+	flag.Parse()
+
+	if *from == "" || *in == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	accounts, err := load(*from, *in, *passcode)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "import failed:", err)
+		os.Exit(1)
+	}
+	if *account != "" && len(accounts) > 1 {
+		fmt.Fprintln(os.Stderr, "-account can't be used, source has", len(accounts), "accounts")
+		os.Exit(1)
+	}
+
+	var storage mtproto.SessionStorage = mtproto.NewFileSessionStorage(*out)
+	if *passphrase != "" {
+		storage = mtproto.NewPassphraseSessionStorage(storage, *passphrase)
+	}
+
+	for _, a := range accounts {
+		if *printString {
+			str, err := mtproto.EncodeSessionString(a.Session)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "encoding session:", err)
+				os.Exit(1)
+			}
+			fmt.Println(str)
+			continue
+		}
+
+		name := *account
+		if name == "" {
+			name = accountName(a.Session)
+		}
+		if err := save(storage, name, a); err != nil {
+			fmt.Fprintln(os.Stderr, "saving account "+name+":", err)
+			os.Exit(1)
+		}
+		fmt.Printf("imported %v: dc %v, %v more keys\n", name, a.Session.DC, len(a.Other))
+	}
+}
+
+func load(from, in, passcode string) ([]*importer.TDesktopAccount, error) {
+	switch from {
+	case "telethon":
+		session, err := importer.Telethon(in)
+		if err != nil {
+			return nil, err
+		}
+		return []*importer.TDesktopAccount{{Session: session}}, nil
+
+	case "pyrogram":
+		str := in
+		if data, err := ioutil.ReadFile(in); err == nil {
+			str = strings.TrimSpace(string(data))
+		}
+		session, err := importer.Pyrogram(str)
+		if err != nil {
+			return nil, err
+		}
+		return []*importer.TDesktopAccount{{Session: session}}, nil
+
+	case "tdesktop":
+		return importer.TDesktop(in, passcode)
+
+	default:
+		return nil, errors.New("unknown source format: " + from)
+	}
+}
+
+func save(storage mtproto.SessionStorage, account string, a *importer.TDesktopAccount) error {
+	sessions := append([]*mtproto.Session{a.Session}, a.Other...)
+	for i, session := range sessions {
+		data, err := mtproto.EncodeSession(session)
+		if err != nil {
+			return err
+		}
+
+		dc := session.DC
+		if i == 0 {
+			dc = mtproto.HomeDC
+		}
+		if err := storage.Store(account, dc, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func accountName(s *mtproto.Session) string {
+	if s.UserID != 0 {
+		return strconv.FormatInt(s.UserID, 10)
+	}
+	return "dc" + strconv.Itoa(s.DC)
+}
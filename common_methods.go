@@ -1,7 +1,9 @@
 package mtproto
 
 import (
+	"context"
 	"reflect"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -139,8 +141,78 @@ func (m *MTProto) SetClientDHParams(nonce, serverNonce *serialize.Int128, encryp
 	return resp, nil
 }
 
-// rpc_drop_answer
-// get_future_salts
+type RpcDropAnswerParams struct {
+	ReqMsgID int64
+}
+
+func (_ *RpcDropAnswerParams) CRC() uint32 {
+	return 0x58e4a740
+}
+
+func (t *RpcDropAnswerParams) Encode() []byte {
+	buf := serialize.NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutLong(t.ReqMsgID)
+	return buf.Result()
+}
+
+func (t *RpcDropAnswerParams) DecodeFrom(d *serialize.Decoder) {
+	t.ReqMsgID = d.PopLong()
+}
+
+// RpcDropAnswer просит сервер не присылать ответ на запрос с id reqMsgID
+func (m *MTProto) RpcDropAnswer(ctx context.Context, reqMsgID int64) (serialize.RpcDropAnswer, error) {
+	data, err := m.MakeRequestContext(ctx, &RpcDropAnswerParams{
+		ReqMsgID: reqMsgID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "sending RpcDropAnswer")
+	}
+
+	resp, ok := data.(serialize.RpcDropAnswer)
+	if !ok {
+		return nil, errors.New("got invalid response type: " + reflect.TypeOf(data).String())
+	}
+
+	return resp, nil
+}
+
+type GetFutureSaltsParams struct {
+	Num int32
+}
+
+func (_ *GetFutureSaltsParams) CRC() uint32 {
+	return 0xb921bd04
+}
+
+func (t *GetFutureSaltsParams) Encode() []byte {
+	buf := serialize.NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutInt(t.Num)
+	return buf.Result()
+}
+
+func (t *GetFutureSaltsParams) DecodeFrom(d *serialize.Decoder) {
+	t.Num = d.PopInt()
+}
+
+// GetFutureSalts запрашивает у сервера num солей, которые будут действовать дальше, начиная с
+// текущей. ответ приходит не в rpc_result, см. processResponse
+func (m *MTProto) GetFutureSalts(ctx context.Context, num int32) (*serialize.FutureSalts, error) {
+	data, err := m.MakeRequestContext(ctx, &GetFutureSaltsParams{
+		Num: num,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "sending GetFutureSalts")
+	}
+
+	resp, ok := data.(*serialize.FutureSalts)
+	if !ok {
+		return nil, errors.New("got invalid response type: " + reflect.TypeOf(data).String())
+	}
+
+	return resp, nil
+}
 
 type PingParams struct {
 	PingID int64
@@ -179,13 +251,49 @@ func (m *MTProto) Ping(pingID int64) (*serialize.Pong, error) {
 
 // ping_delay_disconnect
 // destroy_session
-// http_wait
+
+type HttpWaitParams struct {
+	MaxDelay  int32
+	WaitAfter int32
+	MaxWait   int32
+}
+
+func (_ *HttpWaitParams) CRC() uint32 {
+	return 0x9299359f
+}
+
+func (t *HttpWaitParams) Encode() []byte {
+	buf := serialize.NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutInt(t.MaxDelay)
+	buf.PutInt(t.WaitAfter)
+	buf.PutInt(t.MaxWait)
+	return buf.Result()
+}
+
+func (t *HttpWaitParams) DecodeFrom(d *serialize.Decoder) {
+	t.MaxDelay = d.PopInt()
+	t.WaitAfter = d.PopInt()
+	t.MaxWait = d.PopInt()
+}
+
+// HttpWait говорит серверу, сколько держать открытым http запрос, если ответить нечего. ответа
+// на http_wait нет, сервер просто отвечает на сам http запрос
+func (m *MTProto) HttpWait(maxDelay, waitAfter, maxWait time.Duration) error {
+	_, err := m.MakeRequest(&HttpWaitParams{
+		MaxDelay:  int32(maxDelay / time.Millisecond),
+		WaitAfter: int32(waitAfter / time.Millisecond),
+		MaxWait:   int32(maxWait / time.Millisecond),
+	})
+	if err != nil {
+		return errors.Wrap(err, "sending HttpWait")
+	}
+
+	return nil
+}
 
 // set_client_DH_params#f5045f1f nonce:int128 server_nonce:int128 encrypted_data:bytes = Set_client_DH_params_answer;
 
 // rpc_drop_answer#58e4a740 req_msg_id:long = RpcDropAnswer;
-// get_future_salts#b921bd04 num:int = FutureSalts;
 // ping_delay_disconnect#f3427b8c ping_id:long disconnect_delay:int = Pong;
 // destroy_session#e7512126 session_id:long = DestroySessionRes;
-
-// http_wait#9299359f max_delay:int wait_after:int max_wait:int = HttpWait;
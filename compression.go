@@ -0,0 +1,40 @@
+package mtproto
+
+import (
+	"context"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+// запросы больше этого размера (в байтах) сжимаются в gzip_packed, если в Config не указано иное
+const defaultCompressionThreshold = 1024
+
+type noCompressionKey struct{}
+
+// WithoutCompression запрещает сжимать запросы, отправленные с этим контекстом. нужно, если запрос
+// заведомо не сжимается (например, уже сжатые файлы), что бы не тратить на него время
+func WithoutCompression(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCompressionKey{}, true)
+}
+
+func compressionDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noCompressionKey{}).(bool)
+	return disabled
+}
+
+// encodeRequest кодирует запрос и, если он достаточно большой, заворачивает его в gzip_packed.
+// сжатая версия используется только если она действительно получилась меньше. служебные сообщения
+// не сжимаются никогда, сервер принимает gzip_packed только у запросов к API
+func (m *MTProto) encodeRequest(ctx context.Context, request serialize.TL) []byte {
+	body := request.Encode()
+	if m.compressionThreshold <= 0 || len(body) < m.compressionThreshold || compressionDisabled(ctx) ||
+		isServiceMessage(request) {
+		return body
+	}
+
+	packed := serialize.GzipPack(body)
+	if len(packed) >= len(body) {
+		return body
+	}
+	return packed
+}
@@ -0,0 +1,79 @@
+package mtproto
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+func TestEncodeRequest(t *testing.T) {
+	m := &MTProto{compressionThreshold: defaultCompressionThreshold}
+
+	// auth.bindTempAuthKey это обычный запрос к API, его можно сжимать
+	compressible := &BindTempAuthKeyParams{EncryptedMessage: make([]byte, 2000)}
+	random := &BindTempAuthKeyParams{EncryptedMessage: make([]byte, 2000)}
+	_, _ = rand.Read(random.EncryptedMessage)
+	small := &BindTempAuthKeyParams{EncryptedMessage: make([]byte, 16)}
+	// служебные сообщения не сжимаются, даже если большие
+	service := &serialize.MsgsAck{MsgIds: make([]int64, 1000)}
+
+	cases := []struct {
+		name       string
+		ctx        context.Context
+		request    serialize.TL
+		compressed bool
+	}{
+		{"small", context.Background(), small, false},
+		{"compressible", context.Background(), compressible, true},
+		{"incompressible", context.Background(), random, false},
+		{"opt-out", WithoutCompression(context.Background()), compressible, false},
+		{"service message", context.Background(), service, false},
+	}
+
+	for _, c := range cases {
+		body := m.encodeRequest(c.ctx, c.request)
+		if !c.compressed {
+			if !bytes.Equal(body, c.request.Encode()) {
+				t.Errorf("%v: request must be sent as is", c.name)
+			}
+			continue
+		}
+
+		if len(body) >= len(c.request.Encode()) {
+			t.Errorf("%v: compressed request is not smaller", c.name)
+		}
+		d := serialize.NewDecoder(body)
+		if crc := d.PopCRC(); crc != serialize.CrcGzipPacked {
+			t.Fatalf("%v: expected gzip_packed, got %#x", c.name, crc)
+		}
+		if unpacked := unpackGzip(t, d.PopMessage()); !bytes.Equal(unpacked, c.request.Encode()) {
+			t.Errorf("%v: unpacked request differs from original", c.name)
+		}
+	}
+}
+
+func TestEncodeRequestDisabled(t *testing.T) {
+	m := &MTProto{compressionThreshold: -1}
+	request := &BindTempAuthKeyParams{EncryptedMessage: make([]byte, 2000)}
+	if !bytes.Equal(m.encodeRequest(context.Background(), request), request.Encode()) {
+		t.Error("request was compressed with disabled compression")
+	}
+}
+
+func unpackGzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
@@ -0,0 +1,351 @@
+package mtproto
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xelaj/errs"
+
+	"github.com/lonesta/mtproto/transport"
+	"github.com/lonesta/mtproto/utils"
+)
+
+// у каждого датацентра свой ключ авторизации, поэтому что бы, например, скачать файл с другого
+// датацентра, нужно отдельное соединение со своим ключом. такие соединения создаются по первому
+// требованию через ConnectDC и живут, пока не отключат основное. ключи хранятся в том же
+// SessionStorage, под номерами датацентров
+
+type dcConns struct {
+	// настройки, с которыми создавался клиент, из них собираются соединения с другими датацентрами
+	config Config
+	// основное соединение, если это соединение с другим датацентром
+	parent *MTProto
+
+	connsMutex sync.Mutex
+	conns      map[int]*dcConn
+	// вызывается для каждого нового соединения, см. SetDCConnectHandler
+	onConnect func(conn *MTProto) error
+
+	// не дает переезжать в другой датацентр нескольким запросам одновременно
+	migrateMutex sync.Mutex
+}
+
+type dcConn struct {
+	// закрывается, когда соединение готово (или не получилось)
+	ready chan struct{}
+	conn  *MTProto
+	err   error
+}
+
+// SetDCConnectHandler задает функцию, которая вызывается для каждого нового соединения с другим
+// датацентром до того, как ConnectDC его вернет. обычно она переносит туда авторизацию
+// (auth.exportAuthorization в основном датацентре и auth.importAuthorization в новом). если функция
+// вернула ошибку, то соединение закрывается, а ConnectDC попробует снова при следующем вызове
+func (m *MTProto) SetDCConnectHandler(f func(conn *MTProto) error) {
+	m.connsMutex.Lock()
+	m.onConnect = f
+	m.connsMutex.Unlock()
+}
+
+// ConnectDC возвращает соединение с датацентром dc, открывая его, если нужно. для основного
+// датацентра возвращается сам клиент. безопасно вызывать из нескольких горутин: соединение
+// создается только один раз
+func (m *MTProto) ConnectDC(dc int) (*MTProto, error) {
+	if m.parent != nil {
+		return m.parent.ConnectDC(dc)
+	}
+	if dc == m.DC() {
+		return m, nil
+	}
+
+	m.connsMutex.Lock()
+	if c, ok := m.conns[dc]; ok {
+		m.connsMutex.Unlock()
+		<-c.ready
+		if c.err != nil {
+			return nil, c.err
+		}
+		return c.conn, nil
+	}
+
+	c := &dcConn{ready: make(chan struct{})}
+	m.conns[dc] = c
+	onConnect := m.onConnect
+	m.connsMutex.Unlock()
+
+	c.conn, c.err = m.newDCConn(dc, onConnect)
+	if c.err != nil {
+		// забываем неудачную попытку, что бы следующий вызов попробовал еще раз. те, кто уже ждет
+		// этого соединения, получат ту же ошибку
+		m.connsMutex.Lock()
+		if m.conns[dc] == c {
+			delete(m.conns, dc)
+		}
+		m.connsMutex.Unlock()
+	}
+	close(c.ready)
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.conn, nil
+}
+
+func (m *MTProto) newDCConn(dc int, onConnect func(conn *MTProto) error) (*MTProto, error) {
+	config := m.config
+	// хранилище уже обернуто в шифрование, если оно было нужно
+	config.SessionStorage = m.sessionStorage
+	config.SessionPassphrase = ""
+	config.AuthKeyFile = ""
+	config.DC = dc
+	// через прокси подключаемся к тому же адресу, прокси сам выберет датацентр по номеру
+	t, proxyAddr := m.endpoint()
+	config.Transport = transport.WithDC(t, dc)
+	if transport.ViaProxy(config.Transport) {
+		config.ServerHost = proxyAddr
+	} else {
+		addr, ok := m.dcAddress(dc)
+		if !ok {
+			return nil, errors.Errorf("DC with id %v not found", dc)
+		}
+		config.ServerHost = addr
+	}
+
+	conn, err := NewMTProto(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "setting up connection to dc %v", dc)
+	}
+	conn.parent = m
+	conn.Warnings = m.Warnings
+	conn.RecoverFunc = m.RecoverFunc
+	// часы у датацентров одни, так что разница с ними уже известна
+	atomic.StoreInt64(&conn.timeOffset, int64(m.TimeOffset()))
+	m.mutex.Lock()
+	for k, v := range m.dclist {
+		conn.dclist[k] = v
+	}
+	conn.onTempKeyBound = m.onTempKeyBound
+	userID := m.userID
+	m.mutex.Unlock()
+
+	conn.mutex.Lock()
+	// в сохраненный ключ авторизацию уже переносили, второй раз это не нужно
+	authorized := conn.authorized && conn.userID == userID
+	conn.userID = userID
+	conn.mutex.Unlock()
+
+	err = conn.CreateConnection()
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to dc %v", dc)
+	}
+
+	if onConnect != nil && !authorized {
+		err = onConnect(conn)
+		if err != nil {
+			_ = conn.Disconnect()
+			return nil, errors.Wrapf(err, "preparing connection to dc %v", dc)
+		}
+
+		conn.mutex.Lock()
+		conn.authorized = true
+		conn.mutex.Unlock()
+		err = conn.SaveSession()
+		if err != nil {
+			m.warn(errors.Wrapf(err, "saving key of dc %v", dc))
+		}
+	}
+
+	return conn, nil
+}
+
+// dropDCConn закрывает соединение с датацентром dc, если оно было открыто
+func (m *MTProto) dropDCConn(dc int) {
+	m.connsMutex.Lock()
+	c, ok := m.conns[dc]
+	delete(m.conns, dc)
+	m.connsMutex.Unlock()
+	if !ok {
+		return
+	}
+
+	<-c.ready
+	if c.err != nil {
+		return
+	}
+	err := c.conn.Disconnect()
+	if err != nil {
+		m.warn(errors.Wrapf(err, "disconnecting from dc %v", dc))
+	}
+}
+
+// homeDCState это все, что меняется при переезде основного соединения в другой датацентр. если
+// переехать не вышло, клиент возвращается с этим туда, откуда уезжал
+type homeDCState struct {
+	authKey, authKeyHash         []byte
+	permAuthKey, permAuthKeyHash []byte
+	tempKeyExpiresAt             time.Time
+	salt                         int64
+	encrypted                    bool
+	dc                           int
+	addr                         string
+	transport                    transport.Transport
+	userID                       int64
+	authorized                   bool
+	seq                          msgSequenceState
+}
+
+func (m *MTProto) saveHomeDCState() *homeDCState {
+	s := &homeDCState{
+		tempKeyExpiresAt: m.tempKeyExpiry(),
+		salt:             m.GetServerSalt(),
+		encrypted:        m.isEncrypted(),
+		seq:              m.seq.state(),
+	}
+	m.mutex.Lock()
+	s.authKey, s.authKeyHash = m.authKey, m.authKeyHash
+	s.permAuthKey, s.permAuthKeyHash = m.permAuthKey, m.permAuthKeyHash
+	s.dc, s.addr, s.transport = m.dc, m.addr, m.transport
+	s.userID, s.authorized = m.userID, m.authorized
+	m.mutex.Unlock()
+	return s
+}
+
+// restoreHomeDC возвращает клиент в датацентр, из которого он не смог уехать, и переподключается
+// к нему в фоне. сессия остается той же, так что запросы, которые ждали ответа, дождутся его как
+// после обычного обрыва соединения
+func (m *MTProto) restoreHomeDC(s *homeDCState, unsent []*outgoingMessage) {
+	m.mutex.Lock()
+	m.authKey, m.authKeyHash = s.authKey, s.authKeyHash
+	m.permAuthKey, m.permAuthKeyHash = s.permAuthKey, s.permAuthKeyHash
+	m.dc, m.addr, m.transport = s.dc, s.addr, s.transport
+	m.userID, m.authorized = s.userID, s.authorized
+	m.mutex.Unlock()
+	m.setTempKeyExpiresAt(s.tempKeyExpiresAt)
+	m.setServerSalt(s.salt)
+	m.setEncrypted(s.encrypted)
+	m.seq.restore(s.seq)
+
+	// новый датацентр мог успеть записаться как основной
+	if s.encrypted {
+		err := m.SaveSession()
+		if err != nil {
+			m.warn(errors.Wrap(err, "saving session"))
+		}
+	}
+
+	go m.reconnectWith(s.seq.session, unsent)
+}
+
+// switchHomeDC переносит основное соединение в датацентр dc (после USER_MIGRATE, PHONE_MIGRATE и
+// NETWORK_MIGRATE). ключ старого датацентра сохраняется под его номером, а для нового берется уже
+// сохраненный ключ или создается новый. новый датацентр запоминается как основной. запросы, которые
+// ждали ответа, отправляются заново, если не успели уйти в старый датацентр, см. resendToNewSession.
+// если переехать не вышло, то клиент остается в старом датацентре, см. restoreHomeDC
+func (m *MTProto) switchHomeDC(dc int) error {
+	m.migrateMutex.Lock()
+	defer m.migrateMutex.Unlock()
+
+	oldDC := m.DC()
+	if oldDC == dc {
+		// пока ждали мьютекс, кто-то уже переехал
+		return nil
+	}
+
+	t, _ := m.endpoint()
+	addr, found := m.dcAddress(dc)
+	if !found && !transport.ViaProxy(t) {
+		return errors.Errorf("DC with id %v not found", dc)
+	}
+
+	// соединение с этим датацентром станет основным, второе с тем же ключом не нужно
+	m.dropDCConn(dc)
+
+	old := m.saveHomeDCState()
+	// соединение все равно закрыто, так что ошибка закрытия ни на что не влияет
+	err := m.Stop()
+	if err != nil {
+		m.warn(errors.Wrap(err, "stopping session"))
+	}
+	unsent := m.outgoing.dropMessages()
+
+	// ключ старого датацентра еще пригодится, например для файлов, которые там лежат
+	if m.isEncrypted() && oldDC != 0 {
+		err = m.saveSession(oldDC)
+		if err != nil {
+			m.warn(errors.Wrapf(err, "saving key of dc %v", oldDC))
+		}
+	}
+
+	m.mutex.Lock()
+	userID := m.userID
+	m.mutex.Unlock()
+	err = m.loadSession(dc)
+	switch {
+	case err == nil:
+		m.setEncrypted(true)
+	case errs.IsNotFound(err):
+		// ключа для этого датацентра еще нет, CreateConnection его создаст
+		m.mutex.Lock()
+		m.authKey, m.authKeyHash = nil, nil
+		m.mutex.Unlock()
+		m.setServerSalt(0)
+		m.resetTempKey()
+		m.setEncrypted(false)
+	default:
+		m.restoreHomeDC(old, unsent)
+		return errors.Wrapf(err, "loading key of dc %v", dc)
+	}
+
+	m.mutex.Lock()
+	if m.userID == 0 {
+		m.userID = userID
+	}
+	// основному соединению переносить авторизацию некуда
+	m.authorized = false
+	// через прокси адрес не меняется, прокси сам переключится на нужный датацентр
+	m.dc = dc
+	m.transport = transport.WithDC(m.transport, dc)
+	if !transport.ViaProxy(m.transport) {
+		m.addr = addr
+	}
+	m.mutex.Unlock()
+	// с другим ключом это уже другая сессия
+	m.seq.reset(utils.GenerateSessionID())
+
+	err = m.CreateConnection()
+	if err != nil {
+		m.restoreHomeDC(old, unsent)
+		return errors.Wrap(err, "recreating session")
+	}
+
+	// теперь это основной датацентр аккаунта
+	err = m.SaveSession()
+	if err != nil {
+		m.warn(errors.Wrap(err, "saving session"))
+	}
+
+	m.resendPendingRequests(old.seq.session, unsent)
+	return nil
+}
+
+// closeDCConns закрывает все соединения с другими датацентрами. ключи остаются в хранилище, так
+// что следующий ConnectDC их переиспользует
+func (m *MTProto) closeDCConns() {
+	m.connsMutex.Lock()
+	conns := m.conns
+	m.conns = make(map[int]*dcConn)
+	m.connsMutex.Unlock()
+
+	for dc, c := range conns {
+		<-c.ready
+		if c.err != nil {
+			continue
+		}
+		err := c.conn.Disconnect()
+		if err != nil {
+			m.warn(errors.Wrapf(err, "disconnecting from dc %v", dc))
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package mtproto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+// listenSilent принимает соединения и читает из них, ничего не отвечая
+func listenSilent(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(ioutil.Discard, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	return l
+}
+
+func storeTestSession(t *testing.T, storage SessionStorage, dc int, s *Session) {
+	data, err := EncodeSession(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Store("acc", dc, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConnectDC(t *testing.T) {
+	l := listenSilent(t)
+	defer l.Close()
+
+	homeKey, dcKey := bytes.Repeat([]byte{2}, 256), bytes.Repeat([]byte{4}, 256)
+	storage := NewMemorySessionStorage()
+	storeTestSession(t, storage, HomeDC, &Session{
+		Key: homeKey, Hash: utils.AuthKeyHash(homeKey), Salt: make([]byte, 8), Hostname: l.Addr().String(), DC: 2,
+	})
+	// ключи уже есть, иначе клиент начнет создавать их, а сервер тут ничего не отвечает
+	for _, dc := range []int{4, 5} {
+		storeTestSession(t, storage, dc, &Session{
+			Key: dcKey, Hash: utils.AuthKeyHash(dcKey), Salt: make([]byte, 8), Hostname: l.Addr().String(), DC: dc,
+		})
+	}
+
+	m, err := NewMTProto(Config{SessionStorage: storage, Account: "acc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetDCStorages(map[int]string{4: l.Addr().String(), 5: l.Addr().String()})
+	if err := m.CreateConnection(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Disconnect()
+
+	var calls int32
+	fail := int32(1)
+	m.SetDCConnectHandler(func(conn *MTProto) error {
+		atomic.AddInt32(&calls, 1)
+		if conn.DC() == 5 && atomic.CompareAndSwapInt32(&fail, 1, 0) {
+			return errors.New("import failed")
+		}
+		return nil
+	})
+
+	if conn, err := m.ConnectDC(2); err != nil || conn != m {
+		t.Fatalf("home dc must return the client itself, got %v, %v", conn, err)
+	}
+
+	// одновременные вызовы получают одно и то же соединение
+	conns := make([]*MTProto, 10)
+	var wg sync.WaitGroup
+	for i := range conns {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conn, err := m.ConnectDC(4)
+			if err != nil {
+				t.Error(err)
+			}
+			conns[i] = conn
+		}(i)
+	}
+	wg.Wait()
+	for _, conn := range conns {
+		if conn != conns[0] {
+			t.Fatal("got different connections for the same dc")
+		}
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("handler called %v times, want 1", calls)
+	}
+
+	dc4 := conns[0]
+	if dc4.DC() != 4 || !bytes.Equal(dc4.GetAuthKey(), dcKey) {
+		t.Errorf("dc 4 connection uses dc %v and wrong key", dc4.DC())
+	}
+	// соединения с другими датацентрами создаются через основное
+	if conn, err := dc4.ConnectDC(4); err != nil || conn != dc4 {
+		t.Errorf("expected the same connection, got %v, %v", conn, err)
+	}
+
+	// ключ сохраняется под номером датацентра, основная сессия не трогается
+	dc4.serverSalt = 42
+	if err := dc4.SaveSession(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := storage.Load("acc", HomeDC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if home, _ := DecodeSession(data); !bytes.Equal(home.Key, homeKey) {
+		t.Error("home session was overwritten")
+	}
+
+	// после ошибки обработчика следующий вызов пробует снова
+	if _, err := m.ConnectDC(5); err == nil {
+		t.Fatal("expected error from handler")
+	}
+	if _, err := m.ConnectDC(5); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.ConnectDC(100); err == nil {
+		t.Error("expected error for unknown dc")
+	}
+
+	// в сохраненные ключи авторизация уже перенесена, повторно обработчик не вызывается
+	before := atomic.LoadInt32(&calls)
+	m.closeDCConns()
+	if _, err := m.ConnectDC(4); err != nil {
+		t.Fatal(err)
+	}
+	if after := atomic.LoadInt32(&calls); after != before {
+		t.Errorf("handler called again for authorized key")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	l := listenSilent(t)
+	defer l.Close()
+
+	homeKey, dcKey := bytes.Repeat([]byte{2}, 256), bytes.Repeat([]byte{4}, 256)
+	storage := NewMemorySessionStorage()
+	storeTestSession(t, storage, HomeDC, &Session{
+		Key: homeKey, Hash: utils.AuthKeyHash(homeKey), Salt: make([]byte, 8), Hostname: l.Addr().String(), DC: 2,
+		UserID: 100,
+	})
+	storeTestSession(t, storage, 4, &Session{
+		Key: dcKey, Hash: utils.AuthKeyHash(dcKey), Salt: make([]byte, 8), Hostname: l.Addr().String(), DC: 4,
+	})
+
+	m, err := NewMTProto(Config{SessionStorage: storage, Account: "acc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetDCStorages(map[int]string{2: l.Addr().String(), 4: l.Addr().String()})
+	if err := m.CreateConnection(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Disconnect()
+
+	// запрос, который уже ушел в старый датацентр, мог там выполниться, повторять его нельзя
+	pending := make(chan serialize.TL, 1)
+	m.mutex.Lock()
+	m.responseChannels[1] = pending
+	m.mutex.Unlock()
+
+	// FILE_MIGRATE в тот же датацентр бесполезно повторять
+	fileMigrate := &ErrResponseCode{Message: "FILE_MIGRATE_X", AdditionalInfo: 2}
+	if _, err := m.makeRequestInDC(context.Background(), fileMigrate, &PingParams{}, nil); err != fileMigrate {
+		t.Errorf("expected the same error, got %v", err)
+	}
+
+	err = m.tryToProcessErr(&ErrResponseCode{Message: "USER_MIGRATE_X", AdditionalInfo: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.DC() != 4 || !bytes.Equal(m.GetAuthKey(), dcKey) {
+		t.Errorf("client is in dc %v with wrong key", m.DC())
+	}
+	select {
+	case resp := <-pending:
+		if _, ok := resp.(*UnconfirmedRequestError); !ok {
+			t.Errorf("unexpected response %T", resp)
+		}
+	default:
+		t.Error("pending request wasn't woken up")
+	}
+
+	load := func(dc int) *Session {
+		data, err := storage.Load("acc", dc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := DecodeSession(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	if home := load(HomeDC); home.DC != 4 || !bytes.Equal(home.Key, dcKey) || home.UserID != 100 {
+		t.Errorf("home session wasn't moved: dc %v, user %v", home.DC, home.UserID)
+	}
+	if old := load(2); !bytes.Equal(old.Key, homeKey) {
+		t.Error("key of the old dc wasn't kept")
+	}
+
+	// повторная миграция в тот же датацентр ничего не делает
+	if err := m.switchHomeDC(4); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrateFailure(t *testing.T) {
+	l := listenSilent(t)
+	defer l.Close()
+	// датацентр 5 недоступен
+	closed := listenSilent(t)
+	closed.Close()
+
+	homeKey, dcKey := bytes.Repeat([]byte{2}, 256), bytes.Repeat([]byte{4}, 256)
+	storage := NewMemorySessionStorage()
+	storeTestSession(t, storage, HomeDC, &Session{
+		Key: homeKey, Hash: utils.AuthKeyHash(homeKey), Salt: make([]byte, 8), Hostname: l.Addr().String(), DC: 2,
+	})
+	for dc, addr := range map[int]string{4: l.Addr().String(), 5: closed.Addr().String()} {
+		storeTestSession(t, storage, dc, &Session{
+			Key: dcKey, Hash: utils.AuthKeyHash(dcKey), Salt: make([]byte, 8), Hostname: addr, DC: dc,
+		})
+	}
+
+	m, err := NewMTProto(Config{SessionStorage: storage, Account: "acc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetDCStorages(map[int]string{2: l.Addr().String(), 4: l.Addr().String(), 5: closed.Addr().String()})
+	if err := m.CreateConnection(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Disconnect()
+	session := m.GetSessionID()
+
+	// соединение с другим датацентром переезжает не само, туда уходит только запрос
+	dc4, err := m.ConnectDC(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := make(chan serialize.TL, 1)
+	resp <- &serialize.RpcError{ErrorCode: 303, ErrorMessage: "USER_MIGRATE_5"}
+	if _, err := dc4.waitResponse(context.Background(), resp, 1, &PingParams{}, nil); err == nil {
+		t.Error("expected error connecting to dc 5")
+	}
+	if dc4.DC() != 4 || m.DC() != 2 {
+		t.Errorf("connections moved: dc4 is in %v, home is in %v", dc4.DC(), m.DC())
+	}
+
+	if err := m.switchHomeDC(5); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// клиент остался в старом датацентре, с той же сессией, и подключается к нему заново
+	if m.DC() != 2 || !bytes.Equal(m.GetAuthKey(), homeKey) || m.GetSessionID() != session {
+		t.Errorf("client is in dc %v after failed migration", m.DC())
+	}
+	data, err := storage.Load("acc", HomeDC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if home, _ := DecodeSession(data); home.DC != 2 || !bytes.Equal(home.Key, homeKey) {
+		t.Errorf("home session points to dc %v", home.DC)
+	}
+	_, msgID, err := m.sendPacketNew(context.Background(), &PingParams{PingID: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for m.outgoing.queued(msgID) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if m.outgoing.queued(msgID) {
+		t.Error("client didn't reconnect after failed migration")
+	}
+}
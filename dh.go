@@ -0,0 +1,162 @@
+package mtproto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"sync"
+
+	"github.com/xelaj/go-dry"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+// проверки параметров диффи-хеллмана, которые обязан делать клиент. если их пропустить, то сервер
+// (или кто-то посередине) может подсунуть параметры, при которых общий ключ легко подобрать
+// https://core.telegram.org/mtproto/auth_key#presenting-proof-of-work-server-authentication
+// https://core.telegram.org/mtproto/security_guidelines
+
+const (
+	dhPrimeBits = 2048
+	// g_a и g_b должны отстоять от 1 и dh_prime-1 хотя бы на 2^{2048-64}
+	dhSafetyMarginBits = dhPrimeBits - 64
+	// сколько раз подряд можно получить dh_gen_retry, прежде чем сдаться
+	maxDHGenRetries = 5
+)
+
+// HandshakeError возвращается, если то, что сервер прислал при обмене ключами, не прошло проверку.
+// ключ в этом случае не создается
+type HandshakeError struct {
+	// Check это то, что не прошло проверку: "dh_prime", "g", "g_a", "new_nonce_hash1" и т.д.
+	Check  string
+	Reason string
+}
+
+func (e *HandshakeError) Error() string {
+	return "handshake: invalid " + e.Check + ": " + e.Reason
+}
+
+// известные хорошие dh_prime, проверять их заново на простоту долго. телеграм сейчас присылает
+// только один
+var (
+	knownDHPrimesMutex sync.RWMutex
+	knownDHPrimes      = map[string]bool{
+		"c71caeb9c6b1c9048e6c522f70f13f73980d40238e3e21c14934d037563d930f48198a0aa7c14058229493d22530f4dbfa" +
+			"336f6e0ac925139543aed44cce7c3720fd51f69458705ac68cd4fe6b6b13abdc9746512969328454f18faf8c595f642477fe" +
+			"96bb2a941d5bcd1d4ac8cc49880708fa9b378e3c4f3a9060bee67cf9a4a4a695811051907e162753b56b0f6b410dba74d8a8" +
+			"4b2a14b3144e0ef1284754fd17ed950d5965b4b9dd46582db1178d169c6bc465b0d6ff9ca3928fef5b9ae4e418fc15e83ebe" +
+			"a0f87fa9ff5eed70050ded2849f47bf959d956850ce929851f0d8115f635b105ee2e4e15d04b2454bf6f4fadf034b1040311" +
+			"9cd8e3b92fcc5b": true,
+	}
+)
+
+// checkDHPrime проверяет, что dh_prime это 2048-битное безопасное простое число, то есть что
+// (dh_prime-1)/2 тоже простое
+func checkDHPrime(dhPrime *big.Int) error {
+	if dhPrime.BitLen() != dhPrimeBits {
+		return &HandshakeError{Check: "dh_prime", Reason: "must be 2048 bits long"}
+	}
+
+	key := hex.EncodeToString(dhPrime.Bytes())
+	knownDHPrimesMutex.RLock()
+	known := knownDHPrimes[key]
+	knownDHPrimesMutex.RUnlock()
+	if known {
+		return nil
+	}
+
+	if !dhPrime.ProbablyPrime(64) {
+		return &HandshakeError{Check: "dh_prime", Reason: "not a prime"}
+	}
+	half := big.NewInt(0).Rsh(dhPrime, 1)
+	if !half.ProbablyPrime(64) {
+		return &HandshakeError{Check: "dh_prime", Reason: "not a safe prime"}
+	}
+
+	knownDHPrimesMutex.Lock()
+	knownDHPrimes[key] = true
+	knownDHPrimesMutex.Unlock()
+	return nil
+}
+
+// checkDHGenerator проверяет, что g порождает циклическую подгруппу порядка (dh_prime-1)/2. для
+// безопасного простого это сводится к тому, является ли g квадратичным вычетом, а это видно по
+// остатку dh_prime от деления на небольшое число
+func checkDHGenerator(g int32, dhPrime *big.Int) error {
+	mod := func(n int64) int64 {
+		return big.NewInt(0).Mod(dhPrime, big.NewInt(n)).Int64()
+	}
+
+	var ok bool
+	switch g {
+	case 2:
+		ok = mod(8) == 7
+	case 3:
+		ok = mod(3) == 2
+	case 4:
+		ok = true
+	case 5:
+		r := mod(5)
+		ok = r == 1 || r == 4
+	case 6:
+		r := mod(24)
+		ok = r == 19 || r == 23
+	case 7:
+		r := mod(7)
+		ok = r == 3 || r == 5 || r == 6
+	default:
+		return &HandshakeError{Check: "g", Reason: "must be between 2 and 7"}
+	}
+	if !ok {
+		return &HandshakeError{Check: "g", Reason: "doesn't generate subgroup of order (dh_prime-1)/2"}
+	}
+	return nil
+}
+
+// checkDHValue проверяет, что g_a (или g_b) лежит в (1, dh_prime-1), и при этом не ближе
+// 2^{2048-64} к краям
+func checkDHValue(name string, value, dhPrime *big.Int) error {
+	pMinusOne := big.NewInt(0).Sub(dhPrime, big1)
+	if value.Cmp(big1) <= 0 || value.Cmp(pMinusOne) >= 0 {
+		return &HandshakeError{Check: name, Reason: "must be between 1 and dh_prime-1"}
+	}
+
+	margin := big.NewInt(0).Lsh(big1, dhSafetyMarginBits)
+	if value.Cmp(margin) < 0 || value.Cmp(big.NewInt(0).Sub(dhPrime, margin)) > 0 {
+		return &HandshakeError{Check: name, Reason: "too close to 1 or dh_prime-1"}
+	}
+	return nil
+}
+
+// checkDHParams делает все проверки параметров, которые прислал сервер
+func checkDHParams(g int32, gA, dhPrime *big.Int) error {
+	if err := checkDHPrime(dhPrime); err != nil {
+		return err
+	}
+	if err := checkDHGenerator(g, dhPrime); err != nil {
+		return err
+	}
+	return checkDHValue("g_a", gA, dhPrime)
+}
+
+// newNonceHash считает new_nonce_hash1, 2 или 3 (number) из ответов dh_gen_ok, dh_gen_retry и
+// dh_gen_fail: последние 128 бит SHA1(new_nonce + number + auth_key_aux_hash)
+func newNonceHash(newNonce *serialize.Int256, number byte, authKey []byte) []byte {
+	data := make([]byte, 32+1+8)
+	copy(data, dry.BigIntBytes(newNonce.Int, 256))
+	data[32] = number
+	copy(data[33:], dry.Sha1Byte(authKey)[0:8])
+	return dry.Sha1Byte(data)[4:20]
+}
+
+// checkNewNonceHash сравнивает new_nonce_hash из ответа сервера с ожидаемым
+func checkNewNonceHash(got *serialize.Int128, newNonce *serialize.Int256, number byte, authKey []byte) error {
+	expected := newNonceHash(newNonce, number, authKey)
+	if got == nil || !bytes.Equal(expected, dry.BigIntBytes(got.Int, 128)) {
+		return &HandshakeError{
+			Check:  "new_nonce_hash" + string('0'+number),
+			Reason: "doesn't match the one computed from new_nonce and auth key",
+		}
+	}
+	return nil
+}
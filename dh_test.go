@@ -0,0 +1,146 @@
+package mtproto
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+func knownDHPrime(t *testing.T) *big.Int {
+	t.Helper()
+	for key := range knownDHPrimes {
+		data, err := hex.DecodeString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return big.NewInt(0).SetBytes(data)
+	}
+	t.Fatal("no known primes")
+	return nil
+}
+
+func expectHandshakeError(t *testing.T, err error, check string) {
+	t.Helper()
+	e, ok := err.(*HandshakeError)
+	if !ok {
+		t.Errorf("expected *HandshakeError for %v, got %#v", check, err)
+		return
+	}
+	if e.Check != check {
+		t.Errorf("expected failed check %v, got %v", check, e.Check)
+	}
+}
+
+func TestKnownDHPrimesAreSafe(t *testing.T) {
+	for key := range knownDHPrimes {
+		p, _ := big.NewInt(0).SetString(key, 16)
+		if p.BitLen() != dhPrimeBits || !p.ProbablyPrime(64) || !big.NewInt(0).Rsh(p, 1).ProbablyPrime(64) {
+			t.Errorf("%v... is not a 2048 bit safe prime", key[:16])
+		}
+	}
+}
+
+func TestCheckDHPrime(t *testing.T) {
+	p := knownDHPrime(t)
+	if err := checkDHPrime(p); err != nil {
+		t.Error(err)
+	}
+
+	expectHandshakeError(t, checkDHPrime(big.NewInt(0).Rsh(p, 1)), "dh_prime")
+	expectHandshakeError(t, checkDHPrime(big.NewInt(0).Add(p, big1)), "dh_prime")
+
+	// случайное простое почти наверняка не безопасное
+	notSafe, err := rand.Prime(rand.Reader, dhPrimeBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if big.NewInt(0).Rsh(notSafe, 1).ProbablyPrime(20) {
+		t.Skip("got safe prime by accident")
+	}
+	expectHandshakeError(t, checkDHPrime(notSafe), "dh_prime")
+}
+
+func TestCheckDHGenerator(t *testing.T) {
+	p := knownDHPrime(t)
+	// телеграм присылает g = 3
+	if err := checkDHGenerator(3, p); err != nil {
+		t.Error(err)
+	}
+	if err := checkDHGenerator(4, p); err != nil {
+		t.Error(err)
+	}
+	expectHandshakeError(t, checkDHGenerator(1, p), "g")
+	expectHandshakeError(t, checkDHGenerator(8, p), "g")
+
+	// 23 mod 8 = 7, а 19 mod 8 = 3
+	if err := checkDHGenerator(2, big.NewInt(23)); err != nil {
+		t.Error(err)
+	}
+	expectHandshakeError(t, checkDHGenerator(2, big.NewInt(19)), "g")
+}
+
+func TestCheckDHValue(t *testing.T) {
+	p := knownDHPrime(t)
+	pMinusOne := big.NewInt(0).Sub(p, big1)
+
+	bad := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(2),
+		pMinusOne,
+		p,
+		big.NewInt(0).Lsh(big1, dhSafetyMarginBits-1),
+		big.NewInt(0).Sub(pMinusOne, big1),
+	}
+	for _, v := range bad {
+		expectHandshakeError(t, checkDHValue("g_a", v, p), "g_a")
+	}
+
+	good := []*big.Int{
+		big.NewInt(0).Lsh(big1, dhSafetyMarginBits),
+		big.NewInt(0).Lsh(big1, dhPrimeBits-2),
+	}
+	for _, v := range good {
+		if err := checkDHValue("g_a", v, p); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestMakeGAB(t *testing.T) {
+	p := knownDHPrime(t)
+	a, _ := rand.Int(rand.Reader, p)
+	gA := big.NewInt(0).Exp(big.NewInt(3), a, p)
+
+	gB, gAB, err := makeGAB(3, gA, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkDHValue("g_b", gB, p); err != nil {
+		t.Error(err)
+	}
+	// сервер получит тот же ключ
+	if big.NewInt(0).Exp(gB, a, p).Cmp(gAB) != 0 {
+		t.Error("keys of client and server differ")
+	}
+}
+
+func TestCheckNewNonceHash(t *testing.T) {
+	newNonce := serialize.RandomInt256()
+	authKey := make([]byte, 256)
+	_, _ = rand.Read(authKey)
+
+	for number := byte(1); number <= 3; number++ {
+		hash := &serialize.Int128{Int: big.NewInt(0).SetBytes(newNonceHash(newNonce, number, authKey))}
+		if err := checkNewNonceHash(hash, newNonce, number, authKey); err != nil {
+			t.Error(err)
+		}
+	}
+
+	hash := &serialize.Int128{Int: big.NewInt(0).SetBytes(newNonceHash(newNonce, 1, authKey))}
+	expectHandshakeError(t, checkNewNonceHash(hash, newNonce, 2, authKey), "new_nonce_hash2")
+	expectHandshakeError(t, checkNewNonceHash(nil, newNonce, 1, authKey), "new_nonce_hash1")
+}
@@ -1,11 +1,10 @@
 package mtproto
 
 import (
-	"bytes"
+	"crypto/rsa"
 	"encoding/binary"
-	"encoding/hex"
-	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/xelaj/go-dry"
@@ -15,28 +14,53 @@ import (
 	"github.com/lonesta/mtproto/serialize"
 )
 
+// errNoPublicKey значит, что ни один публичный ключ клиента не подходит к отпечаткам, которые прислал
+// сервер. при переподключении это не изменится
+var errNoPublicKey = errors.New("handshake: no public key for any of server fingerprints")
+
 // https://tlgrm.ru/docs/mtproto/auth_key
 // https://core.telegram.org/mtproto/auth_key
 func (m *MTProto) makeAuthKey() error {
+	authKey, salt, err := m.createAuthKey(0)
+	if err != nil {
+		return err
+	}
+
+	m.SetAuthKey(authKey)
+	m.setServerSalt(salt)
+	m.setEncrypted(true)
+
+	// (all ok)
+	err = m.SaveSession()
+	return errors.Wrap(err, "saving session")
+}
+
+// createAuthKey проходит весь обмен ключами и возвращает новый ключ и первую соль. если expiresIn
+// больше нуля, то создается временный ключ, который сервер забудет через expiresIn секунд (см. pfs.go)
+func (m *MTProto) createAuthKey(expiresIn int32) (authKey []byte, serverSalt int64, err error) {
+	var dc int32
+	if expiresIn > 0 {
+		dc, err = m.tempKeyDC()
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
 	m.serviceModeActivated = true
+	defer func() { m.serviceModeActivated = false }()
+
 	nonceFirst := serialize.RandomInt128()
 	res, err := m.ReqPQ(nonceFirst)
 	if err != nil {
-		return errors.Wrap(err, "requesting first pq")
+		return nil, 0, errors.Wrap(err, "requesting first pq")
 	}
 
-	if nonceFirst.Cmp(res.Nonce.Int) != 0 {
-		return errors.New("handshake: Wrong nonce")
-	}
-	found := false
-	for _, b := range res.Fingerprints {
-		if uint64(b) == binary.LittleEndian.Uint64(keys.RSAFingerprint(m.publicKey)) {
-			found = true
-			break
-		}
+	if res.Nonce == nil || nonceFirst.Cmp(res.Nonce.Int) != 0 {
+		return nil, 0, &HandshakeError{Check: "nonce", Reason: "doesn't match the one sent by client"}
 	}
+	publicKey, keyFingerprint, found := selectPublicKey(m.publicKeys, res.Fingerprints)
 	if !found {
-		return errors.New("handshake: Can't find fingerprint")
+		return nil, 0, errors.Wrapf(errNoPublicKey, "server fingerprints %#x", res.Fingerprints)
 	}
 
 	// (encoding) p_q_inner_data
@@ -45,35 +69,46 @@ func (m *MTProto) makeAuthKey() error {
 	nonceSecond := serialize.RandomInt256()
 	nonceServer := res.ServerNonce
 
-	message := (&serialize.PQInnerData{
-		Pq:          res.Pq,
-		P:           p.Bytes(),
-		Q:           q.Bytes(),
-		Nonce:       nonceFirst,
-		ServerNonce: nonceServer,
-		NewNonce:    nonceSecond,
-	}).Encode()
-
-	hashAndMsg := make([]byte, 255)
-	copy(hashAndMsg, append(dry.Sha1(string(message)), message...))
-
-	encryptedMessage := doRSAencrypt(hashAndMsg, m.publicKey)
+	var message []byte
+	if expiresIn > 0 {
+		message = (&serialize.PQInnerDataTempDC{
+			Pq:          res.Pq,
+			P:           p.Bytes(),
+			Q:           q.Bytes(),
+			Nonce:       nonceFirst,
+			ServerNonce: nonceServer,
+			NewNonce:    nonceSecond,
+			Dc:          dc,
+			ExpiresIn:   expiresIn,
+		}).Encode()
+	} else {
+		message = (&serialize.PQInnerData{
+			Pq:          res.Pq,
+			P:           p.Bytes(),
+			Q:           q.Bytes(),
+			Nonce:       nonceFirst,
+			ServerNonce: nonceServer,
+			NewNonce:    nonceSecond,
+		}).Encode()
+	}
+
+	encryptedMessage, err := rsaPadEncrypt(message, publicKey)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "encrypting p_q_inner_data")
+	}
 
-	keyFingerprint := int64(binary.LittleEndian.Uint64(keys.RSAFingerprint(m.publicKey)))
 	dhResponse, err := m.ReqDHParams(nonceFirst, nonceServer, p.Bytes(), q.Bytes(), keyFingerprint, encryptedMessage)
 	if err != nil {
-		return errors.Wrap(err, "sending ReqDHParams")
+		return nil, 0, errors.Wrap(err, "sending ReqDHParams")
 	}
 	dhParams, ok := dhResponse.(*serialize.ServerDHParamsOk)
 	if !ok {
-		return errors.New("handshake: Need ServerDHParamsOk")
+		return nil, 0, errors.New("handshake: Need ServerDHParamsOk")
 	}
 
-	if nonceFirst.Cmp(dhParams.Nonce.Int) != 0 {
-		return errors.New("handshake: Wrong nonce")
-	}
-	if nonceServer.Cmp(dhParams.ServerNonce.Int) != 0 {
-		return errors.New("handshake: Wrong server_nonce")
+	err = checkNonces(nonceFirst, nonceServer, dhParams.Nonce, dhParams.ServerNonce)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// проверку по хешу, удаление рандомных байт происходит в этой функции
@@ -83,66 +118,113 @@ func (m *MTProto) makeAuthKey() error {
 
 	dhi, ok := data.(*serialize.ServerDHInnerData)
 	if !ok {
-		return errors.New("Handshake: Need server_DH_inner_data")
-	}
-	if nonceFirst.Cmp(dhi.Nonce.Int) != 0 {
-		return errors.New("Handshake: Wrong nonce")
+		return nil, 0, errors.New("Handshake: Need server_DH_inner_data")
 	}
-	if nonceServer.Cmp(dhi.ServerNonce.Int) != 0 {
-		return errors.New("Handshake: Wrong server_nonce")
+	err = checkNonces(nonceFirst, nonceServer, dhi.Nonce, dhi.ServerNonce)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// вот это видимо как раз и есть часть диффи хеллмана, поэтому просто оставим как есть надеюсь сработает
-	_, g_b, g_ab := makeGAB(dhi.G, big.NewInt(0).SetBytes(dhi.GA), big.NewInt(0).SetBytes(dhi.DhPrime))
+	// msg_id дальше будут считаться по часам сервера
+	m.syncTime(time.Unix(int64(dhi.ServerTime), 0))
 
-	authKey := g_ab.Bytes()
-	if authKey[0] == 0 {
-		authKey = authKey[1:]
+	dhPrime := big.NewInt(0).SetBytes(dhi.DhPrime)
+	gA := big.NewInt(0).SetBytes(dhi.GA)
+	err = checkDHParams(dhi.G, gA, dhPrime)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	m.SetAuthKey(authKey)
-
-	// что это я пока не знаю, видимо какой то очень специфичный способ сгенерить ключи
-	t4 := make([]byte, 32+1+8)
-	copy(t4[0:], nonceSecond.Bytes())
-	t4[32] = 1
-	copy(t4[33:], dry.Sha1Byte(m.GetAuthKey())[0:8])
-	nonceHash1 := dry.Sha1Byte(t4)[4:20]
-	salt := make([]byte, serialize.LongLen)
-	copy(salt, nonceSecond.Bytes()[:8])
-	xor(salt, nonceServer.Bytes()[:8])
-	m.serverSalt = int64(binary.LittleEndian.Uint64(salt))
+	salt := dry.BigIntBytes(nonceSecond.Int, 256)[:serialize.LongLen]
+	xor(salt, dry.BigIntBytes(nonceServer.Int, 128)[:serialize.LongLen])
+	serverSalt = int64(binary.LittleEndian.Uint64(salt))
 
-	// (encoding) client_DH_inner_data
-	clientDHData := &serialize.ClientDHInnerData{nonceFirst, nonceServer, 0, g_b.Bytes()}
+	// на dh_gen_retry нужно выбрать другое b и отправить set_client_DH_params заново, указав
+	// auth_key_aux_hash ключа из прошлой попытки
+	var retryID int64
+	for attempt := 0; attempt < maxDHGenRetries; attempt++ {
+		gB, gAB, err := makeGAB(dhi.G, gA, dhPrime)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "generating g_b")
+		}
+		authKey = dry.BigIntBytes(gAB, dhPrimeBits)
+
+		// (encoding) client_DH_inner_data
+		clientDHData := &serialize.ClientDHInnerData{
+			Nonce:       nonceFirst,
+			ServerNonce: nonceServer,
+			Retry:       retryID,
+			GB:          gB.Bytes(),
+		}
+		encryptedMessage = ige.EncryptMessageWithTempKeys(clientDHData.Encode(), nonceSecond.Int, nonceServer.Int)
 
-	encryptedMessage = ige.EncryptMessageWithTempKeys(clientDHData.Encode(), nonceSecond.Int, nonceServer.Int)
+		dhGenStatus, err := m.SetClientDHParams(nonceFirst, nonceServer, encryptedMessage)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "sending clientDHParams")
+		}
 
-	dhGenStatus, err := m.SetClientDHParams(nonceFirst, nonceServer, encryptedMessage)
-	if err != nil {
-		return errors.Wrap(err, "sending clientDHParams")
+		switch dhg := dhGenStatus.(type) {
+		case *serialize.DHGenOk:
+			err = checkNonces(nonceFirst, nonceServer, dhg.Nonce, dhg.ServerNonce)
+			if err != nil {
+				return nil, 0, err
+			}
+			err = checkNewNonceHash(dhg.NewNonceHash1, nonceSecond, 1, authKey)
+			if err != nil {
+				return nil, 0, err
+			}
+			return authKey, serverSalt, nil
+
+		case *serialize.DHGenRetry:
+			err = checkNonces(nonceFirst, nonceServer, dhg.Nonce, dhg.ServerNonce)
+			if err != nil {
+				return nil, 0, err
+			}
+			err = checkNewNonceHash(dhg.NewNonceHash2, nonceSecond, 2, authKey)
+			if err != nil {
+				return nil, 0, err
+			}
+			retryID = int64(binary.LittleEndian.Uint64(dry.Sha1Byte(authKey)[:8]))
+
+		case *serialize.DHGenFail:
+			err = checkNonces(nonceFirst, nonceServer, dhg.Nonce, dhg.ServerNonce)
+			if err != nil {
+				return nil, 0, err
+			}
+			err = checkNewNonceHash(dhg.NewNonceHash3, nonceSecond, 3, authKey)
+			if err != nil {
+				return nil, 0, err
+			}
+			return nil, 0, errors.New("handshake: server failed to create auth key (dh_gen_fail)")
+
+		default:
+			return nil, 0, errors.New("Handshake: Need DHGenOk")
+		}
 	}
 
-	dhg, ok := dhGenStatus.(*serialize.DHGenOk)
-	if !ok {
-		return errors.New("Handshake: Need DHGenOk")
-	}
-	if nonceFirst.Cmp(dhg.Nonce.Int) != 0 {
-		return fmt.Errorf("Handshake: Wrong nonce: %v, %v", nonceFirst, dhg.Nonce)
-	}
-	if nonceServer.Cmp(dhg.ServerNonce.Int) != 0 {
-		return fmt.Errorf("Handshake: Wrong server_nonce: %v, %v", nonceServer, dhg.ServerNonce)
+	return nil, 0, errors.Errorf("handshake: got dh_gen_retry %v times in a row", maxDHGenRetries)
+}
+
+// checkNonces проверяет, что в ответе сервера те же nonce и server_nonce, что были в начале обмена
+func checkNonces(nonce, serverNonce, gotNonce, gotServerNonce *serialize.Int128) error {
+	if gotNonce == nil || nonce.Cmp(gotNonce.Int) != 0 {
+		return &HandshakeError{Check: "nonce", Reason: "doesn't match the one sent by client"}
 	}
-	if !bytes.Equal(nonceHash1, dhg.NewNonceHash1.Bytes()) {
-		return fmt.Errorf(
-			"handshake: Wrong new_nonce_hash1: %v, %v",
-			hex.EncodeToString(nonceHash1),
-			hex.EncodeToString(dhg.NewNonceHash1.Bytes()),
-		)
+	if gotServerNonce == nil || serverNonce.Cmp(gotServerNonce.Int) != 0 {
+		return &HandshakeError{Check: "server_nonce", Reason: "doesn't match the one sent by server"}
 	}
-	m.serviceModeActivated = false
+	return nil
+}
 
-	// (all ok)
-	err = m.SaveSession()
-	return errors.Wrap(err, "saving session")
+// selectPublicKey находит среди известных ключей тот, отпечаток которого прислал сервер
+func selectPublicKey(publicKeys []*rsa.PublicKey, fingerprints []int64) (*rsa.PublicKey, int64, bool) {
+	for _, key := range publicKeys {
+		fingerprint := int64(binary.LittleEndian.Uint64(keys.RSAFingerprint(key)))
+		for _, f := range fingerprints {
+			if f == fingerprint {
+				return key, fingerprint, true
+			}
+		}
+	}
+	return nil, 0, false
 }
@@ -0,0 +1,24 @@
+package mtproto
+
+import (
+	"crypto/rsa"
+	"encoding/binary"
+	"testing"
+
+	"github.com/lonesta/mtproto/keys"
+)
+
+func TestSelectPublicKey(t *testing.T) {
+	production, test := keys.Production()[0], keys.Test()[0]
+	testFingerprint := int64(binary.LittleEndian.Uint64(keys.RSAFingerprint(test)))
+
+	key, fingerprint, found := selectPublicKey([]*rsa.PublicKey{production, test}, []int64{1, testFingerprint})
+	if !found || key != test || fingerprint != testFingerprint {
+		t.Errorf("expected test key to be selected, got %v %#x", found, fingerprint)
+	}
+
+	_, _, found = selectPublicKey([]*rsa.PublicKey{production}, []int64{1, testFingerprint})
+	if found {
+		t.Error("no key must be found for unknown fingerprints")
+	}
+}
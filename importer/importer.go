@@ -0,0 +1,54 @@
+// Package importer переводит сессии других mtproto клиентов (Telethon, Pyrogram, Telegram Desktop) в
+// mtproto.Session, что бы не авторизовываться в уже залогиненных аккаунтах заново
+package importer
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto"
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+const authKeyLen = 256
+
+// адреса тестовых датацентров, для сессий с test_mode
+var testDCList = map[int]string{
+	1: "149.154.175.10:443",
+	2: "149.154.167.40:443",
+	3: "149.154.175.117:443",
+}
+
+// newSession собирает сессию из ключа. соль у импортированных сессий неизвестна, поэтому она нулевая:
+// сервер ответит bad_server_salt и выдаст правильную при первом же запросе
+func newSession(dc int, hostname string, key []byte, userID int64) (*mtproto.Session, error) {
+	if len(key) != authKeyLen {
+		return nil, errors.Errorf("auth key must be %v bytes, got %v", authKeyLen, len(key))
+	}
+	if hostname == "" {
+		return nil, errors.Errorf("unknown address of dc %v", dc)
+	}
+
+	return &mtproto.Session{
+		Key:      key,
+		Hash:     utils.AuthKeyHash(key),
+		Salt:     make([]byte, serialize.LongLen),
+		Hostname: hostname,
+		DC:       dc,
+		UserID:   userID,
+	}, nil
+}
+
+func dcAddress(dc int, testMode bool) string {
+	if testMode {
+		return testDCList[dc]
+	}
+	return mtproto.DefaultDCAddress(dc)
+}
+
+func joinHostPort(host string, port int64) string {
+	return net.JoinHostPort(host, strconv.FormatInt(port, 10))
+}
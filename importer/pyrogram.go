@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto"
+)
+
+// строки сессий пирограма это urlsafe base64 от структуры python struct, в зависимости от версии:
+//
+//	">B?256sI?"  dc_id, test_mode, auth_key, user_id (32 бита), is_bot
+//	">B?256sQ?"  то же самое, но user_id 64 бита
+//	">BI?256sQ?" dc_id, api_id, test_mode, auth_key, user_id, is_bot
+const (
+	pyrogramOldLen   = 1 + 1 + authKeyLen + 4 + 1
+	pyrogramOld64Len = 1 + 1 + authKeyLen + 8 + 1
+	pyrogramLen      = 1 + 4 + 1 + authKeyLen + 8 + 1
+)
+
+// Pyrogram разбирает строку сессии, которую возвращает Client.export_session_string()
+func Pyrogram(sessionString string) (*mtproto.Session, error) {
+	str := strings.TrimSpace(sessionString)
+	if pad := len(str) % 4; pad != 0 {
+		str += strings.Repeat("=", 4-pad)
+	}
+	data, err := base64.URLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding base64")
+	}
+
+	var (
+		dc       int
+		testMode bool
+		key      []byte
+		userID   int64
+	)
+	switch len(data) {
+	case pyrogramOldLen:
+		dc, testMode = int(data[0]), data[1] != 0
+		key = data[2 : 2+authKeyLen]
+		userID = int64(int32(binary.BigEndian.Uint32(data[2+authKeyLen:])))
+	case pyrogramOld64Len:
+		dc, testMode = int(data[0]), data[1] != 0
+		key = data[2 : 2+authKeyLen]
+		userID = int64(binary.BigEndian.Uint64(data[2+authKeyLen:]))
+	case pyrogramLen:
+		dc, testMode = int(data[0]), data[5] != 0
+		key = data[6 : 6+authKeyLen]
+		userID = int64(binary.BigEndian.Uint64(data[6+authKeyLen:]))
+	default:
+		return nil, errors.Errorf("unknown pyrogram session string format (%v bytes)", len(data))
+	}
+
+	return newSession(dc, dcAddress(dc, testMode), append([]byte(nil), key...), userID)
+}
@@ -0,0 +1,60 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestPyrogram(t *testing.T) {
+	key := testKey()
+
+	// >BI?256sQ?
+	v2 := new(bytes.Buffer)
+	v2.WriteByte(4)
+	_ = binary.Write(v2, binary.BigEndian, uint32(12345))
+	v2.WriteByte(0)
+	v2.Write(key)
+	_ = binary.Write(v2, binary.BigEndian, uint64(987654321))
+	v2.WriteByte(1)
+
+	// >B?256sI?
+	old := new(bytes.Buffer)
+	old.WriteByte(2)
+	old.WriteByte(1)
+	old.Write(key)
+	_ = binary.Write(old, binary.BigEndian, uint32(1000))
+	old.WriteByte(0)
+
+	cases := []struct {
+		data     []byte
+		dc       int
+		hostname string
+		userID   int64
+	}{
+		{v2.Bytes(), 4, "149.154.167.91:443", 987654321},
+		{old.Bytes(), 2, "149.154.167.40:443", 1000},
+	}
+
+	for _, tcase := range cases {
+		// пирограм обрезает паддинг
+		str := strings.TrimRight(base64.URLEncoding.EncodeToString(tcase.data), "=")
+		session, err := Pyrogram(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if session.DC != tcase.dc || session.Hostname != tcase.hostname || session.UserID != tcase.userID {
+			t.Errorf("got %v %v %v, want %v %v %v", session.DC, session.Hostname, session.UserID,
+				tcase.dc, tcase.hostname, tcase.userID)
+		}
+		if !bytes.Equal(session.Key, key) {
+			t.Error("auth key mismatch")
+		}
+	}
+
+	if _, err := Pyrogram("AAAA"); err == nil {
+		t.Error("expected error for short string")
+	}
+}
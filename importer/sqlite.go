@@ -0,0 +1,383 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// минимальный читатель файлов sqlite3: умеет только найти таблицу по имени и пройтись по ее строкам.
+// тащить ради одной таблицы телетона cgo драйвер не хочется, а формат файла простой
+// https://www.sqlite.org/fileformat2.html
+
+const (
+	sqliteHeaderLen = 100
+	sqliteMagic     = "SQLite format 3\x00"
+
+	sqlitePageInteriorTable = 0x05
+	sqlitePageLeafTable     = 0x0d
+
+	// меньше этого usable размер страницы по формату быть не может
+	sqliteMinUsable = 480
+)
+
+type sqliteDB struct {
+	data     []byte
+	pageSize int
+	usable   int // размер страницы без зарезервированного в конце места
+}
+
+// sqliteRow это значения колонок по именам. значения бывают nil, int64, float64, string и []byte
+type sqliteRow map[string]interface{}
+
+func openSQLite(data []byte) (*sqliteDB, error) {
+	if len(data) < sqliteHeaderLen || !bytes.HasPrefix(data, []byte(sqliteMagic)) {
+		return nil, errors.New("not a sqlite3 database")
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, errors.Errorf("invalid page size %v", pageSize)
+	}
+
+	usable := pageSize - int(data[20])
+	if usable < sqliteMinUsable {
+		return nil, errors.Errorf("invalid reserved space %v for page size %v", data[20], pageSize)
+	}
+
+	return &sqliteDB{
+		data:     data,
+		pageSize: pageSize,
+		usable:   usable,
+	}, nil
+}
+
+func (db *sqliteDB) page(n int) ([]byte, error) {
+	start := (n - 1) * db.pageSize
+	if n < 1 || start+db.pageSize > len(db.data) {
+		return nil, errors.Errorf("page %v is out of file", n)
+	}
+	return db.data[start : start+db.pageSize], nil
+}
+
+// table возвращает все строки таблицы name
+func (db *sqliteDB) table(name string) ([]sqliteRow, error) {
+	var root int
+	var sql string
+	err := db.walk(1, func(_ int64, values []interface{}) error {
+		// sqlite_master: type, name, tbl_name, rootpage, sql
+		if len(values) < 5 || values[0] != "table" || values[1] != name {
+			return nil
+		}
+		page, _ := values[3].(int64)
+		root = int(page)
+		sql, _ = values[4].(string)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "reading schema")
+	}
+	if root == 0 {
+		return nil, errors.Errorf("table %v not found", name)
+	}
+
+	columns, rowidColumn := parseColumns(sql)
+	rows := make([]sqliteRow, 0)
+	err = db.walk(root, func(rowid int64, values []interface{}) error {
+		row := make(sqliteRow)
+		for i, column := range columns {
+			if i < len(values) {
+				row[column] = values[i]
+			}
+		}
+		// колонка "integer primary key" хранится не в записи, а как rowid
+		if rowidColumn != "" {
+			row[rowidColumn] = rowid
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading table %v", name)
+	}
+
+	return rows, nil
+}
+
+// walk обходит b-дерево таблицы, начиная со страницы root
+func (db *sqliteDB) walk(root int, f func(rowid int64, values []interface{}) error) error {
+	return db.walkPage(root, f, 0)
+}
+
+func (db *sqliteDB) walkPage(n int, f func(rowid int64, values []interface{}) error, depth int) error {
+	if depth > 64 {
+		return errors.New("b-tree is too deep, file is probably broken")
+	}
+
+	page, err := db.page(n)
+	if err != nil {
+		return err
+	}
+	header := page
+	if n == 1 {
+		header = page[sqliteHeaderLen:]
+	}
+
+	headerLen := 8
+	if header[0] == sqlitePageInteriorTable {
+		headerLen = 12
+	}
+	cellCount := int(binary.BigEndian.Uint16(header[3:5]))
+	if headerLen+cellCount*2 > len(header) {
+		return errors.Errorf("page %v has more cells than fits in it", n)
+	}
+	pointers := header[headerLen:]
+
+	switch header[0] {
+	case sqlitePageInteriorTable:
+		for i := 0; i < cellCount; i++ {
+			cell := int(binary.BigEndian.Uint16(pointers[i*2:]))
+			if cell+4 > len(page) {
+				return errors.New("cell is out of page")
+			}
+			child := int(binary.BigEndian.Uint32(page[cell:]))
+			if err := db.walkPage(child, f, depth+1); err != nil {
+				return err
+			}
+		}
+		return db.walkPage(int(binary.BigEndian.Uint32(header[8:12])), f, depth+1)
+
+	case sqlitePageLeafTable:
+		for i := 0; i < cellCount; i++ {
+			cell := int(binary.BigEndian.Uint16(pointers[i*2:]))
+			rowid, payload, err := db.readLeafCell(page, cell)
+			if err != nil {
+				return err
+			}
+			values, err := parseRecord(payload)
+			if err != nil {
+				return errors.Wrapf(err, "parsing row %v", rowid)
+			}
+			if err := f(rowid, values); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return errors.Errorf("unexpected page type %#x", header[0])
+	}
+}
+
+func (db *sqliteDB) readLeafCell(page []byte, offset int) (rowid int64, payload []byte, err error) {
+	if offset >= len(page) {
+		return 0, nil, errors.New("cell is out of page")
+	}
+	size, n := sqliteVarint(page[offset:])
+	if n == 0 {
+		return 0, nil, errors.New("cell is out of page")
+	}
+	offset += n
+	rowidU, n := sqliteVarint(page[offset:])
+	if n == 0 {
+		return 0, nil, errors.New("cell is out of page")
+	}
+	offset += n
+
+	// запись не может быть больше всего файла
+	if size > uint64(len(db.data)) {
+		return 0, nil, errors.Errorf("invalid cell size %v", size)
+	}
+	total := int(size)
+	local := db.localPayloadSize(total)
+	if offset+local > len(page) {
+		return 0, nil, errors.New("cell is out of page")
+	}
+	payload = append(make([]byte, 0, total), page[offset:offset+local]...)
+	if local == total {
+		return int64(rowidU), payload, nil
+	}
+
+	// то, что не влезло, лежит в цепочке overflow страниц
+	if offset+local+4 > len(page) {
+		return 0, nil, errors.New("cell is out of page")
+	}
+	next := int(binary.BigEndian.Uint32(page[offset+local:]))
+	// в цепочке не может быть больше страниц, чем в файле, иначе она зациклена
+	for pages := 0; len(payload) < total; pages++ {
+		if pages*db.pageSize >= len(db.data) {
+			return 0, nil, errors.New("overflow chain is too long, file is probably broken")
+		}
+		overflow, err := db.page(next)
+		if err != nil {
+			return 0, nil, errors.Wrap(err, "reading overflow page")
+		}
+		next = int(binary.BigEndian.Uint32(overflow))
+		chunk := overflow[4:db.usable]
+		if rest := total - len(payload); len(chunk) > rest {
+			chunk = chunk[:rest]
+		}
+		payload = append(payload, chunk...)
+	}
+
+	return int64(rowidU), payload, nil
+}
+
+// localPayloadSize считает, сколько байт записи лежит прямо в листе таблицы
+func (db *sqliteDB) localPayloadSize(total int) int {
+	maxLocal := db.usable - 35
+	if total <= maxLocal {
+		return total
+	}
+	minLocal := (db.usable-12)*32/255 - 23
+	k := minLocal + (total-minLocal)%(db.usable-4)
+	if k <= maxLocal {
+		return k
+	}
+	return minLocal
+}
+
+func parseRecord(payload []byte) ([]interface{}, error) {
+	headerSize, n := sqliteVarint(payload)
+	if headerSize > uint64(len(payload)) || n == 0 {
+		return nil, errors.New("invalid record header")
+	}
+
+	types := make([]uint64, 0)
+	for pos := n; pos < int(headerSize); {
+		typ, n := sqliteVarint(payload[pos:])
+		if n == 0 {
+			return nil, errors.New("invalid record header")
+		}
+		types = append(types, typ)
+		pos += n
+	}
+
+	values := make([]interface{}, len(types))
+	body := payload[headerSize:]
+	for i, typ := range types {
+		var size int
+		switch {
+		case typ == 0, typ == 8, typ == 9:
+			size = 0
+		case typ >= 1 && typ <= 4:
+			size = int(typ)
+		case typ == 5:
+			size = 6
+		case typ == 6, typ == 7:
+			size = 8
+		case typ >= 12:
+			if (typ-12)/2 > uint64(len(body)) {
+				return nil, errors.New("record is shorter than its header")
+			}
+			size = int(typ-12) / 2
+		default:
+			return nil, errors.Errorf("unknown serial type %v", typ)
+		}
+		if size > len(body) {
+			return nil, errors.New("record is shorter than its header")
+		}
+		value := body[:size]
+		body = body[size:]
+
+		switch {
+		case typ == 0:
+			values[i] = nil
+		case typ == 8:
+			values[i] = int64(0)
+		case typ == 9:
+			values[i] = int64(1)
+		case typ <= 6:
+			// знаковое big endian число произвольной длины
+			var v int64
+			if value[0]&0x80 != 0 {
+				v = -1
+			}
+			for _, b := range value {
+				v = v<<8 | int64(b)
+			}
+			values[i] = v
+		case typ == 7:
+			values[i] = math.Float64frombits(binary.BigEndian.Uint64(value))
+		case typ%2 == 0:
+			values[i] = append([]byte(nil), value...)
+		default:
+			values[i] = string(value)
+		}
+	}
+
+	return values, nil
+}
+
+// sqliteVarint читает varint в формате sqlite (big endian, до 9 байт). возвращает 0 прочитанных байт,
+// если данных не хватило
+func sqliteVarint(data []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(data) {
+			return 0, 0
+		}
+		if i == 8 {
+			return v<<8 | uint64(data[i]), 9
+		}
+		v = v<<7 | uint64(data[i]&0x7f)
+		if data[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, 9
+}
+
+// parseColumns достает имена колонок из CREATE TABLE. разбор очень наивный, но для простых схем
+// (а телетон других не создает) его хватает
+func parseColumns(sql string) (columns []string, rowidColumn string) {
+	start, end := strings.Index(sql, "("), strings.LastIndex(sql, ")")
+	if start < 0 || end < start {
+		return nil, ""
+	}
+
+	depth := 0
+	parts := make([]string, 0)
+	last := start + 1
+	for i := start + 1; i < end; i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, sql[last:i])
+				last = i + 1
+			}
+		}
+	}
+	parts = append(parts, sql[last:end])
+
+	for _, part := range parts {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToLower(fields[0]) {
+		case "primary", "unique", "check", "foreign", "constraint":
+			// ограничения таблицы, а не колонки
+			continue
+		}
+
+		name := strings.Trim(fields[0], "\"`[]")
+		columns = append(columns, name)
+		definition := strings.ToLower(strings.Join(fields[1:], " "))
+		if strings.HasPrefix(definition, "integer") && strings.Contains(definition, "primary key") {
+			rowidColumn = name
+		}
+	}
+
+	return columns, rowidColumn
+}
@@ -0,0 +1,340 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/lonesta/mtproto"
+	ige "github.com/lonesta/mtproto/internal/aes_ige"
+)
+
+// telegram desktop хранит все в директории tdata. нам нужны два вида файлов:
+//
+//	key_datas (key_data для старых версий) - локальный ключ, зашифрованный паролем (passcode), и
+//	                                         список аккаунтов
+//	<md5 от "data" или "data#N">s           - для каждого аккаунта, данные mtproto авторизации
+//
+// все файлы в формате "TDF$" | версия (int32 LE) | данные | md5, данные сериализованы через
+// QDataStream (big endian)
+
+const (
+	tdfMagic = "TDF$"
+
+	tdesktopKeyFile = "key_datas"
+
+	tdesktopLocalKeyLen        = 256
+	tdesktopStrongIterations   = 100000
+	tdesktopPasscodeIterations = 1 // если пароля нет, то ключ все равно считается, но с одной итерацией
+
+	// dbiMtpAuthorization, единственный блок, который нас интересует в файле аккаунта
+	tdesktopBlockMtpAuthorization = 0x4b
+
+	tdesktopMaxAccounts = 3
+)
+
+// TDesktopAccount это один аккаунт из tdata
+type TDesktopAccount struct {
+	// Session это сессия основного датацентра аккаунта
+	Session *mtproto.Session
+	// Other это ключи для остальных датацентров, если клиент успел их получить
+	Other []*mtproto.Session
+}
+
+// TDesktop читает все аккаунты из директории tdata. passcode это локальный пароль telegram desktop,
+// пустая строка, если он не установлен
+func TDesktop(tdataDir, passcode string) ([]*TDesktopAccount, error) {
+	keyData, err := readTDF(filepath.Join(tdataDir, tdesktopKeyFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading "+tdesktopKeyFile)
+	}
+
+	r := qtReader{data: keyData}
+	salt := r.byteArray()
+	keyEncrypted := r.byteArray()
+	infoEncrypted := r.byteArray()
+	if r.err != nil {
+		return nil, errors.Wrap(r.err, "parsing "+tdesktopKeyFile)
+	}
+
+	passcodeKey := tdesktopPasscodeKey(salt, passcode)
+	keyInner, err := tdesktopDecrypt(keyEncrypted, passcodeKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting local key (wrong passcode?)")
+	}
+	if len(keyInner) < tdesktopLocalKeyLen {
+		return nil, errors.New("local key is too short")
+	}
+	localKey := keyInner[:tdesktopLocalKeyLen]
+
+	info, err := tdesktopDecrypt(infoEncrypted, localKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting accounts list")
+	}
+	r = qtReader{data: info}
+	count := r.int32()
+	if r.err != nil {
+		return nil, errors.Wrap(r.err, "parsing accounts list")
+	}
+	if count <= 0 || count > tdesktopMaxAccounts {
+		return nil, errors.Errorf("invalid accounts count %v", count)
+	}
+
+	res := make([]*TDesktopAccount, 0, count)
+	for i := int32(0); i < count; i++ {
+		index := r.int32()
+		if r.err != nil {
+			return nil, errors.Wrap(r.err, "parsing accounts list")
+		}
+
+		account, err := readTDesktopAccount(tdataDir, int(index), localKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "account %v", index)
+		}
+		res = append(res, account)
+	}
+
+	return res, nil
+}
+
+func readTDesktopAccount(tdataDir string, index int, localKey []byte) (*TDesktopAccount, error) {
+	name := "data"
+	if index > 0 {
+		name += "#" + strconv.Itoa(index+1)
+	}
+
+	data, err := readTDF(filepath.Join(tdataDir, tdesktopFilePart(name)))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading account file")
+	}
+	r := qtReader{data: data}
+	encrypted := r.byteArray()
+	if r.err != nil {
+		return nil, errors.Wrap(r.err, "parsing account file")
+	}
+	decrypted, err := tdesktopDecrypt(encrypted, localKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting account file")
+	}
+
+	r = qtReader{data: decrypted}
+	blockID := r.uint32()
+	auth := r.byteArray()
+	if r.err != nil {
+		return nil, errors.Wrap(r.err, "parsing account file")
+	}
+	if blockID != tdesktopBlockMtpAuthorization {
+		return nil, errors.Errorf("unexpected block %#x", blockID)
+	}
+
+	return parseTDesktopAuthorization(auth)
+}
+
+func parseTDesktopAuthorization(data []byte) (*TDesktopAccount, error) {
+	r := qtReader{data: data}
+
+	userID, mainDC := int64(r.int32()), int(r.int32())
+	if userID == -1 && mainDC == -1 {
+		// новый формат, где id пользователя 64 бита
+		userID = int64(r.uint64())
+		mainDC = int(r.int32())
+	}
+
+	count := r.int32()
+	if r.err != nil {
+		return nil, errors.Wrap(r.err, "parsing authorization")
+	}
+	if count < 0 || int(count) > len(data)/authKeyLen {
+		return nil, errors.Errorf("invalid keys count %v", count)
+	}
+
+	res := new(TDesktopAccount)
+	for i := int32(0); i < count; i++ {
+		dc := int(r.int32())
+		key := r.raw(authKeyLen)
+		if r.err != nil {
+			return nil, errors.Wrap(r.err, "parsing authorization")
+		}
+
+		var sessionUserID int64
+		if dc == mainDC {
+			sessionUserID = userID
+		}
+		session, err := newSession(dc, mtproto.DefaultDCAddress(dc), key, sessionUserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if dc == mainDC {
+			res.Session = session
+		} else {
+			res.Other = append(res.Other, session)
+		}
+	}
+	// дальше идет список ключей, которые клиент собирается удалить, они нам не нужны
+
+	if res.Session == nil {
+		return nil, errors.Errorf("no auth key for main dc %v", mainDC)
+	}
+	return res, nil
+}
+
+// readTDF читает файл в формате TDF$. telegram desktop пишет файл с суффиксом "s" и держит копии с
+// суффиксами "1" и "0", берем первый, который прочитается
+func readTDF(path string) ([]byte, error) {
+	var lastErr error
+	for _, suffix := range []string{"s", "1", "0"} {
+		data, err := ioutil.ReadFile(path + suffix)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				lastErr = err
+			}
+			continue
+		}
+
+		res, err := parseTDF(data)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return res, nil
+	}
+
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, errors.New(path + ": file not found")
+}
+
+func parseTDF(data []byte) ([]byte, error) {
+	const footerLen = md5.Size
+	headerLen := len(tdfMagic) + 4
+	if len(data) < headerLen+footerLen || !bytes.HasPrefix(data, []byte(tdfMagic)) {
+		return nil, errors.New("not a tdesktop file")
+	}
+
+	version := data[len(tdfMagic):headerLen]
+	body := data[headerLen : len(data)-footerLen]
+
+	hash := md5.New()
+	hash.Write(body)
+	_ = binary.Write(hash, binary.LittleEndian, int32(len(body)))
+	hash.Write(version)
+	hash.Write([]byte(tdfMagic))
+	if !bytes.Equal(hash.Sum(nil), data[len(data)-footerLen:]) {
+		return nil, errors.New("checksum mismatch")
+	}
+
+	return body, nil
+}
+
+func tdesktopPasscodeKey(salt []byte, passcode string) []byte {
+	iterations := tdesktopStrongIterations
+	if passcode == "" {
+		iterations = tdesktopPasscodeIterations
+	}
+
+	hash := sha512.New()
+	hash.Write(salt)
+	hash.Write([]byte(passcode))
+	hash.Write(salt)
+
+	return pbkdf2.Key(hash.Sum(nil), salt, iterations, tdesktopLocalKeyLen, sha512.New)
+}
+
+// tdesktopDecrypt расшифровывает блок: первые 16 байт это msg_key, остальное зашифровано aes ige.
+// внутри первые 4 байта это длина полезных данных вместе с ними самими
+func tdesktopDecrypt(encrypted, key []byte) ([]byte, error) {
+	if len(encrypted) <= 16 || len(encrypted)%16 != 0 {
+		return nil, errors.Errorf("invalid encrypted data size %v", len(encrypted))
+	}
+
+	msgKey := encrypted[:16]
+	decrypted, err := ige.DecryptLocal(encrypted[16:], key, msgKey)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha1.Sum(decrypted)
+	if !bytes.Equal(checksum[:16], msgKey) {
+		return nil, errors.New("bad checksum")
+	}
+
+	dataLen := binary.LittleEndian.Uint32(decrypted)
+	if dataLen < 4 || int(dataLen) > len(decrypted) {
+		return nil, errors.Errorf("invalid data length %v", dataLen)
+	}
+
+	return decrypted[4:dataLen], nil
+}
+
+// tdesktopFilePart это то, как telegram desktop превращает имя в имя файла: первые 8 байт md5,
+// каждый байт как два hex символа, младший полубайт первым
+func tdesktopFilePart(name string) string {
+	const hex = "0123456789ABCDEF"
+
+	sum := md5.Sum([]byte(name))
+	res := make([]byte, 0, 16)
+	for _, b := range sum[:8] {
+		res = append(res, hex[b&0x0f], hex[b>>4])
+	}
+	return string(res)
+}
+
+// qtReader читает то, что записал QDataStream. первая ошибка запоминается, дальше все методы
+// возвращают нули
+type qtReader struct {
+	data []byte
+	err  error
+}
+
+func (r *qtReader) raw(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data) {
+		r.err = errors.New("unexpected end of data")
+		return nil
+	}
+	res := append([]byte(nil), r.data[:n]...)
+	r.data = r.data[n:]
+	return res
+}
+
+func (r *qtReader) uint32() uint32 {
+	b := r.raw(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *qtReader) int32() int32 {
+	return int32(r.uint32())
+}
+
+func (r *qtReader) uint64() uint64 {
+	b := r.raw(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// byteArray читает QByteArray: длина uint32 и данные. длина 0xffffffff означает null
+func (r *qtReader) byteArray() []byte {
+	size := r.uint32()
+	if r.err != nil || size == 0xffffffff {
+		return nil
+	}
+	return r.raw(int(size))
+}
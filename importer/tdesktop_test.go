@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ige "github.com/lonesta/mtproto/internal/aes_ige"
+)
+
+func tdesktopEncrypt(t *testing.T, data, key []byte) []byte {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(data)+4))
+	buf.Write(data)
+	if pad := buf.Len() % 16; pad != 0 {
+		buf.Write(make([]byte, 16-pad))
+	}
+
+	checksum := sha1.Sum(buf.Bytes())
+	msgKey := checksum[:16]
+	encrypted, err := ige.EncryptLocal(buf.Bytes(), key, msgKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(append([]byte(nil), msgKey...), encrypted...)
+}
+
+func qtByteArray(buf *bytes.Buffer, data []byte) {
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+}
+
+func writeTDF(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	version := []byte{0x10, 0x27, 0, 0}
+	hash := md5.New()
+	hash.Write(data)
+	_ = binary.Write(hash, binary.LittleEndian, int32(len(data)))
+	hash.Write(version)
+	hash.Write([]byte(tdfMagic))
+
+	file := append([]byte(tdfMagic), version...)
+	file = append(file, data...)
+	file = append(file, hash.Sum(nil)...)
+	if err := ioutil.WriteFile(path, file, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// makeTData собирает tdata с одним аккаунтом: основной датацентр 2, плюс ключ для 4
+func makeTData(t *testing.T, passcode string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "mtproto-tdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	salt := bytes.Repeat([]byte{0x5a}, 32)
+	localKey := bytes.Repeat([]byte{0x42, 0x17}, tdesktopLocalKeyLen/2)
+
+	info := new(bytes.Buffer)
+	_ = binary.Write(info, binary.BigEndian, int32(1)) // количество аккаунтов
+	_ = binary.Write(info, binary.BigEndian, int32(0)) // индекс
+
+	keyData := new(bytes.Buffer)
+	qtByteArray(keyData, salt)
+	qtByteArray(keyData, tdesktopEncrypt(t, localKey, tdesktopPasscodeKey(salt, passcode)))
+	qtByteArray(keyData, tdesktopEncrypt(t, info.Bytes(), localKey))
+	writeTDF(t, filepath.Join(dir, tdesktopKeyFile+"s"), keyData.Bytes())
+
+	auth := new(bytes.Buffer)
+	_ = binary.Write(auth, binary.BigEndian, int32(-1))
+	_ = binary.Write(auth, binary.BigEndian, int32(-1))
+	_ = binary.Write(auth, binary.BigEndian, uint64(5000000000))
+	_ = binary.Write(auth, binary.BigEndian, int32(2))
+	_ = binary.Write(auth, binary.BigEndian, int32(2)) // количество ключей
+	_ = binary.Write(auth, binary.BigEndian, int32(2))
+	auth.Write(testKey())
+	_ = binary.Write(auth, binary.BigEndian, int32(4))
+	auth.Write(bytes.Repeat([]byte{4}, authKeyLen))
+	_ = binary.Write(auth, binary.BigEndian, int32(0)) // ключи на удаление
+
+	account := new(bytes.Buffer)
+	_ = binary.Write(account, binary.BigEndian, uint32(tdesktopBlockMtpAuthorization))
+	qtByteArray(account, auth.Bytes())
+
+	accountFile := new(bytes.Buffer)
+	qtByteArray(accountFile, tdesktopEncrypt(t, account.Bytes(), localKey))
+	writeTDF(t, filepath.Join(dir, tdesktopFilePart("data")+"s"), accountFile.Bytes())
+
+	return dir
+}
+
+func TestTDesktop(t *testing.T) {
+	for _, passcode := range []string{"", "hunter2"} {
+		dir := makeTData(t, passcode)
+		defer os.RemoveAll(dir)
+
+		accounts, err := TDesktop(dir, passcode)
+		if err != nil {
+			t.Fatalf("passcode %q: %v", passcode, err)
+		}
+		if len(accounts) != 1 {
+			t.Fatalf("got %v accounts", len(accounts))
+		}
+
+		main := accounts[0].Session
+		if main.DC != 2 || main.UserID != 5000000000 || main.Hostname != "149.154.167.50:443" {
+			t.Errorf("main session: dc %v, user %v, host %v", main.DC, main.UserID, main.Hostname)
+		}
+		if !bytes.Equal(main.Key, testKey()) {
+			t.Error("auth key mismatch")
+		}
+		if len(accounts[0].Other) != 1 || accounts[0].Other[0].DC != 4 {
+			t.Errorf("unexpected other sessions: %+v", accounts[0].Other)
+		}
+	}
+
+	dir := makeTData(t, "right")
+	defer os.RemoveAll(dir)
+	if _, err := TDesktop(dir, "wrong"); err == nil {
+		t.Error("expected error for wrong passcode")
+	}
+}
+
+func TestTDesktopFilePart(t *testing.T) {
+	if got := tdesktopFilePart("data"); got != "D877F783D5D3EF8C" {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,53 @@
+package importer
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto"
+)
+
+// Telethon читает файл сессии телетона (*.session, это база sqlite). телетон хранит там одну строку
+// в таблице sessions: dc_id, server_address, port, auth_key, takeout_id
+func Telethon(path string) (*mtproto.Session, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading file")
+	}
+
+	return TelethonFromBytes(data)
+}
+
+// TelethonFromBytes то же самое, что и Telethon, только файл уже прочитан
+func TelethonFromBytes(data []byte) (*mtproto.Session, error) {
+	db, err := openSQLite(data)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.table("sessions")
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		key, _ := row["auth_key"].([]byte)
+		if len(key) == 0 {
+			// телетон оставляет строку без ключа, если авторизация не закончилась
+			continue
+		}
+
+		dc, _ := row["dc_id"].(int64)
+		host, _ := row["server_address"].(string)
+		port, _ := row["port"].(int64)
+
+		hostname := mtproto.DefaultDCAddress(int(dc))
+		if host != "" && port != 0 {
+			hostname = joinHostPort(host, port)
+		}
+
+		return newSession(int(dc), hostname, key, 0)
+	}
+
+	return nil, errors.New("session file doesn't contain auth key")
+}
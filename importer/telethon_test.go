@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func testKey() []byte {
+	key := make([]byte, authKeyLen)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return key
+}
+
+func TestTelethon(t *testing.T) {
+	session, err := Telethon("testdata/telethon.session")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if session.DC != 2 || session.Hostname != "149.154.167.51:443" {
+		t.Errorf("got dc %v at %v", session.DC, session.Hostname)
+	}
+	if !bytes.Equal(session.Key, testKey()) {
+		t.Error("auth key mismatch")
+	}
+	if len(session.Hash) != 8 || len(session.Salt) != 8 {
+		t.Errorf("invalid hash or salt: %x %x", session.Hash, session.Salt)
+	}
+}
+
+// в фикстуре у entities несколько уровней b-дерева и одна строка, которая не влезает в страницу
+func TestSQLiteTable(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/telethon.session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := openSQLite(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.table("entities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 300 {
+		t.Fatalf("got %v rows, want 300", len(rows))
+	}
+	for i, row := range rows {
+		id := int64(i + 1)
+		if row["id"] != id || row["hash"] != -id*1000003 || row["phone"] != 79990000000+id {
+			t.Fatalf("row %v: %v", i, row)
+		}
+		name := row["name"].(string)
+		if id == 150 && name != strings.Repeat("x", 3000) {
+			t.Errorf("overflowed value is broken: %v bytes", len(name))
+		}
+	}
+
+	if _, err := db.table("nonexistent"); err == nil {
+		t.Error("expected error for missing table")
+	}
+}
+
+// malformedSQLite собирает файл из одной страницы-листа с одной ячейкой cell
+func malformedSQLite(cell []byte) []byte {
+	data := make([]byte, 512)
+	copy(data, sqliteMagic)
+	data[16], data[17] = 2, 0 // страница 512 байт
+	header := data[sqliteHeaderLen:]
+	header[0] = sqlitePageLeafTable
+	header[4] = 1 // одна ячейка
+	header[8], header[9] = 1, 0x2c
+	copy(data[300:], cell)
+	return data
+}
+
+func TestSQLiteMalformed(t *testing.T) {
+	hugeCell := malformedSQLite(bytes.Repeat([]byte{0xff}, 9))
+
+	tooManyCells := malformedSQLite(nil)
+	tooManyCells[sqliteHeaderLen+3], tooManyCells[sqliteHeaderLen+4] = 0xff, 0xff
+
+	truncatedCell := malformedSQLite(nil)
+	truncatedCell[sqliteHeaderLen+8], truncatedCell[sqliteHeaderLen+9] = 1, 0xff
+	for i := 0x1ff - 8; i < len(truncatedCell); i++ {
+		truncatedCell[i] = 0xff
+	}
+
+	// длина заголовка записи и размер строки больше самой записи
+	hugeRecord := malformedSQLite([]byte{3, 1, 0xff, 0xff})
+	hugeColumn := malformedSQLite([]byte{11, 1, 10, 0x81, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	reserved := malformedSQLite(nil)
+	reserved[20] = 255
+
+	cases := map[string][]byte{
+		"huge cell size":      hugeCell,
+		"too many cells":      tooManyCells,
+		"truncated cell":      truncatedCell,
+		"huge record header":  hugeRecord,
+		"huge column":         hugeColumn,
+		"invalid usable size": reserved,
+	}
+	for name, data := range cases {
+		if _, err := TelethonFromBytes(data); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package mtproto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+// проверки входящих сообщений. без них тот, кто сидит посередине, может прислать сообщение еще раз
+// (и, например, второй раз выполнить обработку апдейта), или подсунуть сообщение из другой сессии
+// https://core.telegram.org/mtproto/security_guidelines#checking-msg-id
+// https://core.telegram.org/mtproto/description#message-identifier-msg-id
+
+// сообщения, время которых больше чем на maxMsgTimeAhead впереди времени сервера, отбрасываются.
+// слишком старые (больше maxMsgAge) тоже
+const maxMsgTimeAhead = 30 * time.Second
+
+type InvalidMessageReason uint8
+
+const (
+	InvalidMessageWrongSessionID InvalidMessageReason = iota + 1
+	InvalidMessageWrongMsgIDBits
+	InvalidMessageTooOld
+	InvalidMessageTooNew
+	InvalidMessageDuplicate
+)
+
+var invalidMessageReasons = map[InvalidMessageReason]string{
+	InvalidMessageWrongSessionID: "session_id doesn't match the current session",
+	InvalidMessageWrongMsgIDBits: "msg_id of server message must be odd",
+	InvalidMessageTooOld:         "msg_id is too old",
+	InvalidMessageTooNew:         "msg_id is too far in the future",
+	InvalidMessageDuplicate:      "message with this msg_id was already received",
+}
+
+// InvalidMessageError возвращается, если сообщение от сервера не прошло проверку. такое сообщение
+// не обрабатывается
+type InvalidMessageError struct {
+	MsgID  int64
+	Reason InvalidMessageReason
+}
+
+func (e *InvalidMessageError) Error() string {
+	return fmt.Sprintf("message %v rejected: %v", e.MsgID, invalidMessageReasons[e.Reason])
+}
+
+// isServerMsgID проверяет младшие биты msg_id: у сообщений сервера он нечетный
+func isServerMsgID(msgID int64) bool {
+	mod := msgID & 3
+	return mod == 1 || mod == 3
+}
+
+// checkPacket проверяет то, что есть только у пакета целиком, а не у сообщений в контейнере
+func (m *MTProto) checkPacket(msg serialize.CommonMessage) error {
+	msgID := int64(msg.GetMsgID())
+	if encrypted, ok := msg.(*serialize.EncryptedMessage); ok && !m.isKnownSession(encrypted.SessionID) {
+		return &InvalidMessageError{MsgID: msgID, Reason: InvalidMessageWrongSessionID}
+	}
+	if !isServerMsgID(msgID) {
+		return &InvalidMessageError{MsgID: msgID, Reason: InvalidMessageWrongMsgIDBits}
+	}
+	return nil
+}
+
+// checkReceived проверяет msg_id сообщения (в том числе вложенного в контейнер) и запоминает его,
+// что бы второй раз такое же сообщение уже не обрабатывалось
+func (m *MTProto) checkReceived(msgID int64, seqNo int32, data serialize.TL) error {
+	if !isServerMsgID(msgID) {
+		return &InvalidMessageError{MsgID: msgID, Reason: InvalidMessageWrongMsgIDBits}
+	}
+
+	// пока часы не сверены с сервером, окно считать не от чего: клиент с отстающими часами отбросил
+	// бы вообще все. по уведомлениям об ошибках и new_session_created как раз и узнается время
+	// сервера, поэтому их время не проверяется. у контейнера проверяется каждое сообщение внутри,
+	// иначе вместе с ним отбросились бы и те уведомления, что в нем лежат
+	switch data.(type) {
+	case *serialize.BadMsgNotification, *serialize.BadServerSalt, *serialize.NewSessionCreated,
+		*serialize.MessageContainer:
+	default:
+		if !m.isTimeSynced() {
+			break
+		}
+		now := m.serverTime()
+		sentAt := utils.MessageIDTime(msgID)
+		if sentAt.Before(now.Add(-maxMsgAge)) {
+			return &InvalidMessageError{MsgID: msgID, Reason: InvalidMessageTooOld}
+		}
+		if sentAt.After(now.Add(maxMsgTimeAhead)) {
+			return &InvalidMessageError{MsgID: msgID, Reason: InvalidMessageTooNew}
+		}
+	}
+
+	oldest := m.oldestLoggedMsgID()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.receivedLog.get(msgID); ok {
+		return &InvalidMessageError{MsgID: msgID, Reason: InvalidMessageDuplicate}
+	}
+	// про сообщения младше всех, что мы помним, уже нельзя сказать, получали мы их или нет
+	if msgID <= m.receivedLog.forgotten {
+		return &InvalidMessageError{MsgID: msgID, Reason: InvalidMessageTooOld}
+	}
+	m.receivedLog.add(msgID, &loggedMessage{seqNo: seqNo}, oldest, 0)
+	return nil
+}
@@ -0,0 +1,120 @@
+package mtproto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+func isInvalidMessage(err error, reason InvalidMessageReason) bool {
+	e, ok := err.(*InvalidMessageError)
+	return ok && e.Reason == reason
+}
+
+func TestCheckPacket(t *testing.T) {
+	m := newTestClockMTProto()
+	m.seq.reset(1)
+	msgID := serverMsgID(time.Now())
+
+	if err := m.checkPacket(&serialize.EncryptedMessage{SessionID: 1, MsgID: msgID}); err != nil {
+		t.Errorf("valid message rejected: %v", err)
+	}
+	if err := m.checkPacket(&serialize.EncryptedMessage{SessionID: 2, MsgID: msgID}); !isInvalidMessage(err, InvalidMessageWrongSessionID) {
+		t.Errorf("message from other session: got %v", err)
+	}
+	if err := m.checkPacket(&serialize.EncryptedMessage{SessionID: 1, MsgID: msgID - 1}); !isInvalidMessage(err, InvalidMessageWrongMsgIDBits) {
+		t.Errorf("message with client msg_id: got %v", err)
+	}
+}
+
+func TestDuplicateMessage(t *testing.T) {
+	m := newTestClockMTProto()
+	msgID := serverMsgID(time.Now())
+	msg := &serialize.EncryptedMessage{Msg: (&serialize.MsgsAck{}).Encode()}
+
+	if err := m.processResponse(int(msgID), 1, msg); err != nil {
+		t.Fatal(err)
+	}
+	err := m.processResponse(int(msgID), 1, msg)
+	if !isInvalidMessage(err, InvalidMessageDuplicate) {
+		t.Errorf("expected duplicate to be rejected, got %v", err)
+	}
+
+	// подтверждение могло потеряться, поэтому дубликат подтверждается еще раз
+	if _, acks := m.outgoing.take(); len(acks) != 2 {
+		t.Errorf("expected 2 acks, got %v", acks)
+	}
+}
+
+func TestMessageTimeWindow(t *testing.T) {
+	m := newTestClockMTProto()
+	msg := &serialize.EncryptedMessage{Msg: (&serialize.MsgsAck{}).Encode()}
+
+	// часы еще не сверены, и отстают они или нет, неизвестно
+	err := m.processResponse(int(serverMsgID(time.Now().Add(maxMsgTimeAhead+time.Minute))), 0, msg)
+	if err != nil {
+		t.Errorf("message rejected before clock sync: %v", err)
+	}
+
+	m.syncTime(time.Now())
+	err = m.processResponse(int(serverMsgID(time.Now().Add(-maxMsgAge-time.Minute))), 0, msg)
+	if !isInvalidMessage(err, InvalidMessageTooOld) {
+		t.Errorf("old message: got %v", err)
+	}
+	err = m.processResponse(int(serverMsgID(time.Now().Add(maxMsgTimeAhead+time.Minute))), 0, msg)
+	if !isInvalidMessage(err, InvalidMessageTooNew) {
+		t.Errorf("message from future: got %v", err)
+	}
+
+	// окно считается по часам сервера
+	m.syncTime(time.Now().Add(time.Hour))
+	err = m.processResponse(int(serverMsgID(time.Now().Add(time.Hour))), 0, msg)
+	if err != nil {
+		t.Errorf("message with server time rejected: %v", err)
+	}
+}
+
+func TestDuplicateInContainer(t *testing.T) {
+	m := newTestClockMTProto()
+	waiting := make(chan serialize.TL, 1)
+	m.responseChannels[5] = waiting
+
+	seen := serverMsgID(time.Now())
+	if err := m.processResponse(int(seen), 0, &serialize.EncryptedMessage{Msg: (&serialize.MsgsAck{}).Encode()}); err != nil {
+		t.Fatal(err)
+	}
+
+	container := serialize.MessageContainer{
+		{MsgID: seen, Msg: (&serialize.MsgsAck{}).Encode()},
+		{MsgID: seen + 4, Msg: (&serialize.FutureSalts{ReqMsgID: 5}).Encode()},
+	}
+	err := m.processResponse(int(seen+8), 0, &serialize.EncryptedMessage{Msg: container.Encode()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(waiting) != 1 {
+		t.Error("message after duplicate in container was not processed")
+	}
+}
+
+func TestNoticeInContainerOutsideTimeWindow(t *testing.T) {
+	m := newTestClockMTProto()
+	m.syncTime(time.Now())
+	waiting := make(chan serialize.TL, 1)
+	m.responseChannels[4] = waiting
+
+	// часы клиента отстают, и сервер сообщает об этом в контейнере
+	ahead := serverMsgID(time.Now().Add(maxMsgTimeAhead + time.Minute))
+	container := serialize.MessageContainer{
+		{MsgID: ahead, Msg: (&serialize.BadMsgNotification{BadMsgID: 4, Code: int32(ErrBadMsgIdTooLow)}).Encode()},
+		{MsgID: ahead + 4, Msg: (&serialize.MsgsAck{}).Encode()},
+	}
+	err := m.processResponse(int(ahead+8), 0, &serialize.EncryptedMessage{Msg: container.Encode()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := (<-waiting).(*serialize.ErrorSessionConfigsChanged); !ok {
+		t.Error("bad_msg_notification in container was not processed")
+	}
+}
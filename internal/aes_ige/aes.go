@@ -59,6 +59,23 @@ func Decrypt(msg, key, checkData []byte) ([]byte, error) {
 	return out, nil
 }
 
+// EncryptLocal и DecryptLocal шифруют так же, как telegram desktop шифрует свои локальные файлы:
+// ключи считаются по старой схеме mtproto 1.0, но в обе стороны с x = 8. msgKey это первые 16 байт
+// sha1 от открытых данных, проверять его должен вызывающий
+func EncryptLocal(msg, key, msgKey []byte) ([]byte, error) {
+	aesKey, aesIV := generateAESIGE(msgKey, key, true)
+
+	out := make([]byte, len(msg))
+	if err := doAES256IGEencrypt(msg, out, aesKey, aesIV); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func DecryptLocal(msg, key, msgKey []byte) ([]byte, error) {
+	return Decrypt(msg, key, msgKey)
+}
+
 func doAES256IGEencrypt(data, out, key, iv []byte) error {
 	c, err := NewCipher(key, iv)
 	if err != nil {
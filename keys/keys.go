@@ -37,6 +37,12 @@ func ReadFromFile(path string) ([]*rsa.PublicKey, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "reading file  keys")
 	}
+
+	return Parse(data)
+}
+
+// Parse читает все ключи в формате PEM, которые есть в data
+func Parse(data []byte) ([]*rsa.PublicKey, error) {
 	keys := make([]*rsa.PublicKey, 0)
 	for {
 		block, rest := pem.Decode(data)
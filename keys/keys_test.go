@@ -0,0 +1,32 @@
+package keys
+
+import (
+	"crypto/rsa"
+	"encoding/binary"
+	"testing"
+)
+
+func TestBuiltinFingerprints(t *testing.T) {
+	cases := []struct {
+		name        string
+		keys        []*rsa.PublicKey
+		fingerprint uint64
+	}{
+		{"production", Production(), 0xd09d1d85de64fd85},
+		{"test", Test(), 0xb25898df208d2603},
+	}
+
+	for _, c := range cases {
+		if len(c.keys) != 1 {
+			t.Errorf("%v: expected 1 key, got %v", c.name, len(c.keys))
+			continue
+		}
+		if got := binary.LittleEndian.Uint64(RSAFingerprint(c.keys[0])); got != c.fingerprint {
+			t.Errorf("%v: fingerprint %#x, want %#x", c.name, got, c.fingerprint)
+		}
+	}
+
+	if len(Builtin()) != 2 {
+		t.Error("Builtin must return keys of both production and test servers")
+	}
+}
@@ -0,0 +1,55 @@
+package keys
+
+import (
+	"crypto/rsa"
+
+	"github.com/xelaj/go-dry"
+)
+
+// публичные ключи серверов телеграма, которые клиенты зашивают в себя. сервер в ответ на req_pq
+// присылает отпечатки своих ключей, из них и выбирается нужный
+// https://core.telegram.org/mtproto/auth_key
+
+// productionKeys это ключ боевых серверов, отпечаток 0xd09d1d85de64fd85
+const productionKeys = `-----BEGIN RSA PUBLIC KEY-----
+MIIBCgKCAQEA6LszBcC1LGzyr992NzE0ieY+BSaOW622Aa9Bd4ZHLl+TuFQ4lo4g
+5nKaMBwK/BIb9xUfg0Q29/2mgIR6Zr9krM7HjuIcCzFvDtr+L0GQjae9H0pRB2OO
+62cECs5HKhT5DZ98K33vmWiLowc621dQuwKWSQKjWf50XYFw42h21P2KXUGyp2y/
++aEyZ+uVgLLQbRA1dEjSDZ2iGRy12Mk5gpYc397aYp438fsJoHIgJ2lgMv5h7WY9
+t6N/byY9Nw9p21Og3AoXSL2q/2IJ1WRUhebgAdGVMlV1fkuOQoEzR7EdpqtQD9Cs
+5+bfo3Nhmcyvk5ftB0WkJ9z6bNZ7yxrP8wIDAQAB
+-----END RSA PUBLIC KEY-----
+`
+
+// testKeys это ключ тестовых серверов, отпечаток 0xb25898df208d2603
+const testKeys = `-----BEGIN RSA PUBLIC KEY-----
+MIIBCgKCAQEAyMEdY1aR+sCR3ZSJrtztKTKqigvO/vBfqACJLZtS7QMgCGXJ6XIR
+yy7mx66W0/sOFa7/1mAZtEoIokDP3ShoqF4fVNb6XeqgQfaUHd8wJpDWHcR2OFwv
+plUUI1PLTktZ9uW2WE23b+ixNwJjJGwBDJPQEQFBE+vfmH0JP503wr5INS1poWg/
+j25sIWeYPHYeOrFp/eXaqhISP6G+q2IeTaWTXpwZj4LzXq5YOpk4bYEQ6mvRq7D1
+aHWfYmlEGepfaYR8Q0YqvvhYtMte3ITnuSJs171+GDqpdKcSwHnd6FudwGO4pcCO
+j4WcDuXc2CTHgH8gFTNhp/Y8/SpDOhvn9QIDAQAB
+-----END RSA PUBLIC KEY-----
+`
+
+// Production возвращает ключи боевых серверов
+func Production() []*rsa.PublicKey {
+	return mustParse(productionKeys)
+}
+
+// Test возвращает ключи тестовых серверов
+func Test() []*rsa.PublicKey {
+	return mustParse(testKeys)
+}
+
+// Builtin возвращает все зашитые ключи. клиент сам выберет нужный по отпечатку, так что с ними
+// можно подключаться и к боевым, и к тестовым серверам
+func Builtin() []*rsa.PublicKey {
+	return append(Production(), Test()...)
+}
+
+func mustParse(data string) []*rsa.PublicKey {
+	keys, err := Parse([]byte(data))
+	dry.PanicIfErr(err)
+	return keys
+}
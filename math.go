@@ -1,13 +1,16 @@
 package mtproto
 
 import (
+	crand "crypto/rand"
 	"crypto/rsa"
-	"math"
+	"crypto/sha256"
 	"math/big"
 	"math/rand"
 	"time"
 
-	"github.com/xelaj/go-dry"
+	"github.com/pkg/errors"
+
+	ige "github.com/lonesta/mtproto/aes_ige"
 )
 
 var (
@@ -18,20 +21,63 @@ var (
 	big17 = big.NewInt(17)
 )
 
-// doRSAencrypt шифрует ровно 1 блок сообщения длиной 255 байт публичным ключом.
-// специфический алгоритм для мтпрото, т.к. документация не указывает, шифрование
-// по OAEP или как-то еще
-func doRSAencrypt(block []byte, key *rsa.PublicKey) []byte {
-	dry.PanicIf(len(block) != math.MaxUint8, "block size isn't equal 255 bytes")
-	z := big.NewInt(0).SetBytes(block)
-	exponent := big.NewInt(int64(key.E))
+const (
+	// сколько байт можно зашифровать через RSA_PAD и до скольки они дополняются
+	rsaPadDataLen   = 144
+	rsaPadPaddedLen = 192
+)
+
+// rsaPadEncrypt шифрует данные (не больше 144 байт) публичным ключом сервера по схеме RSA_PAD:
+// данные дополняются случайными байтами до 192, шифруются в AES-IGE случайным временным ключом, а
+// уже временный ключ (поксоренный с хешем результата) вместе с шифровкой идет в RSA
+// https://core.telegram.org/mtproto/auth_key#presenting-proof-of-work-server-authentication
+func rsaPadEncrypt(data []byte, key *rsa.PublicKey) ([]byte, error) {
+	if len(data) > rsaPadDataLen {
+		return nil, errors.Errorf("data is too long: %v bytes, max is %v", len(data), rsaPadDataLen)
+	}
+
+	dataWithPadding := make([]byte, rsaPadPaddedLen)
+	copy(dataWithPadding, data)
+	if _, err := crand.Read(dataWithPadding[len(data):]); err != nil {
+		return nil, errors.Wrap(err, "generating padding")
+	}
+
+	dataPadReversed := make([]byte, len(dataWithPadding))
+	for i := range dataWithPadding {
+		dataPadReversed[len(dataPadReversed)-1-i] = dataWithPadding[i]
+	}
+
+	// в среднем хватает одной-двух попыток, число должно оказаться меньше модуля ключа
+	tempKey := make([]byte, 32)
+	for {
+		if _, err := crand.Read(tempKey); err != nil {
+			return nil, errors.Wrap(err, "generating temporary key")
+		}
+
+		hash := sha256.Sum256(append(append([]byte{}, tempKey...), dataWithPadding...))
+		dataWithHash := append(append([]byte{}, dataPadReversed...), hash[:]...)
 
-	c := big.NewInt(0).Exp(z, exponent, key.N)
+		aesEncrypted, err := ige.EncryptIGE(dataWithHash, tempKey, make([]byte, 32))
+		if err != nil {
+			return nil, errors.Wrap(err, "encrypting with temporary key")
+		}
 
-	res := make([]byte, 256)
-	copy(res, c.Bytes())
+		aesHash := sha256.Sum256(aesEncrypted)
+		keyAESEncrypted := append([]byte{}, tempKey...)
+		xor(keyAESEncrypted, aesHash[:])
+		keyAESEncrypted = append(keyAESEncrypted, aesEncrypted...)
+
+		z := big.NewInt(0).SetBytes(keyAESEncrypted)
+		if z.Cmp(key.N) >= 0 {
+			continue
+		}
 
-	return res
+		c := big.NewInt(0).Exp(z, big.NewInt(int64(key.E)), key.N)
+		res := make([]byte, 256)
+		cBytes := c.Bytes()
+		copy(res[len(res)-len(cBytes):], cBytes)
+		return res, nil
+	}
 }
 
 // splitPQ раскладывает число на два простых, при том таким образом, что p1 < p2
@@ -111,14 +157,23 @@ func splitPQ(pq *big.Int) (p1, p2 *big.Int) {
 	return p1, p2
 }
 
-func makeGAB(g int32, g_a, dh_prime *big.Int) (b, g_b, g_ab *big.Int) {
-	rnd := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint: gosec зачем
-	rndmax := big.NewInt(0).SetBit(big.NewInt(0), 2048, 1)
-	b = big.NewInt(0).Rand(rnd, rndmax)
-	g_b = big.NewInt(0).Exp(big.NewInt(int64(g)), b, dh_prime)
-	g_ab = big.NewInt(0).Exp(g_a, b, dh_prime)
+// makeGAB выбирает случайное b и считает g_b и g_ab. b выбирается заново, пока g_b не пройдет те же
+// проверки, что и g_a от сервера
+func makeGAB(g int32, g_a, dh_prime *big.Int) (g_b, g_ab *big.Int, err error) {
+	rndmax := big.NewInt(0).SetBit(big.NewInt(0), dhPrimeBits, 1)
+	for {
+		b, err := crand.Int(crand.Reader, rndmax)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "generating b")
+		}
+		g_b = big.NewInt(0).Exp(big.NewInt(int64(g)), b, dh_prime)
+		if checkDHValue("g_b", g_b, dh_prime) != nil {
+			continue
+		}
 
-	return
+		g_ab = big.NewInt(0).Exp(g_a, b, dh_prime)
+		return g_b, g_ab, nil
+	}
 }
 
 func xor(dst, src []byte) {
@@ -1,8 +1,14 @@
 package mtproto
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
 	"math/big"
 	"testing"
+
+	ige "github.com/lonesta/mtproto/aes_ige"
 )
 
 func TestSplitPQ(t *testing.T) {
@@ -21,3 +27,56 @@ func TestSplitPQ(t *testing.T) {
 		}
 	}
 }
+
+// decryptRSAPad делает то же, что сервер: расшифровывает RSA_PAD приватным ключом
+func decryptRSAPad(t *testing.T, encrypted []byte, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	keyAESEncrypted := make([]byte, 256)
+	m := big.NewInt(0).Exp(big.NewInt(0).SetBytes(encrypted), key.D, key.N).Bytes()
+	copy(keyAESEncrypted[len(keyAESEncrypted)-len(m):], m)
+
+	tempKey, aesEncrypted := keyAESEncrypted[:32], keyAESEncrypted[32:]
+	aesHash := sha256.Sum256(aesEncrypted)
+	xor(tempKey, aesHash[:])
+
+	dataWithHash, err := ige.DecryptIGE(aesEncrypted, tempKey, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataWithPadding := make([]byte, rsaPadPaddedLen)
+	for i := range dataWithPadding {
+		dataWithPadding[i] = dataWithHash[rsaPadPaddedLen-1-i]
+	}
+
+	hash := sha256.Sum256(append(append([]byte{}, tempKey...), dataWithPadding...))
+	if !bytes.Equal(hash[:], dataWithHash[rsaPadPaddedLen:]) {
+		t.Fatal("hash mismatch")
+	}
+	return dataWithPadding
+}
+
+func TestRSAPadEncrypt(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("p_q_inner_data goes here")
+	for i := 0; i < 10; i++ {
+		encrypted, err := rsaPadEncrypt(data, &key.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(encrypted) != 256 {
+			t.Fatalf("encrypted data must be 256 bytes, got %v", len(encrypted))
+		}
+		if decrypted := decryptRSAPad(t, encrypted, key); !bytes.HasPrefix(decrypted, data) {
+			t.Errorf("data mismatch: %x", decrypted)
+		}
+	}
+
+	_, err = rsaPadEncrypt(make([]byte, rsaPadDataLen+1), &key.PublicKey)
+	if err == nil {
+		t.Error("too long data must not be encrypted")
+	}
+}
@@ -0,0 +1,112 @@
+package mtproto
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lonesta/mtproto/utils"
+)
+
+// msg_id в одной сессии должны строго возрастать, а seqno это удвоенное количество уже отправленных
+// сообщений, требующих подтверждения (content-related), плюс один, если это сообщение тоже такое.
+// сервер сверяет одно с другим: у сообщения с большим msg_id seqno не может быть меньше. поэтому
+// msg_id и seqno выдаются вместе, под одним мьютексом
+// https://core.telegram.org/mtproto/description#message-identifier-msg-id
+// https://core.telegram.org/mtproto/description#message-sequence-number-msg-seqno
+
+// msgSequence выдает msg_id и seqno для сообщений одной сессии
+type msgSequence struct {
+	mutex     sync.Mutex
+	session   int64
+	lastMsgID int64
+	// первый msg_id, который может быть у сообщения этой сессии. все, что меньше, выдано в прошлых
+	firstMsgID int64
+	// сколько отправлено сообщений, которые требуют подтверждения
+	contentMessages int32
+}
+
+// reset начинает новую сессию: seqno снова считается с нуля. msg_id продолжают расти, так проще
+// не получить повтор, если часы сервера вдруг отстанут
+func (s *msgSequence) reset(sessionID int64) {
+	s.mutex.Lock()
+	s.session = sessionID
+	s.firstMsgID = s.lastMsgID + 1
+	s.contentMessages = 0
+	s.mutex.Unlock()
+}
+
+// msgSequenceState это сессия и ее seqno, см. msgSequence.state
+type msgSequenceState struct {
+	session         int64
+	firstMsgID      int64
+	contentMessages int32
+}
+
+// state и restore нужны, что бы вернуться в прежнюю сессию, если в новую перейти не вышло. msg_id
+// при этом не восстанавливаются, они только растут
+func (s *msgSequence) state() msgSequenceState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return msgSequenceState{session: s.session, firstMsgID: s.firstMsgID, contentMessages: s.contentMessages}
+}
+
+func (s *msgSequence) restore(state msgSequenceState) {
+	s.mutex.Lock()
+	s.session = state.session
+	s.firstMsgID = state.firstMsgID
+	s.contentMessages = state.contentMessages
+	s.mutex.Unlock()
+}
+
+func (s *msgSequence) sessionID() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.session
+}
+
+// inSession проверяет, что msgID выдан в текущей сессии, а не до последнего reset
+func (s *msgSequence) inSession(msgID int64) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return msgID >= s.firstMsgID
+}
+
+// next выдает msg_id для момента now (но всегда больше предыдущего) и seqno. contentRelated
+// означает, что сообщение требует подтверждения, у таких seqno нечетный
+func (s *msgSequence) next(now time.Time, contentRelated bool) (msgID int64, seqNo int32) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	msgID = utils.MessageIDAt(now)
+	if msgID <= s.lastMsgID {
+		// в один и тот же момент (или если часы пошли назад) просто берем следующий после прошлого.
+		// младшие два бита у msg_id клиента всегда нулевые
+		msgID = s.lastMsgID + 4
+	}
+	s.lastMsgID = msgID
+
+	seqNo = s.contentMessages * 2
+	if contentRelated {
+		seqNo++
+		s.contentMessages++
+	}
+	return msgID, seqNo
+}
+
+// lastSeqNo возвращает seqno, который получит следующее сообщение без подтверждения
+func (s *msgSequence) lastSeqNo() int32 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.contentMessages * 2
+}
+
+// nextMessage выдает msg_id и seqno для нового сообщения по часам сервера
+func (m *MTProto) nextMessage(contentRelated bool) (msgID int64, seqNo int32) {
+	return m.seq.next(m.serverTime(), contentRelated)
+}
+
+// newMsgID создает msg_id для сообщения, у которого seqno не важен, например при обмене ключами
+func (m *MTProto) newMsgID() int64 {
+	msgID, _ := m.nextMessage(false)
+	return msgID
+}
@@ -0,0 +1,204 @@
+package mtproto
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	ige "github.com/lonesta/mtproto/aes_ige"
+	"github.com/lonesta/mtproto/serialize"
+)
+
+func TestMsgSequence(t *testing.T) {
+	var s msgSequence
+	s.reset(1)
+	now := time.Now()
+
+	// в один и тот же момент msg_id все равно должны расти
+	first, seqNo := s.next(now, true)
+	second, ackSeqNo := s.next(now, false)
+	third, nextSeqNo := s.next(now.Add(-time.Second), true)
+	if !(first < second && second < third) {
+		t.Errorf("msg_ids don't grow: %v, %v, %v", first, second, third)
+	}
+	for _, id := range []int64{first, second, third} {
+		if id%4 != 0 {
+			t.Errorf("msg_id %v is not divisible by 4", id)
+		}
+	}
+	if seqNo != 1 || ackSeqNo != 2 || nextSeqNo != 3 {
+		t.Errorf("seqno %v, %v, %v, want 1, 2, 3", seqNo, ackSeqNo, nextSeqNo)
+	}
+
+	// в новой сессии seqno считается заново
+	s.reset(2)
+	fourth, seqNo := s.next(now, true)
+	if fourth <= third || seqNo != 1 || s.sessionID() != 2 {
+		t.Errorf("after reset got msg_id %v, seqno %v, session %v", fourth, seqNo, s.sessionID())
+	}
+}
+
+type sentMessage struct {
+	msgID int64
+	seqNo int32
+}
+
+// pingServer расшифровывает все, что отправляет клиент, запоминает msg_id и seqno, и отвечает
+// pong на каждый ping
+type pingServer struct {
+	t   *testing.T
+	key []byte
+
+	mutex      sync.Mutex
+	sessions   map[int64]bool
+	messages   []sentMessage
+	containers map[int64][]int64
+
+	pongs chan *serialize.Pong
+}
+
+func newPingServer(t *testing.T) *pingServer {
+	return &pingServer{
+		t:          t,
+		sessions:   make(map[int64]bool),
+		containers: make(map[int64][]int64),
+		pongs:      make(chan *serialize.Pong, 1024),
+	}
+}
+
+func (s *pingServer) WritePacket(data []byte) error {
+	// ige.Decrypt считает ключи для сообщений от сервера, см. decryptFromClient
+	shifted := append(make([]byte, 8), s.key[:len(s.key)-8]...)
+	plain, err := ige.Decrypt(data[24:], shifted, data[8:24])
+	if err != nil {
+		s.t.Error(err)
+		return err
+	}
+
+	d := serialize.NewDecoder(plain)
+	_ = d.PopLong() // соль
+	session := d.PopLong()
+	msg := &serialize.EncryptedMessage{MsgID: d.PopLong(), SeqNo: d.PopInt()}
+	msg.Msg = d.PopRawBytes(int(d.PopInt()))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sessions[session] = true
+	s.receive(msg)
+	if container, ok := serialize.NewDecoder(msg.Msg).PopObj().(*serialize.MessageContainer); ok {
+		for _, item := range *container {
+			s.containers[msg.MsgID] = append(s.containers[msg.MsgID], item.MsgID)
+			s.receive(item)
+		}
+	}
+	return nil
+}
+
+func (s *pingServer) receive(msg *serialize.EncryptedMessage) {
+	s.messages = append(s.messages, sentMessage{msgID: msg.MsgID, seqNo: msg.SeqNo})
+	if binary.LittleEndian.Uint32(msg.Msg) == (&PingParams{}).CRC() {
+		s.pongs <- &serialize.Pong{MsgID: msg.MsgID, PingID: int64(binary.LittleEndian.Uint64(msg.Msg[4:]))}
+	}
+}
+
+func (s *pingServer) ReadPacket() ([]byte, error) { select {} }
+func (s *pingServer) Close() error                { return nil }
+
+// respond отдает клиенту ответы так же, как это делает читающая горутина
+func (s *pingServer) respond(m *MTProto, done chan struct{}) {
+	msgID := serverMsgID(time.Now())
+	for {
+		select {
+		case <-done:
+			return
+		case pong := <-s.pongs:
+			msgID += 4
+			err := m.processResponse(int(msgID), 1, &serialize.EncryptedMessage{Msg: pong.Encode()})
+			if err != nil {
+				s.t.Error(err)
+			}
+		}
+	}
+}
+
+func TestMakeRequestConcurrent(t *testing.T) {
+	const (
+		goroutines = 50
+		requests   = 20
+	)
+
+	server := newPingServer(t)
+	m := newTestMTProto(t, server)
+	server.key = m.GetAuthKey()
+
+	done := make(chan struct{})
+	go server.respond(m, done)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < requests; i++ {
+				pingID := int64(g*requests + i)
+				resp, err := m.MakeRequest(&PingParams{PingID: pingID})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if pong, ok := resp.(*serialize.Pong); !ok || pong.PingID != pingID {
+					t.Errorf("ping %v got response %#v", pingID, resp)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(done)
+	if err := m.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if len(server.sessions) != 1 || !server.sessions[m.GetSessionID()] {
+		t.Errorf("messages were sent in sessions %v", server.sessions)
+	}
+
+	seen := make(map[int64]bool)
+	contentMessages := 0
+	for _, msg := range server.messages {
+		if seen[msg.msgID] {
+			t.Errorf("msg_id %v was sent twice", msg.msgID)
+		}
+		seen[msg.msgID] = true
+		if msg.msgID%4 != 0 {
+			t.Errorf("msg_id %v is not divisible by 4", msg.msgID)
+		}
+		if msg.seqNo&1 != 0 {
+			contentMessages++
+		}
+	}
+	if contentMessages != goroutines*requests {
+		t.Errorf("%v content-related messages, want %v", contentMessages, goroutines*requests)
+	}
+
+	for id, items := range server.containers {
+		for _, item := range items {
+			if item >= id {
+				t.Errorf("container %v contains message %v with greater msg_id", id, item)
+			}
+		}
+	}
+
+	// по порядку msg_id seqno не убывает, а нечетные вообще не повторяются
+	sort.Slice(server.messages, func(i, j int) bool { return server.messages[i].msgID < server.messages[j].msgID })
+	for i := 1; i < len(server.messages); i++ {
+		prev, cur := server.messages[i-1], server.messages[i]
+		if cur.seqNo < prev.seqNo || (cur.seqNo == prev.seqNo && cur.seqNo&1 != 0) {
+			t.Fatalf("msg_id %v has seqno %v, but previous msg_id %v has %v", cur.msgID, cur.seqNo, prev.msgID, prev.seqNo)
+		}
+	}
+}
@@ -7,21 +7,35 @@ import (
 	"net"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/xelaj/errs"
-	"github.com/xelaj/go-dry"
 
+	"github.com/lonesta/mtproto/keys"
 	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/transport"
 	"github.com/lonesta/mtproto/utils"
 )
 
 type MTProto struct {
 	addr         string
-	conn         *net.TCPConn
-	stopRoutines context.CancelFunc // остановить ping, read, и подобные горутины
-	routineswg   sync.WaitGroup     // WaitGroup что бы быть уверенным, что все рутины остановились
+	dc           int // номер датацентра, 0 если неизвестен, см. DC()
+	conn         transport.Conn
+	transport    transport.Transport // режим упаковки пакетов, нужен при каждом переподключении
+	dialer       transport.Dialer    // через него открываются все соединения, в том числе при миграции
+	stopRoutines context.CancelFunc  // остановить ping, read, и подобные горутины
+	routineswg   sync.WaitGroup      // WaitGroup что бы быть уверенным, что все рутины остановились
+
+	// не ноль, пока идет переподключение после обрыва соединения
+	reconnecting int32
+	// не ноль, если соединение закрыли через Disconnect(), тогда переподключаться не нужно
+	disconnected int32
+	// насколько часы сервера впереди локальных, в наносекундах. меняется атомарно, см. clock.go
+	timeOffset int64
+	// не ноль, если timeOffset уже сверен с сервером в текущем соединении
+	timeSynced int32
 
 	// ключ авторизации. изменять можно только через setAuthKey
 	authKey []byte
@@ -29,10 +43,36 @@ type MTProto struct {
 	// хеш ключа авторизации. изменять можно только через setAuthKey
 	authKeyHash []byte
 
-	// соль сессии
+	// в режиме PFS authKey это временный ключ, а постоянный (тот, что сохраняется) лежит тут. без
+	// PFS здесь nil. см. pfs.go
+	permAuthKey     []byte
+	permAuthKeyHash []byte
+	// сколько живет временный ключ, 0 если PFS выключен
+	tempKeyTTL time.Duration
+	// когда истечет текущий временный ключ. меняется под mutex, ключ меняют из отдельной горутины
+	tempKeyExpiresAt time.Time
+	// прошлый временный ключ и его сессия. после смены ключа на живом соединении ответы на запросы,
+	// которые ушли со старым ключом, еще какое-то время приходят зашифрованными им
+	prevAuthKey     []byte
+	prevAuthKeyHash []byte
+	prevSessionID   int64
+	// держится, пока очередь шифруется и отправляется, что бы ключ и сессия не сменились посреди
+	// нее, см. switchTempKey
+	sendMutex sync.Mutex
+	// вызывается после привязки каждого временного ключа, см. SetTempKeyBoundHandler
+	onTempKeyBound func(conn *MTProto) error
+
+	// соль сессии. меняется только под saltMutex, см. salts.go
 	serverSalt int64
 	encrypted  bool
-	sessionId  int64
+	// id сессии, msg_id и seqno ее сообщений, см. msgid.go
+	seq msgSequence
+
+	saltMutex sync.Mutex
+	// соли, полученные заранее через get_future_salts, отсортированные по началу действия
+	futureSalts []*serialize.FutureSalt
+	// сюда пишется, когда заранее полученные соли стали не нужны и пора запросить новые
+	saltsInvalidated chan struct{}
 
 	// общий мьютекс
 	mutex *sync.Mutex
@@ -49,21 +89,36 @@ type MTProto struct {
 	// каналы, которые ожидают ответа rpc. ответ записывается в канал и удаляется
 	responseChannels map[int64]chan serialize.TL
 
-	// идентификаторы сообщений, нужны что бы посылать и принимать сообщения.
-	seqNo int32
-	msgId int64
-
-	// не знаю что это но как-то используется
-	lastSeqNo int32
+	// очередь зашифрованных сообщений на отправку
+	outgoing *outgoingQueue
+	// какие сообщения ушли в каких контейнерах, см. rememberContainer
+	sentContainers map[int64][]int64
+	// что отправлено и что получено за последнее время, см. service_messages.go
+	sentLog     messageLog
+	receivedLog messageLog
+	// о каких запросах спросили в msgs_state_req после переподключения, по msg_id самого
+	// msgs_state_req, см. reconnect.go
+	stateRequests map[int64][]int64
+	// запросы больше этого размера сжимаются, если <= 0, то не сжимается ничего
+	compressionThreshold int
 
 	// пока непонятно для чего, кажется это нужно клиенту конкретно телеграма
 	dclist map[int]string
 
-	// путь до файла токена сессии.
-	tokensStorage string
-
-	// один из публичных ключей telegram. нужен только для создания сессии.
-	publicKey *rsa.PublicKey
+	// где хранится сессия, и под каким аккаунтом
+	sessionStorage SessionStorage
+	account        string
+	// номер, под которым сессия лежит в sessionStorage: HomeDC для основного соединения, номер
+	// датацентра для остальных
+	sessionDC int
+	// id пользователя, если он известен. нужен только что бы сохранить его вместе с сессией
+	userID int64
+	// в ключ этого датацентра уже перенесена авторизация, см. newDCConn
+	authorized bool
+
+	// публичные ключи telegram. нужны только для создания сессии, из них берется тот, отпечаток
+	// которого пришлет сервер
+	publicKeys []*rsa.PublicKey
 
 	// serviceChannel нужен только на время создания ключей, т.к. это
 	// не RpcResult, поэтому все данные отдаются в один поток без
@@ -77,34 +132,117 @@ type MTProto struct {
 	Warnings chan error
 
 	serverRequestHandlers []customHandlerFunc
+
+	// соединения с другими датацентрами, см. ConnectDC
+	dcConns
 }
 
 type customHandlerFunc = func(i interface{}) bool
 
 type Config struct {
+	// AuthKeyFile это путь до файла сессии. используется, только если не указан SessionStorage
 	AuthKeyFile string
-	ServerHost  string
-	PublicKey   *rsa.PublicKey
+	// SessionStorage хранит ключи авторизации. если не указан и AuthKeyFile пустой, то сессия
+	// хранится только в памяти
+	SessionStorage SessionStorage
+	// Account это имя аккаунта в SessionStorage, нужно, если в одном хранилище лежит несколько аккаунтов
+	Account string
+	// SessionPassphrase если указан, то сессии шифруются ключом из этого пароля (см.
+	// NewPassphraseSessionStorage). старые незашифрованные сессии перешифровываются автоматически
+	SessionPassphrase string
+
+	ServerHost string
+	// PublicKey оставлен для совместимости, он просто добавляется к PublicKeys
+	PublicKey *rsa.PublicKey
+	// PublicKeys это публичные ключи серверов. если не указаны ни они, ни PublicKey, то используются
+	// зашитые в пакет keys ключи боевых и тестовых серверов
+	PublicKeys []*rsa.PublicKey
+	// Transport это режим упаковки пакетов (abridged, intermediate и т.д.). если не указан,
+	// используется intermediate. с websocket dialer'ом транспорт оборачивается в obfuscated2, см.
+	// transport.ForDialer
+	Transport transport.Transport
+	// Dialer открывает соединения с датацентрами, например через SOCKS5 или HTTP прокси. если не
+	// указан, то подключение прямое
+	Dialer transport.Dialer
+	// CompressionThreshold это размер запроса в байтах, начиная с которого запрос сжимается в
+	// gzip_packed. если 0, то используется 1024, если меньше нуля, то запросы не сжимаются вообще
+	CompressionThreshold int
+	// TempKeyTTL включает perfect forward secrecy: трафик шифруется временным ключом, который живет
+	// TempKeyTTL и меняется до того, как истечет. постоянный ключ при этом используется только для
+	// привязки временного. если 0, то все шифруется постоянным ключом
+	TempKeyTTL time.Duration
+	// TestServer означает, что клиент подключается к тестовым серверам. MediaOnly означает, что
+	// адрес датацентра это media-only адрес. оба нужны только для номера датацентра, который
+	// передается при создании временного ключа
+	TestServer bool
+	MediaOnly  bool
+	// DC это номер датацентра, для которого создается клиент. если не 0, то сессия хранится в
+	// SessionStorage под этим номером, а не как основная (см. HomeDC). обычно такие клиенты создаются
+	// через ConnectDC
+	DC int
 }
 
 func NewMTProto(c Config) (*MTProto, error) {
 	m := new(MTProto)
-	m.tokensStorage = c.AuthKeyFile
+	m.config = c
+	m.account = c.Account
+	m.sessionDC = c.DC
+	m.sessionStorage = c.SessionStorage
+	if m.sessionStorage == nil {
+		if c.AuthKeyFile != "" {
+			m.sessionStorage = &singleFileSessionStorage{path: c.AuthKeyFile}
+		} else {
+			m.sessionStorage = NewMemorySessionStorage()
+		}
+	}
+	if c.SessionPassphrase != "" {
+		m.sessionStorage = NewPassphraseSessionStorage(m.sessionStorage, c.SessionPassphrase)
+	}
 
+	m.mutex = &sync.Mutex{}
+	m.transport = c.Transport
+	if m.transport == nil {
+		m.transport = transport.NewIntermediate()
+	}
+	// через прокси адрес из сессии не используется, см. loadSession
+	m.addr = c.ServerHost
 	err := m.LoadSession()
 	if err == nil {
-		m.encrypted = true
+		m.setEncrypted(true)
 	} else if errs.IsNotFound(err) {
-		m.addr = c.ServerHost
-		m.encrypted = false
+		m.dc = c.DC
+		m.setEncrypted(false)
 	} else {
 		return nil, errors.Wrap(err, "loading session")
 	}
 
-	m.sessionId = utils.GenerateSessionID()
+	m.seq.reset(utils.GenerateSessionID())
 	m.serviceChannel = make(chan serialize.TL)
-	m.publicKey = c.PublicKey
+	m.publicKeys = c.PublicKeys
+	if c.PublicKey != nil {
+		m.publicKeys = append([]*rsa.PublicKey{c.PublicKey}, m.publicKeys...)
+	}
+	if len(m.publicKeys) == 0 {
+		m.publicKeys = keys.Builtin()
+	}
+	m.dialer = c.Dialer
+	if m.dialer == nil {
+		m.dialer = &net.Dialer{}
+	}
+	m.transport, err = transport.ForDialer(m.transport, m.dialer)
+	if err != nil {
+		return nil, err
+	}
+	m.tempKeyTTL = c.TempKeyTTL
+	m.compressionThreshold = c.CompressionThreshold
+	if m.compressionThreshold == 0 {
+		m.compressionThreshold = defaultCompressionThreshold
+	}
+	m.dcConns.conns = make(map[int]*dcConn)
+	m.outgoing = newOutgoingQueue()
+	m.saltsInvalidated = make(chan struct{}, 1)
 	m.responseChannels = make(map[int64]chan serialize.TL)
+	m.msgsIdToResp = make(map[int64]chan serialize.TL)
 	m.msgsIdDecodeAsVector = make(map[int64]reflect.Type)
 	m.serverRequestHandlers = make([]customHandlerFunc, 0)
 	// копируем мапу, т.к. все таки дефолтный список нельзя менять, вдруг его использует несколько клиентов
@@ -116,20 +254,34 @@ func NewMTProto(c Config) (*MTProto, error) {
 }
 
 func (m *MTProto) SetDCStorages(in map[int]string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	if m.dclist == nil {
 		m.dclist = make(map[int]string)
 	}
-	for k, v := range defaultDCList {
+	for k, v := range in {
 		m.dclist[k] = v
 	}
 }
 
+// dcAddress возвращает адрес датацентра из списка, который пришел от сервера (или встроенного)
+func (m *MTProto) dcAddress(dc int) (string, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	addr, ok := m.dclist[dc]
+	return addr, ok
+}
+
 // Stop останавливает текущее соединение
 func (m *MTProto) Stop() error {
-	m.stopRoutines()
-	m.routineswg.Wait()
+	conn, stopRoutines := m.connection()
+	stopRoutines()
 
-	err := m.conn.Close()
+	// сначала закрываем соединение, иначе читающая горутина так и останется висеть на чтении
+	err := conn.Close()
+	m.routineswg.Wait()
 	if err != nil {
 		return errors.Wrap(err, "closing connection")
 	}
@@ -139,87 +291,195 @@ func (m *MTProto) Stop() error {
 }
 
 func (m *MTProto) CreateConnection() error {
-	// connect
-	tcpAddr, err := net.ResolveTCPAddr("tcp", m.addr)
-	if err != nil {
-		return errors.Wrap(err, "resolving tcp")
-	}
-	m.conn, err = net.DialTCP("tcp", nil, tcpAddr)
-	if err != nil {
-		return errors.Wrap(err, "dialing tcp")
-	}
+	atomic.StoreInt32(&m.disconnected, 0)
+	return m.connect()
+}
 
-	// https://core.telegram.org/mtproto/mtproto-transports#intermediate
-	_, err = m.conn.Write([]byte{0xee, 0xee, 0xee, 0xee})
+// connect открывает новое соединение и запускает все горутины. в отличие от CreateConnection
+// вызывается и при переподключении
+func (m *MTProto) connect() error {
+	// пока соединения не было, часы могли уйти, сверяем их заново по первому же пакету
+	atomic.StoreInt32(&m.timeSynced, 0)
+
+	t, addr := m.endpoint()
+	conn, err := transport.Dial(context.Background(), m.dialer, t, addr)
 	if err != nil {
-		return errors.Wrap(err, "writing first byte")
+		return errors.Wrap(err, "connecting")
 	}
-
 	ctx, cancelfunc := context.WithCancel(context.Background())
-	m.stopRoutines = cancelfunc
+	// переподключение идет в своей горутине, а Stop и Disconnect могут вызвать в это же время
+	m.mutex.Lock()
+	m.conn, m.stopRoutines = conn, cancelfunc
+	m.mutex.Unlock()
 
 	// start reading responses from the server
 	m.startReadingResponses(ctx)
+	m.startSending(ctx)
 
+	err = m.prepareConnection(ctx)
+	if err != nil {
+		// горутины и соединение этой попытки больше никому не нужны, а следующая попытка их
+		// перезапишет
+		_ = m.Stop()
+		return err
+	}
+
+	// start keepalive pinging
+	m.startPinging(ctx)
+	m.startSaltsUpdating(ctx)
+	m.startLongPolling(ctx)
+
+	return nil
+}
+
+// prepareConnection создает ключи, если их еще нет, на только что открытом соединении
+func (m *MTProto) prepareConnection(ctx context.Context) error {
 	// get new authKey if need
-	if !m.encrypted {
+	if !m.isEncrypted() {
 		println("not encrypted, creating auth key")
-		err = m.makeAuthKey()
+		err := m.makeAuthKey()
 		if err != nil {
 			return errors.Wrap(err, "making auth key")
 		}
 	}
 
-	// start goroutines
-	m.msgsIdToResp = make(map[int64]chan serialize.TL)
-	m.mutex = &sync.Mutex{}
-
-	// start keepalive pinging
-	m.startPinging(ctx)
+	if m.tempKeyTTL > 0 {
+		// после обрыва соединения старый временный ключ еще годится, если не истекает
+		if m.tempKeyNeedsRenewal() {
+			err := m.makeTempAuthKey()
+			if err != nil {
+				return errors.Wrap(err, "making temporary auth key")
+			}
+		}
+		m.startTempKeyRotation(ctx)
+	}
 
 	return nil
 }
 
 // отправить запрос
-func (m *MTProto) makeRequest(data serialize.TL, as reflect.Type) (serialize.TL, error) {
-	resp, err := m.sendPacketNew(data, as)
+func (m *MTProto) makeRequest(ctx context.Context, data serialize.TL, as reflect.Type) (serialize.TL, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resp, msgID, err := m.sendPacketNew(ctx, data, as)
 	if err != nil {
 		return nil, errors.Wrap(err, "sending message")
 	}
-	response := <-resp
+
+	return m.waitResponse(ctx, resp, msgID, data, as)
+}
+
+// waitResponse ждет ответ на уже отправленный запрос data с id msgID. если запрос нужно
+// отправить заново (новая соль, миграция и т.п.), то он отправляется и ответ ждется уже на него
+func (m *MTProto) waitResponse(ctx context.Context, resp chan serialize.TL, msgID int64, data serialize.TL, as reflect.Type) (serialize.TL, error) {
+	var response serialize.TL
+	select {
+	case response = <-resp:
+	case <-ctx.Done():
+		m.dropAnswer(msgID, data)
+		return nil, errors.Wrap(ctx.Err(), "waiting for response")
+	}
 
 	if _, ok := response.(*serialize.ErrorSessionConfigsChanged); ok {
 		// если пришел ответ типа badServerSalt, то отправляем данные заново
-		return m.makeRequest(data, as)
+		return m.makeRequest(ctx, data, as)
+	}
+	if e, ok := response.(*BadMsgError); ok {
+		return nil, e
+	}
+	if e, ok := response.(*UnconfirmedRequestError); ok {
+		return nil, e
 	}
 	if e, ok := response.(*serialize.RpcError); ok {
-		realErr := RpcErrorToNative(e)
+		realErr := RpcErrorToNative(e).(*ErrResponseCode)
+
+		switch realErr.Message {
+		case "FILE_MIGRATE_X", "STATS_MIGRATE_X":
+			// сессия остается где была, в другой датацентр уходит только этот запрос
+			return m.makeRequestInDC(ctx, realErr, data, as)
+
+		case "PHONE_MIGRATE_X", "USER_MIGRATE_X", "NETWORK_MIGRATE_X":
+			if m.parent != nil {
+				// основной датацентр меняет только основное соединение. соединение с другим
+				// датацентром остается где было, а запрос уходит туда, куда сказали
+				return m.makeRequestInDC(ctx, realErr, data, as)
+			}
+		}
 
-		err = m.tryToProcessErr(realErr.(*ErrResponseCode))
+		err := m.tryToProcessErr(realErr)
 		if err != nil {
 			return nil, err
 		}
 
-		return m.makeRequest(data, as)
+		return m.makeRequest(ctx, data, as)
 	}
 
 	return response, nil
 }
 
+// makeRequestInDC отправляет запрос в датацентр, указанный в ошибке e
+func (m *MTProto) makeRequestInDC(ctx context.Context, e *ErrResponseCode, data serialize.TL, as reflect.Type) (serialize.TL, error) {
+	dc := e.AdditionalInfo.(int)
+	conn, err := m.ConnectDC(dc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to dc %v", dc)
+	}
+	if conn == m {
+		// сервер отправляет туда, где мы и так находимся, повторять бесполезно
+		return nil, e
+	}
+
+	return conn.makeRequest(ctx, data, as)
+}
+
+// dropAnswer забывает про запрос, ответ на который больше никто не ждет, и просит сервер этот
+// ответ не присылать
+func (m *MTProto) dropAnswer(msgID int64, request serialize.TL) {
+	m.mutex.Lock()
+	_, pending := m.responseChannels[msgID]
+	delete(m.responseChannels, msgID)
+	m.mutex.Unlock()
+
+	// ответ уже успел прийти, или это сам rpc_drop_answer, отменять который бессмысленно
+	if _, ok := request.(*RpcDropAnswerParams); !pending || ok {
+		return
+	}
+
+	go func() {
+		defer m.recoverGoroutine()
+		ctx, cancel := context.WithTimeout(context.Background(), dropAnswerTimeout)
+		defer cancel()
+
+		_, err := m.RpcDropAnswer(ctx, msgID)
+		if err != nil {
+			m.warn(errors.Wrap(err, "dropping answer"))
+		}
+	}()
+}
+
 func (m *MTProto) Disconnect() error {
+	atomic.StoreInt32(&m.disconnected, 1)
+
 	// stop all routines
-	m.stopRoutines()
+	conn, stopRoutines := m.connection()
+	stopRoutines()
 
-	err := m.conn.Close()
+	err := conn.Close()
+	m.routineswg.Wait()
 	if err != nil {
 		return errors.Wrap(err, "closing TCP connection")
 	}
 
+	// соединения с другими датацентрами без основного никому не нужны
+	m.closeDCConns()
+
 	// TODO: закрыть каналы
 
 	// возвращаем в false, потому что мы теряем конфигурацию
 	// сессии, и можем ее потерять во время отключения.
-	m.encrypted = false
+	m.setEncrypted(false)
 
 	return nil
 }
@@ -237,11 +497,41 @@ func (m *MTProto) startPinging(ctx context.Context) {
 				m.routineswg.Done()
 				return
 			case <-ticker:
-				_, err := m.Ping(0xCADACADA)
-				if err != nil {
-					if m.Warnings != nil {
-						m.Warnings <- errors.Wrap(err, "ping unsuccsesful")
+				// pong ждем не здесь: если соединение оборвалось, ping будет висеть до переподключения, а
+				// переподключение ждет, пока эта горутина остановится
+				go func() {
+					defer m.recoverGoroutine()
+					_, err := m.Ping(0xCADACADA)
+					if err != nil {
+						m.warn(errors.Wrap(err, "ping unsuccsesful"))
 					}
+				}()
+			}
+		}
+	}()
+}
+
+// startLongPolling нужен только для http транспорта: сервер может прислать что-то только в ответ
+// на запрос, поэтому как только открытых запросов не осталось, отправляем http_wait
+func (m *MTProto) startLongPolling(ctx context.Context) {
+	conn, _ := m.connection()
+	poller, ok := conn.(interface{ Idle() <-chan struct{} })
+	if !ok {
+		return
+	}
+
+	m.routineswg.Add(1)
+	go func() {
+		defer m.recoverGoroutine()
+		for {
+			select {
+			case <-ctx.Done():
+				m.routineswg.Done()
+				return
+			case <-poller.Idle():
+				err := m.HttpWait(0, 0, httpWaitMaxWait)
+				if err != nil {
+					m.warn(errors.Wrap(err, "sending http_wait"))
 				}
 			}
 		}
@@ -260,12 +550,23 @@ func (m *MTProto) startReadingResponses(ctx context.Context) {
 			default:
 				data, err := m.readFromConn(ctx)
 				if err != nil {
-					m.Warnings <- errors.Wrap(err, "reading from connection")
+					m.routineswg.Done()
+					if ctx.Err() != nil {
+						// соединение закрыли через Stop(), дальше читать нечего
+						return
+					}
+
+					// соединение умерло само. переподключаемся в отдельной горутине, т.к.
+					// reconnect дожидается остановки в том числе и этой горутины
+					m.warn(errors.Wrap(err, "reading from connection"))
+					go m.reconnect()
+					return
 				}
 
 				response, err := m.decodeRecievedData(data)
 				if err != nil {
-					m.Warnings <- errors.Wrap(err, "decoding received data")
+					m.warn(errors.Wrap(err, "decoding received data"))
+					continue
 				}
 
 				if m.serviceModeActivated {
@@ -274,9 +575,10 @@ func (m *MTProto) startReadingResponses(ctx context.Context) {
 					obj := decoder.PopObj()
 					m.serviceChannel <- obj
 				} else {
-					err = m.processResponse(int(m.msgId), int(m.seqNo), response)
+					m.syncTimeFromPacket(int64(response.GetMsgID()))
+					err = m.processResponse(response.GetMsgID(), response.GetSeqNo(), response)
 					if err != nil {
-						m.Warnings <- errors.Wrap(err, "processing response")
+						m.warn(errors.Wrap(err, "processing response"))
 					}
 				}
 			}
@@ -296,9 +598,12 @@ func (m *MTProto) processResponse(msgId, seqNo int, msg serialize.CommonMessage)
 		_ = decoder.PopCRC() // уже прочитали
 		rpc := &serialize.RpcResult{}
 		msgID := binary.LittleEndian.Uint64(msg.GetMsg()[serialize.WordLen : serialize.WordLen+serialize.LongLen])
-		if typ, ok := m.msgsIdDecodeAsVector[int64(msgID)]; ok {
+		m.mutex.Lock()
+		typ, ok := m.msgsIdDecodeAsVector[int64(msgID)]
+		delete(m.msgsIdDecodeAsVector, int64(msgID))
+		m.mutex.Unlock()
+		if ok {
 			rpc.DecodeFromButItsVector(decoder, typ)
-			delete(m.msgsIdDecodeAsVector, int64(msgID))
 		} else {
 			rpc.DecodeFrom(decoder)
 		}
@@ -307,48 +612,107 @@ func (m *MTProto) processResponse(msgId, seqNo int, msg serialize.CommonMessage)
 		data = decoder.PopObj()
 	}
 
+	err := m.checkReceived(int64(msgId), int32(seqNo), data)
+	if err != nil {
+		if e, ok := err.(*InvalidMessageError); ok && e.Reason == InvalidMessageDuplicate && seqNo&1 != 0 {
+			// сервер присылает сообщение еще раз, если не дождался подтверждения
+			m.outgoing.pushAck(int64(msgId))
+		}
+		return err
+	}
+
 	switch message := data.(type) {
 	case *serialize.MessageContainer:
 		println("MessageContainer")
 		for _, v := range *message {
 			err := m.processResponse(int(v.MsgID), int(v.SeqNo), v)
+			if _, ok := err.(*InvalidMessageError); ok {
+				// из-за одного отброшенного сообщения остальные терять не нужно
+				m.warn(err)
+				continue
+			}
 			if err != nil {
 				return errors.Wrap(err, "processing item in container")
 			}
 		}
 
 	case *serialize.BadServerSalt:
-		m.serverSalt = message.NewSalt
+		m.setServerSalt(message.NewSalt)
 		err := m.SaveSession()
-		dry.PanicIfErr(err)
-
-		m.mutex.Lock()
-		for _, v := range m.responseChannels {
-			v <- &serialize.ErrorSessionConfigsChanged{}
+		if err != nil {
+			m.warn(errors.Wrap(err, "saving session"))
 		}
-		m.mutex.Unlock()
+
+		m.resendMessages(m.affectedMessages(message.BadMsgID))
 
 	case *serialize.NewSessionCreated:
 		println("session created")
-		m.serverSalt = message.ServerSalt
+		// new_session_created сервер присылает первым в сессии, по нему часы и сверяются
+		m.syncTime(utils.MessageIDTime(int64(msgId)))
+		m.setServerSalt(message.ServerSalt)
 		err := m.SaveSession()
 		if err != nil {
-			if m.Warnings != nil {
-				m.Warnings <- errors.Wrap(err, "saving session")
-			}
+			m.warn(errors.Wrap(err, "saving session"))
+		}
+
+	case *serialize.FutureSalts:
+		// future_salts, как и pong, приходит не в rpc_result
+		err := m.writeRPCResponse(int(message.ReqMsgID), message)
+		if err != nil && !errs.IsNotFound(err) {
+			return errors.Wrap(err, "writing future salts")
 		}
 
 	case *serialize.Pong:
-		// игнорим, пришло и пришло, че бубнить то
+		// pong приходит не в rpc_result, но в нем есть id запроса, так что отдаем его тому, кто ждет.
+		// если не ждет никто (например ping_delay_disconnect), то и ладно
+		err := m.writeRPCResponse(int(message.MsgID), message)
+		if err != nil && !errs.IsNotFound(err) {
+			return errors.Wrap(err, "writing pong")
+		}
 
 	case *serialize.MsgsAck:
 		for _, id := range message.MsgIds {
 			m.gotAck(id)
+			m.forgetSent(id)
+		}
+
+	case *serialize.MsgsStateReq:
+		m.sendServiceMessage(&serialize.MsgsStateInfo{
+			ReqMsgId: int64(msgId),
+			Info:     m.receivedStates(message.MsgIds),
+		})
+
+	case *serialize.MsgResendReq:
+		m.handleResendReq(int64(msgId), message.MsgIds)
+
+	case *serialize.MsgsAllInfo:
+		err := m.handleAllInfo(message)
+		if err != nil {
+			return errors.Wrap(err, "processing msgs_all_info")
 		}
 
+	case *serialize.MsgsStateInfo:
+		// ответ на msgs_state_req, который отправляется после переподключения. еще приходит, если
+		// мы попросили заново сообщение, которое сервер уже забыл, сделать с этим ничего нельзя
+		err := m.handleStateInfo(message)
+		if err != nil {
+			return errors.Wrap(err, "processing msgs_state_info")
+		}
+
+	case *serialize.MsgsDetailedInfo:
+		// раз есть ответ, то запрос сервер точно получил
+		m.gotAck(message.MsgId)
+		m.forgetSent(message.MsgId)
+		m.handleDetailedInfo(message.AnswerMsgId)
+
+	case *serialize.MsgsNewDetailedInfo:
+		m.handleDetailedInfo(message.AnswerMsgId)
+
 	case *serialize.BadMsgNotification:
-		panic(message)
-		return BadMsgErrorFromNative(message)
+		err := m.handleBadMsg(int64(msgId), message)
+		if err != nil {
+			return errors.Wrap(err, "processing bad_msg_notification")
+		}
 
 	case *serialize.RpcResult:
 		obj := message.Obj
@@ -356,8 +720,10 @@ func (m *MTProto) processResponse(msgId, seqNo int, msg serialize.CommonMessage)
 			obj = v.Obj
 		}
 
+		m.forgetSent(message.ReqMsgID)
 		err := m.writeRPCResponse(int(message.ReqMsgID), obj)
-		if err != nil {
+		if err != nil && !errs.IsNotFound(err) {
+			// если не нашли, то ответ уже никто не ждет: например, запрос отменили через контекст
 			return errors.Wrap(err, "writing RPC response")
 		}
 
@@ -370,17 +736,13 @@ func (m *MTProto) processResponse(msgId, seqNo int, msg serialize.CommonMessage)
 			}
 		}
 		if !processed {
-			if m.Warnings != nil {
-				m.Warnings <- errors.New("got nonsystem message from server: " + reflect.TypeOf(message).String())
-			}
+			m.warn(errors.New("got nonsystem message from server: " + reflect.TypeOf(message).String()))
 		}
 	}
 
 	if (seqNo & 1) != 0 {
-		_, err := m.MakeRequest(&serialize.MsgsAck{MsgIds: []int64{int64(msgId)}})
-		if err != nil {
-			return errors.Wrap(err, "sending ack")
-		}
+		// подтверждения копятся и уходят одним msgs_ack вместе со следующей пачкой сообщений
+		m.outgoing.pushAck(int64(msgId))
 	}
 
 	return nil
@@ -393,21 +755,11 @@ func (m *MTProto) processResponse(msgId, seqNo int, msg serialize.CommonMessage)
 // ряда вон выходящее)
 func (m *MTProto) tryToProcessErr(e *ErrResponseCode) error {
 	switch e.Message {
-	case "PHONE_MIGRATE_X":
-		newIP, found := m.dclist[e.AdditionalInfo.(int)]
-		if !found {
-			return errors.Wrapf(e, "DC with id %v not found", e.AdditionalInfo)
-		}
-		err := m.Stop()
-		if err != nil {
-			return errors.Wrap(err, "stopping session")
-		}
-
-		m.addr = newIP
-
-		err = m.CreateConnection()
+	case "PHONE_MIGRATE_X", "USER_MIGRATE_X", "NETWORK_MIGRATE_X":
+		dc := e.AdditionalInfo.(int)
+		err := m.switchHomeDC(dc)
 		if err != nil {
-			return errors.Wrap(err, "recreating session")
+			return errors.Wrapf(err, "migrating to dc %v", dc)
 		}
 
 		return nil
@@ -0,0 +1,110 @@
+package mtproto
+
+import (
+	"context"
+	"crypto/rand"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/transport"
+)
+
+// recordingConn запоминает все отправленные пакеты и никогда ничего не отвечает
+type recordingConn struct {
+	mutex   sync.Mutex
+	packets [][]byte
+	written chan struct{}
+}
+
+func newRecordingConn() *recordingConn {
+	return &recordingConn{written: make(chan struct{}, 100)}
+}
+
+func (c *recordingConn) WritePacket(data []byte) error {
+	c.mutex.Lock()
+	c.packets = append(c.packets, data)
+	c.mutex.Unlock()
+	c.written <- struct{}{}
+	return nil
+}
+
+func (c *recordingConn) ReadPacket() ([]byte, error) { select {} }
+func (c *recordingConn) Close() error                { return nil }
+
+// newTestMTProto возвращает клиент с готовым ключом, который пишет в conn. остановить его
+// нужно через Stop()
+func newTestMTProto(t *testing.T, conn transport.Conn) *MTProto {
+	m := &MTProto{
+		conn:                 conn,
+		encrypted:            true,
+		mutex:                &sync.Mutex{},
+		outgoing:             newOutgoingQueue(),
+		responseChannels:     make(map[int64]chan serialize.TL),
+		msgsIdDecodeAsVector: make(map[int64]reflect.Type),
+	}
+	key := make([]byte, 256)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	m.SetAuthKey(key)
+	m.seq.reset(1)
+	m.resetAck()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopRoutines = cancel
+	m.startSending(ctx)
+	return m
+}
+
+func TestMakeRequestContextCancel(t *testing.T) {
+	conn := newRecordingConn()
+	m := newTestMTProto(t, conn)
+	defer m.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-conn.written
+		cancel()
+	}()
+
+	_, err := m.MakeRequestContext(ctx, &PingParams{PingID: 1})
+	if errors.Cause(err) != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// rpc_drop_answer уходит в фоне
+	select {
+	case <-conn.written:
+	case <-time.After(time.Second):
+		t.Fatal("rpc_drop_answer was not sent")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if len(m.responseChannels) != 1 {
+		// остаться должен только сам rpc_drop_answer, который все еще ждет ответа
+		t.Fatalf("expected only rpc_drop_answer to be pending, got %v", len(m.responseChannels))
+	}
+}
+
+func TestMakeRequestContextAlreadyDone(t *testing.T) {
+	conn := newRecordingConn()
+	m := newTestMTProto(t, conn)
+	defer m.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := m.MakeRequestContext(ctx, &PingParams{PingID: 1})
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(conn.packets) != 0 {
+		t.Fatalf("request was sent with a cancelled context")
+	}
+}
@@ -1,12 +1,14 @@
 package mtproto
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
 	"github.com/xelaj/go-dry"
 
 	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/transport"
 	"github.com/lonesta/mtproto/utils"
 )
 
@@ -37,35 +39,108 @@ func (m *MTProto) resetAck() {
 
 // получает текущий идентификатор сессии
 func (m *MTProto) GetSessionID() int64 {
-	return m.sessionId
+	return m.seq.sessionID()
 }
 
-// Получает lastSeqNo
+// GetLastSeqNo возвращает seqno, который получит следующее сообщение без подтверждения
 func (m *MTProto) GetLastSeqNo() int32 {
-	return m.lastSeqNo
+	return m.seq.lastSeqNo()
 }
 
-// получает соль
+// DC возвращает номер датацентра, к которому подключен клиент, или 0, если он неизвестен
+func (m *MTProto) DC() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.dc != 0 {
+		return m.dc
+	}
+	for id, addr := range m.dclist {
+		if addr == m.addr {
+			return id
+		}
+	}
+	// через прокси адрес у всех датацентров один, но прокси знает, куда подключаться
+	return transport.ProxyDC(m.transport)
+}
+
+// SetUserID запоминает, какому пользователю принадлежит сессия. сохраняется вместе с сессией
+func (m *MTProto) SetUserID(id int64) {
+	m.mutex.Lock()
+	m.userID = id
+	m.mutex.Unlock()
+}
+
+// получает соль, которая действует сейчас
 func (m *MTProto) GetServerSalt() int64 {
-	return m.serverSalt
+	return m.currentSalt(m.serverTime())
 }
 
 // получает ключ авторизации
 func (m *MTProto) GetAuthKey() []byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	return m.authKey
 }
 
+// getAuthKeyHash возвращает хеш ключа, которым сейчас шифруются сообщения
+func (m *MTProto) getAuthKeyHash() []byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.authKeyHash
+}
+
+// endpoint возвращает, куда и как подключаться. меняется при миграции, см. switchHomeDC
+func (m *MTProto) endpoint() (transport.Transport, string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.transport, m.addr
+}
+
+// connection возвращает текущее соединение и функцию, которая останавливает его горутины. оба
+// меняются при переподключении из другой горутины, поэтому напрямую conn и stopRoutines не читаются
+func (m *MTProto) connection() (transport.Conn, context.CancelFunc) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.conn, m.stopRoutines
+}
+
+// isEncrypted и setEncrypted нужны потому, что encrypted проверяется при каждой отправке, в том
+// числе из фоновых горутин
+func (m *MTProto) isEncrypted() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.encrypted
+}
+
+func (m *MTProto) setEncrypted(encrypted bool) {
+	m.mutex.Lock()
+	m.encrypted = encrypted
+	m.mutex.Unlock()
+}
+
 func (m *MTProto) SetAuthKey(key []byte) {
-	m.authKey = key
-	m.authKeyHash = utils.AuthKeyHash(m.authKey)
+	hash := utils.AuthKeyHash(key)
+	m.mutex.Lock()
+	m.authKey, m.authKeyHash = key, hash
+	m.mutex.Unlock()
 }
 
 func (m *MTProto) MakeRequest(msg serialize.TL) (serialize.TL, error) {
-	return m.makeRequest(msg, nil)
+	return m.makeRequest(context.Background(), msg, nil)
+}
+
+// MakeRequestContext то же самое, что и MakeRequest, но перестает ждать ответ, как только ctx
+// отменяется. сервер при этом просят не присылать ответ через rpc_drop_answer
+func (m *MTProto) MakeRequestContext(ctx context.Context, msg serialize.TL) (serialize.TL, error) {
+	return m.makeRequest(ctx, msg, nil)
 }
 
 func (m *MTProto) MakeRequestAsSlice(msg serialize.TL, as reflect.Type) (serialize.TL, error) {
-	return m.makeRequest(msg, as)
+	return m.makeRequest(context.Background(), msg, as)
+}
+
+func (m *MTProto) MakeRequestAsSliceContext(ctx context.Context, msg serialize.TL, as reflect.Type) (serialize.TL, error) {
+	return m.makeRequest(ctx, msg, as)
 }
 
 func (m *MTProto) recoverGoroutine() {
@@ -79,6 +154,13 @@ func (m *MTProto) recoverGoroutine() {
 	}
 }
 
+// warn пишет ошибку в Warnings, если кто-то их слушает
+func (m *MTProto) warn(err error) {
+	if m.Warnings != nil {
+		m.Warnings <- err
+	}
+}
+
 func (m *MTProto) AddCustomServerRequestHandler(handler customHandlerFunc) {
 	m.serverRequestHandlers = append(m.serverRequestHandlers, handler)
 }
@@ -2,7 +2,6 @@ package mtproto
 
 import (
 	"encoding/binary"
-	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
@@ -10,14 +9,9 @@ import (
 	"github.com/lonesta/mtproto/serialize"
 )
 
-const (
-	// если длина пакета больше или равн 127 слов, то кодируем 4 байтами, 1 это магическое число, оставшиеся 3 — дилна
-	magicValueSizeMoreThanSingleByte = 0x7f
-)
-
 func isNullableResponse(t serialize.TL) bool {
 	switch t.(type) {
-	case /**serialize.Ping,*/ *serialize.Pong, *serialize.MsgsAck:
+	case /**serialize.Ping,*/ *serialize.Pong, *serialize.MsgsAck, *HttpWaitParams:
 		return true
 	default:
 		return false
@@ -26,6 +20,15 @@ func isNullableResponse(t serialize.TL) bool {
 
 const (
 	readTimeout = 300 * time.Second
+
+	// сколько сервер может держать http запрос, если ему нечего ответить
+	httpWaitMaxWait = 25 * time.Second
+
+	// сколько ждать ответа на rpc_drop_answer, после этого просто забываем про запрос
+	dropAnswerTimeout = 10 * time.Second
+
+	// сколько ждать ответа на auth.bindTempAuthKey
+	bindTempKeyTimeout = 30 * time.Second
 )
 
 func CatchResponseErrorCode(data []byte) error {
@@ -52,7 +55,7 @@ func (m *MTProto) decodeRecievedData(data []byte) (serialize.CommonMessage, erro
 	var msg serialize.CommonMessage
 
 	if IsPacketEncrypted(data) {
-		msg, err = serialize.DeserializeEncryptedMessage(data, m.GetAuthKey())
+		msg, err = serialize.DeserializeEncryptedMessage(data, m.decryptionKey(data[:serialize.LongLen]))
 	} else {
 		msg, err = serialize.DeserializeUnencryptedMessage(data)
 	}
@@ -60,11 +63,9 @@ func (m *MTProto) decodeRecievedData(data []byte) (serialize.CommonMessage, erro
 		return nil, errors.Wrap(err, "parsing message")
 	}
 
-	m.msgId = int64(msg.GetMsgID())
-	m.seqNo = int32(msg.GetSeqNo())
-	mod := m.msgId & 3
-	if mod != 1 && mod != 3 {
-		return nil, fmt.Errorf("Wrong bits of message_id: %d", mod)
+	err = m.checkPacket(msg)
+	if err != nil {
+		return nil, err
 	}
 
 	return msg, nil
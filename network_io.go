@@ -2,50 +2,44 @@ package mtproto
 
 import (
 	"context"
-	"encoding/binary"
-	"fmt"
 	"reflect"
 	"strconv"
 	"time"
 
-	"github.com/k0kubun/pp"
 	"github.com/lonesta/mtproto/serialize"
-	"github.com/lonesta/mtproto/utils"
 	"github.com/pkg/errors"
 	"github.com/xelaj/errs"
-	"github.com/xelaj/go-dry"
 )
 
-func (m *MTProto) sendPacketNew(request serialize.TL, expectVector reflect.Type) (chan serialize.TL, error) {
-	resp := make(chan serialize.TL)
+func (m *MTProto) sendPacketNew(ctx context.Context, request serialize.TL, expectVector reflect.Type) (chan serialize.TL, int64, error) {
+	// буфер нужен, что бы ответ можно было записать, даже если его уже не ждут (например, отменили
+	// контекст)
+	resp := make(chan serialize.TL, 1)
 	if m.serviceModeActivated {
 		resp = m.serviceChannel
 	}
-	var data []byte
-	var err error
-	var msgID = utils.GenerateMessageId()
+	encrypted := m.isEncrypted()
+	// у нешифрованных сообщений seqno нет вообще
+	requireToAck := encrypted && MessageRequireToAck(request)
+	msgID, seqNo := m.nextMessage(requireToAck)
+
+	// содержимое некоторых запросов зависит от их же msg_id, см. bindTempAuthKeyRequest
+	if r, ok := request.(interface{ setMsgID(int64) }); ok {
+		r.setMsgID(msgID)
+	}
 
 	// может мы ожидаем вектор, см. erialize.RpcResult для понимания
 	if expectVector != nil {
+		m.mutex.Lock()
 		m.msgsIdDecodeAsVector[msgID] = expectVector
+		m.mutex.Unlock()
 	}
 
-	if m.encrypted {
-		requireToAck := false
-		if MessageRequireToAck(request) {
-			m.mutex.Lock()
+	if encrypted {
+		if requireToAck {
+			m.idsToAckMutex.Lock()
 			m.waitAck(msgID)
-			m.mutex.Unlock()
-			requireToAck = true
-		}
-
-		data, err = (&serialize.EncryptedMessage{
-			Msg:         request.Encode(),
-			MsgID:       msgID,
-			AuthKeyHash: m.authKeyHash,
-		}).Serialize(m, requireToAck)
-		if err != nil {
-			return nil, errors.Wrap(err, "serializing message")
+			m.idsToAckMutex.Unlock()
 		}
 
 		if !isNullableResponse(request) {
@@ -55,43 +49,42 @@ func (m *MTProto) sendPacketNew(request serialize.TL, expectVector reflect.Type)
 			m.mutex.Unlock()
 		} else {
 			// ответов на TL_Ack, TL_Pong и пр. не требуется
-			go func() {
-				// горутина, т.к. мы ПРЯМО СЕЙЧАС из resp не читаем
-				resp <- &serialize.Null{}
-			}()
+			resp <- &serialize.Null{}
 		}
-		// этот кусок не часть кодирования так что делаем при отправке
-		m.lastSeqNo += 2
-	} else {
-		data, _ = (&serialize.UnencryptedMessage{ //nolint: errcheck нешифрованое не отправляет ошибки
-			Msg:   request.Encode(),
-			MsgID: msgID,
-		}).Serialize(m)
-	}
 
-	//? https://core.telegram.org/mtproto/mtproto-transports#intermediate
-	size := make([]byte, 4)
-	binary.LittleEndian.PutUint32(size, uint32(len(data)))
-	_, err = m.conn.Write(size)
-	if err != nil {
-		return nil, errors.Wrap(err, "sending data")
+		// шифруется и уходит сообщение уже из очереди, см. startSending. в журнал оно попадает
+		// сразу, что бы при переподключении его можно было отправить заново с тем же msg_id
+		msg := &outgoingMessage{
+			msgID:        msgID,
+			seqNo:        seqNo,
+			body:         m.encodeRequest(ctx, request),
+			requireToAck: requireToAck,
+		}
+		m.logSent(msg)
+		m.outgoing.push(msg)
+
+		return resp, msgID, nil
 	}
 
-	//? https://core.telegram.org/mtproto/mtproto-transports#abridged
-	// _, err := m.conn.Write(utils.PacketLengthMTProtoCompatible(data))
-	// dry.PanicIfErr(err)
-	_, err = m.conn.Write(data)
+	data, _ := (&serialize.UnencryptedMessage{ //nolint: errcheck нешифрованое не отправляет ошибки
+		Msg:   request.Encode(),
+		MsgID: msgID,
+	}).Serialize(m)
+
+	conn, _ := m.connection()
+	err := conn.WritePacket(data)
 	if err != nil {
-		return nil, errors.Wrap(err, "sending request")
+		return nil, 0, errors.Wrap(err, "sending request")
 	}
 
-	return resp, nil
+	return resp, msgID, nil
 }
 
 func (m *MTProto) writeRPCResponse(msgID int, data serialize.TL) error {
 	m.mutex.Lock()
 	v, ok := m.responseChannels[int64(msgID)]
 	if !ok {
+		m.mutex.Unlock()
 		return errs.NotFound("msgID", strconv.Itoa(msgID))
 	}
 
@@ -103,47 +96,23 @@ func (m *MTProto) writeRPCResponse(msgID int, data serialize.TL) error {
 }
 
 func (m *MTProto) readFromConn(ctx context.Context) (data []byte, err error) {
-	err = m.conn.SetReadDeadline(time.Now().Add(readTimeout)) // возможно поможет???
-	dry.PanicIfErr(err)
-
-	reader := dry.NewCancelableReader(ctx, m.conn)
-	// https://core.telegram.org/mtproto/mtproto-transports#abridged
-	// что делаем:
-	// в conn есть определенный буффер, все что телега присылает, мы сохраняем в буффере, и потом через
-	// Read читаем. т.к. маленькие пакеты (до 127 байт)  кодируют длину в 1 байт, а побольше в 4, то
-	// мы читаем сначала 1 байт, смотрим, это 0xef или нет, если да, то читаем оставшиеся 3 байта и получаем длину
-	//firstByte, err := reader.ReadByte()
-	//dry.PanicIfErr(err)
-	//
-	//sizeInBytes, err := utils.GetPacketLengthMTProtoCompatible([]byte{firstByte})
-	//if err == utils.ErrPacketSizeIsBigger {
-	//	restOfSize := make([]byte, 3)
-	//	n, err := reader.Read(restOfSize)
-	//	dry.PanicIfErr(err)
-	//	dry.PanicIf(n != 3, fmt.Sprintf("expected read 3 bytes, got %d", n))
-	//
-	//	sizeInBytes, _ = utils.GetPacketLengthMTProtoCompatible(append([]byte{firstByte}, restOfSize...))
-	//
-	//	pp.Println(firstByte, restOfSize, sizeInBytes)
-	//}
-
-	// https://core.telegram.org/mtproto/mtproto-transports#intermediate
-	sizeInBytes := make([]byte, 4)
-	n, err := reader.Read(sizeInBytes)
-	if err != nil {
-		pp.Println(sizeInBytes, err)
-		return nil, errors.Wrap(err, "reading length")
-	}
-	if n != 4 {
-		return nil, fmt.Errorf("size is not length of int32, expected 4 bytes, got %d", n)
+	conn, _ := m.connection()
+	if d, ok := conn.(interface{ SetReadDeadline(time.Time) error }); ok {
+		err = d.SetReadDeadline(time.Now().Add(readTimeout)) // возможно поможет???
+		if err != nil {
+			return nil, errors.Wrap(err, "setting read deadline")
+		}
 	}
 
-	size := binary.LittleEndian.Uint32(sizeInBytes)
-	// читаем сами данные
-	data = make([]byte, int(size))
-	n, err = reader.Read(data)
-	dry.PanicIfErr(err)
-	dry.PanicIf(n != int(size), fmt.Sprintf("expected read %d bytes, got %d", size, n))
+	// как именно пакет достается из соединения решает транспорт
+	// https://core.telegram.org/mtproto/mtproto-transports
+	data, err = conn.ReadPacket()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, errors.Wrap(err, "reading packet")
+	}
 
 	return data, nil
 }
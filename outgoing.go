@@ -0,0 +1,255 @@
+package mtproto
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+// все зашифрованные сообщения уходят через очередь: все, что накопилось за outgoingFlushDelay,
+// упаковывается в msg_container, а подтверждения собираются в один msgs_ack
+// https://core.telegram.org/mtproto/service_messages#simple-container
+
+const (
+	// сколько ждать, пока в очереди накопятся еще сообщения
+	outgoingFlushDelay = 5 * time.Millisecond
+
+	// ограничения на размер контейнера. сообщения больше maxContainerSize уходят отдельно
+	maxContainerMessages = 1020
+	maxContainerSize     = 1 << 15
+
+	// в одном msgs_ack может быть не больше 8192 id
+	maxAcksPerMessage = 8192
+
+	// msg_id + seqno + длина перед каждым сообщением в контейнере
+	containerItemHeaderLen = serialize.LongLen + serialize.WordLen + serialize.WordLen
+)
+
+// msg_id и seqno выдаются сообщению сразу, когда оно ставится в очередь, см. msgSequence
+type outgoingMessage struct {
+	msgID        int64
+	seqNo        int32
+	body         []byte
+	requireToAck bool
+}
+
+type outgoingQueue struct {
+	mutex    sync.Mutex
+	messages []*outgoingMessage
+	size     int
+	acks     []int64
+
+	// в wake пишется, когда в очереди что-то появилось, в full — когда пора отправлять не дожидаясь
+	// таймера. оба с буфером 1, что бы не блокировать тех, кто кладет в очередь
+	wake chan struct{}
+	full chan struct{}
+}
+
+func newOutgoingQueue() *outgoingQueue {
+	return &outgoingQueue{
+		wake: make(chan struct{}, 1),
+		full: make(chan struct{}, 1),
+	}
+}
+
+func (q *outgoingQueue) push(msg *outgoingMessage) {
+	q.mutex.Lock()
+	q.messages = append(q.messages, msg)
+	q.size += containerItemHeaderLen + len(msg.body)
+	isFull := len(q.messages) >= maxContainerMessages || q.size >= maxContainerSize
+	q.mutex.Unlock()
+
+	q.notify(isFull)
+}
+
+func (q *outgoingQueue) pushAck(msgID int64) {
+	q.mutex.Lock()
+	q.acks = append(q.acks, msgID)
+	isFull := len(q.acks) >= maxAcksPerMessage
+	q.mutex.Unlock()
+
+	q.notify(isFull)
+}
+
+func (q *outgoingQueue) notify(isFull bool) {
+	c := q.wake
+	if isFull {
+		c = q.full
+	}
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
+
+// take забирает из очереди все, что в ней есть
+func (q *outgoingQueue) take() (messages []*outgoingMessage, acks []int64) {
+	q.mutex.Lock()
+	messages, acks = q.messages, q.acks
+	q.messages, q.acks, q.size = nil, nil, 0
+	q.mutex.Unlock()
+	return messages, acks
+}
+
+// dropMessages выкидывает из очереди все сообщения, но оставляет подтверждения, и возвращает
+// выкинутые. нужно при переподключении: эти сообщения точно не дошли до сервера
+func (q *outgoingQueue) dropMessages() []*outgoingMessage {
+	q.mutex.Lock()
+	messages := q.messages
+	q.messages, q.size = nil, 0
+	q.mutex.Unlock()
+	return messages
+}
+
+// queued проверяет, лежит ли сообщение в очереди
+func (q *outgoingQueue) queued(msgID int64) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for _, msg := range q.messages {
+		if msg.msgID == msgID {
+			return true
+		}
+	}
+	return false
+}
+
+// packMessages разбивает сообщения на группы, каждая из которых влезает в один контейнер
+func packMessages(messages []*outgoingMessage) [][]*outgoingMessage {
+	packs := make([][]*outgoingMessage, 0, 1)
+	var current []*outgoingMessage
+	size := 0
+	for _, msg := range messages {
+		itemSize := containerItemHeaderLen + len(msg.body)
+		if len(current) > 0 && (len(current) >= maxContainerMessages || size+itemSize > maxContainerSize) {
+			packs = append(packs, current)
+			current, size = nil, 0
+		}
+		current = append(current, msg)
+		size += itemSize
+	}
+	if len(current) > 0 {
+		packs = append(packs, current)
+	}
+	return packs
+}
+
+// startSending отправляет все, что накопилось в очереди
+func (m *MTProto) startSending(ctx context.Context) {
+	m.routineswg.Add(1)
+	go func() {
+		defer m.recoverGoroutine()
+		for {
+			select {
+			case <-ctx.Done():
+				m.routineswg.Done()
+				return
+			case <-m.outgoing.full:
+			case <-m.outgoing.wake:
+				// ждем, вдруг еще кто-то захочет что-то отправить
+				select {
+				case <-ctx.Done():
+					m.routineswg.Done()
+					return
+				case <-m.outgoing.full:
+				case <-time.After(outgoingFlushDelay):
+				}
+			}
+
+			err := m.flushOutgoing()
+			if err != nil {
+				// если соединение умерло, то читающая горутина это тоже заметит и переподключится,
+				// а все запросы, которые ждут ответа, уйдут заново
+				m.warn(errors.Wrap(err, "sending queued messages"))
+			}
+		}
+	}()
+}
+
+func (m *MTProto) flushOutgoing() error {
+	m.sendMutex.Lock()
+	defer m.sendMutex.Unlock()
+
+	messages, acks := m.outgoing.take()
+	// если временный ключ сменился, пока сообщения лежали в очереди, то их msg_id и seqno выданы
+	// еще в старой сессии. такие запросы отправятся заново, уже в новой
+	fresh := messages[:0]
+	var stale []int64
+	for _, msg := range messages {
+		if m.seq.inSession(msg.msgID) {
+			fresh = append(fresh, msg)
+		} else {
+			stale = append(stale, msg.msgID)
+		}
+	}
+	messages = fresh
+	m.resendMessages(stale)
+
+	for len(acks) > 0 {
+		n := len(acks)
+		if n > maxAcksPerMessage {
+			n = maxAcksPerMessage
+		}
+		msgID, seqNo := m.nextMessage(false)
+		messages = append(messages, &outgoingMessage{
+			msgID: msgID,
+			seqNo: seqNo,
+			body:  (&serialize.MsgsAck{MsgIds: acks[:n]}).Encode(),
+		})
+		acks = acks[n:]
+	}
+
+	conn, _ := m.connection()
+	for _, pack := range packMessages(messages) {
+		data, err := m.serializePack(pack)
+		if err != nil {
+			return errors.Wrap(err, "serializing message")
+		}
+
+		err = conn.WritePacket(data)
+		if err != nil {
+			return errors.Wrap(err, "sending request")
+		}
+	}
+
+	return nil
+}
+
+// serializePack шифрует одно сообщение как есть, а несколько заворачивает в msg_container
+func (m *MTProto) serializePack(pack []*outgoingMessage) ([]byte, error) {
+	if len(pack) == 1 {
+		msg := pack[0]
+		return (&serialize.EncryptedMessage{
+			Msg:         msg.body,
+			MsgID:       msg.msgID,
+			SeqNo:       msg.seqNo,
+			AuthKeyHash: m.getAuthKeyHash(),
+		}).Serialize(m)
+	}
+
+	container := make(serialize.MessageContainer, len(pack))
+	msgIDs := make([]int64, len(pack))
+	for i, msg := range pack {
+		msgIDs[i] = msg.msgID
+		container[i] = &serialize.EncryptedMessage{
+			Msg:   msg.body,
+			MsgID: msg.msgID,
+			SeqNo: msg.seqNo,
+		}
+	}
+
+	// сам контейнер подтверждать не нужно, подтверждаются сообщения внутри. msg_id контейнера
+	// должен быть больше, чем у всех вложенных сообщений, поэтому генерируем его только сейчас
+	containerID, seqNo := m.nextMessage(false)
+	m.rememberContainer(containerID, msgIDs)
+
+	return (&serialize.EncryptedMessage{
+		Msg:         container.Encode(),
+		MsgID:       containerID,
+		SeqNo:       seqNo,
+		AuthKeyHash: m.getAuthKeyHash(),
+	}).Serialize(m)
+}
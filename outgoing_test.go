@@ -0,0 +1,93 @@
+package mtproto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+func TestPackMessages(t *testing.T) {
+	small := func() *outgoingMessage { return &outgoingMessage{body: make([]byte, 16)} }
+	big := &outgoingMessage{body: make([]byte, maxContainerSize)}
+
+	cases := []struct {
+		name  string
+		in    []*outgoingMessage
+		sizes []int
+	}{
+		{"single", []*outgoingMessage{small()}, []int{1}},
+		{"few", []*outgoingMessage{small(), small(), small()}, []int{3}},
+		{"big one goes alone", []*outgoingMessage{small(), big, small()}, []int{1, 1, 1}},
+	}
+
+	many := make([]*outgoingMessage, maxContainerMessages+1)
+	for i := range many {
+		many[i] = &outgoingMessage{}
+	}
+	cases = append(cases, struct {
+		name  string
+		in    []*outgoingMessage
+		sizes []int
+	}{"too many", many, []int{maxContainerMessages, 1}})
+
+	for _, c := range cases {
+		packs := packMessages(c.in)
+		if len(packs) != len(c.sizes) {
+			t.Errorf("%v: got %v packs, want %v", c.name, len(packs), len(c.sizes))
+			continue
+		}
+		for i, pack := range packs {
+			if len(pack) != c.sizes[i] {
+				t.Errorf("%v: pack %v has %v messages, want %v", c.name, i, len(pack), c.sizes[i])
+			}
+		}
+	}
+}
+
+func TestOutgoingBatching(t *testing.T) {
+	conn := newRecordingConn()
+	m := newTestMTProto(t, conn)
+	defer m.Stop()
+
+	// все, что положили в очередь почти одновременно, должно уйти одним пакетом
+	for i := 0; i < 10; i++ {
+		_, _, err := m.sendPacketNew(context.Background(), &PingParams{PingID: int64(i)}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.outgoing.pushAck(int64(i))
+	}
+
+	select {
+	case <-conn.written:
+	case <-time.After(time.Second):
+		t.Fatal("queue was not flushed")
+	}
+	select {
+	case <-conn.written:
+		t.Fatal("messages were sent in more than one packet")
+	case <-time.After(10 * outgoingFlushDelay):
+	}
+
+	// 10 пингов с подтверждением, msgs_ack и сам контейнер seqno не увеличивают
+	if m.GetLastSeqNo() != 10*2 {
+		t.Errorf("unexpected seqno after flush: %v", m.GetLastSeqNo())
+	}
+}
+
+func TestOutgoingFlushWhenFull(t *testing.T) {
+	q := newOutgoingQueue()
+	for i := 0; i < maxContainerMessages; i++ {
+		q.push(&outgoingMessage{body: (&serialize.MsgsAck{}).Encode()})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	select {
+	case <-q.full:
+	case <-ctx.Done():
+		t.Fatal("full queue did not ask for flush")
+	}
+}
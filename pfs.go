@@ -0,0 +1,372 @@
+package mtproto
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	ige "github.com/lonesta/mtproto/aes_ige"
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+// perfect forward secrecy: весь трафик шифруется временным ключом, который привязан к постоянному
+// через auth.bindTempAuthKey. временный ключ нигде не сохраняется и меняется до того, как истечет,
+// так что если постоянный ключ когда-нибудь утечет, записанный трафик им все равно не расшифровать
+// https://core.telegram.org/api/pfs
+
+// BindTempAuthKeyParams это auth.bindTempAuthKey. он объявлен здесь, а не в пакете telegram, потому
+// что без привязки временного ключа клиент вообще не может делать запросы
+type BindTempAuthKeyParams struct {
+	PermAuthKeyID    int64
+	Nonce            int64
+	ExpiresAt        int32
+	EncryptedMessage []byte
+}
+
+func (_ *BindTempAuthKeyParams) CRC() uint32 {
+	return 0xcdd42a05
+}
+
+func (t *BindTempAuthKeyParams) Encode() []byte {
+	buf := serialize.NewEncoder()
+	buf.PutUint(t.CRC())
+	buf.PutLong(t.PermAuthKeyID)
+	buf.PutLong(t.Nonce)
+	buf.PutInt(t.ExpiresAt)
+	buf.PutMessage(t.EncryptedMessage)
+	return buf.Result()
+}
+
+func (t *BindTempAuthKeyParams) DecodeFrom(d *serialize.Decoder) {
+	t.PermAuthKeyID = d.PopLong()
+	t.Nonce = d.PopLong()
+	t.ExpiresAt = d.PopInt()
+	t.EncryptedMessage = d.PopMessage()
+}
+
+// bindTempAuthKeyRequest шифрует bind_auth_key_inner только когда становится известен msg_id
+// запроса: он должен совпадать с msg_id внутри зашифрованного сообщения
+type bindTempAuthKeyRequest struct {
+	BindTempAuthKeyParams
+	inner   *serialize.BindAuthKeyInner
+	permKey []byte
+}
+
+func (r *bindTempAuthKeyRequest) setMsgID(msgID int64) {
+	r.EncryptedMessage = encryptBindMessage(r.inner, r.permKey, msgID)
+}
+
+// encryptBindMessage шифрует inner постоянным ключом по схеме MTProto 1.0. вместо соли и id сессии
+// идут случайные байты, seqno всегда 0
+func encryptBindMessage(inner *serialize.BindAuthKeyInner, permKey []byte, msgID int64) []byte {
+	body := inner.Encode()
+
+	buf := serialize.NewEncoder()
+	random := make([]byte, serialize.Int128Len)
+	_, _ = rand.Read(random)
+	buf.PutRawBytes(random)
+	buf.PutLong(msgID)
+	buf.PutInt(0)
+	buf.PutInt(int32(len(body)))
+	buf.PutRawBytes(body)
+	plain := buf.Result()
+
+	encrypted, err := ige.Encrypt(plain, permKey)
+	if err != nil {
+		// ключ всегда 256 байт, так что ошибки тут быть не может
+		panic(err)
+	}
+
+	res := make([]byte, 0, serialize.LongLen+16+len(encrypted))
+	res = append(res, utils.AuthKeyHash(permKey)...)
+	res = append(res, ige.MessageKey(plain)...)
+	return append(res, encrypted...)
+}
+
+// tempKeyNeedsRenewal показывает, что временного ключа нет, или что он скоро истечет. новый ключ
+// создается, когда от времени жизни старого осталась десятая часть
+func (m *MTProto) tempKeyNeedsRenewal() bool {
+	renewAt, ok := m.tempKeyRenewAt()
+	return !ok || time.Now().After(renewAt)
+}
+
+// tempKeyRenewAt возвращает, когда пора менять текущий временный ключ. ok будет false, если ключа
+// нет. ключ меняется из отдельной горутины, поэтому время читается под мьютексом
+func (m *MTProto) tempKeyRenewAt() (renewAt time.Time, ok bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.tempKeyExpiresAt.IsZero() {
+		return time.Time{}, false
+	}
+	return m.tempKeyExpiresAt.Add(-m.tempKeyTTL / 10), true
+}
+
+func (m *MTProto) setTempKeyExpiresAt(t time.Time) {
+	m.mutex.Lock()
+	m.tempKeyExpiresAt = t
+	m.mutex.Unlock()
+}
+
+// resetTempKey забывает временный ключ, например после того, как загрузили другой постоянный
+func (m *MTProto) resetTempKey() {
+	m.mutex.Lock()
+	m.permAuthKey, m.permAuthKeyHash = nil, nil
+	m.prevAuthKey, m.prevAuthKeyHash, m.prevSessionID = nil, nil, 0
+	m.tempKeyExpiresAt = time.Time{}
+	m.mutex.Unlock()
+}
+
+// makeTempAuthKey создает временный ключ, привязывает его к постоянному и начинает шифровать им
+// трафик. постоянный ключ к этому моменту уже должен быть
+func (m *MTProto) makeTempAuthKey() error {
+	m.mutex.Lock()
+	if m.permAuthKey == nil {
+		m.permAuthKey, m.permAuthKeyHash = m.authKey, m.authKeyHash
+	}
+	permKey, permKeyHash := m.permAuthKey, m.permAuthKeyHash
+	// соединение новое, ответов, зашифрованных прошлым ключом, по нему уже не придет
+	m.prevAuthKey, m.prevAuthKeyHash, m.prevSessionID = nil, nil, 0
+	m.mutex.Unlock()
+
+	expiresIn := int32(m.tempKeyTTL / time.Second)
+	expiresAt := time.Now().Add(m.tempKeyTTL)
+
+	// обмен ключами идет без шифрования
+	m.setEncrypted(false)
+	tempKey, salt, err := m.createAuthKey(expiresIn)
+	if err != nil {
+		// пока нового ключа нет, шифруем тем, что было
+		m.setEncrypted(true)
+		return errors.Wrap(err, "creating temporary key")
+	}
+
+	m.SetAuthKey(tempKey)
+	m.setServerSalt(salt)
+	// у нового ключа своя сессия
+	m.seq.reset(utils.GenerateSessionID())
+	m.setEncrypted(true)
+
+	nonce := make([]byte, serialize.LongLen)
+	_, _ = rand.Read(nonce)
+	inner := &serialize.BindAuthKeyInner{
+		Nonce:         int64(binary.LittleEndian.Uint64(nonce)),
+		TempAuthKeyID: int64(binary.LittleEndian.Uint64(m.getAuthKeyHash())),
+		PermAuthKeyID: int64(binary.LittleEndian.Uint64(permKeyHash)),
+		TempSessionID: m.GetSessionID(),
+		ExpiresAt:     int32(expiresAt.Unix()),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bindTempKeyTimeout)
+	defer cancel()
+	resp, err := m.MakeRequestContext(ctx, &bindTempAuthKeyRequest{
+		BindTempAuthKeyParams: BindTempAuthKeyParams{
+			PermAuthKeyID: inner.PermAuthKeyID,
+			Nonce:         inner.Nonce,
+			ExpiresAt:     inner.ExpiresAt,
+		},
+		inner:   inner,
+		permKey: permKey,
+	})
+	if err != nil {
+		return errors.Wrap(err, "binding temporary key")
+	}
+	if res, _ := resp.(*serialize.Bool); res == nil || !res.Value {
+		return errors.New("server refused to bind temporary key")
+	}
+
+	m.mutex.Lock()
+	onBound := m.onTempKeyBound
+	m.mutex.Unlock()
+	if onBound != nil {
+		err = onBound(m)
+		if err != nil {
+			return errors.Wrap(err, "initializing connection with temporary key")
+		}
+	}
+
+	m.setTempKeyExpiresAt(expiresAt)
+	return nil
+}
+
+// SetTempKeyBoundHandler задает функцию, которая вызывается каждый раз после того, как привязан
+// новый временный ключ. с новым ключом сервер считает соединение новым, поэтому функция должна
+// заново отправить invokeWithLayer(initConnection(...)). если она вернула ошибку, то соединение
+// создается заново вместе с ключом. соединения с другими датацентрами, открытые после вызова,
+// получают ту же функцию
+func (m *MTProto) SetTempKeyBoundHandler(f func(conn *MTProto) error) {
+	m.mutex.Lock()
+	m.onTempKeyBound = f
+	m.mutex.Unlock()
+}
+
+var errTempKeyDCUnknown = errors.New("datacenter of temporary key is unknown")
+
+// tempKeyDC возвращает номер датацентра для p_q_inner_data_temp_dc: у тестовых серверов к нему
+// прибавляется 10000, а у media-only адресов он отрицательный
+func (m *MTProto) tempKeyDC() (int32, error) {
+	dc := m.DC()
+	if dc == 0 {
+		return 0, errTempKeyDCUnknown
+	}
+	if m.config.TestServer {
+		dc += 10000
+	}
+	if m.config.MediaOnly {
+		dc = -dc
+	}
+	return int32(dc), nil
+}
+
+// startTempKeyRotation меняет временный ключ, когда текущий скоро истечет. новый ключ создается и
+// привязывается по отдельному соединению, а это переключается на него, не переподключаясь, см.
+// rotateTempKey. если сменить ключ так не вышло, то клиент переподключается и создает ключ заново
+func (m *MTProto) startTempKeyRotation(ctx context.Context) {
+	// в routineswg горутина не входит: смена ключа занимает время, а Stop ждать ее не должен. после
+	// отмены ctx она уже ничего не меняет
+	go func() {
+		defer m.recoverGoroutine()
+		for {
+			renewAt, ok := m.tempKeyRenewAt()
+			if !ok {
+				return
+			}
+			timer := time.NewTimer(time.Until(renewAt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			err := m.rotateTempKey(ctx)
+			switch {
+			case err == nil:
+			case ctx.Err() != nil:
+				// соединение закрыли, при подключении ключ создастся заново, если будет нужно
+				return
+			default:
+				m.warn(errors.Wrap(err, "rotating temporary key"))
+				go m.reconnect()
+				return
+			}
+		}
+	}()
+}
+
+// rotateTempKey создает следующий временный ключ на отдельном соединении с тем же датацентром и
+// привязывает его к постоянному. сервер разрешает привязать к одному постоянному ключу несколько
+// временных, так что запросы, которые уже ушли со старым ключом, получат ответы как обычно, а новые
+// уходят уже с новым ключом и в новой сессии
+func (m *MTProto) rotateTempKey(ctx context.Context) error {
+	helper, err := m.newTempKeyConn()
+	if err != nil {
+		return err
+	}
+	err = helper.CreateConnection()
+	if err != nil {
+		return errors.Wrap(err, "making temporary key on separate connection")
+	}
+	// на вспомогательном соединении больше ничего не отправляется, ключ остается привязанным
+	defer func() {
+		if err := helper.Disconnect(); err != nil {
+			m.warn(errors.Wrap(err, "closing connection used for temporary key"))
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.switchTempKey(helper.GetAuthKey(), helper.GetServerSalt(), helper.tempKeyExpiry())
+
+	m.mutex.Lock()
+	onBound := m.onTempKeyBound
+	m.mutex.Unlock()
+	if onBound != nil {
+		err = onBound(m)
+		if err != nil {
+			return errors.Wrap(err, "initializing connection with temporary key")
+		}
+	}
+	return nil
+}
+
+// newTempKeyConn собирает соединение с тем же датацентром и тем же постоянным ключом. в
+// хранилище оно ничего не пишет
+func (m *MTProto) newTempKeyConn() (*MTProto, error) {
+	config := m.config
+	config.SessionStorage = NewMemorySessionStorage()
+	config.SessionPassphrase = ""
+	config.AuthKeyFile = ""
+	config.DC = m.DC()
+	config.Transport, config.ServerHost = m.endpoint()
+
+	conn, err := NewMTProto(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "setting up connection for temporary key")
+	}
+	conn.Warnings = m.Warnings
+	conn.RecoverFunc = m.RecoverFunc
+	atomic.StoreInt64(&conn.timeOffset, int64(m.TimeOffset()))
+
+	m.mutex.Lock()
+	permKey := m.permAuthKey
+	m.mutex.Unlock()
+	if permKey == nil {
+		return nil, errors.New("permanent key is unknown")
+	}
+	conn.SetAuthKey(permKey)
+	conn.setEncrypted(true)
+	return conn, nil
+}
+
+func (m *MTProto) tempKeyExpiry() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.tempKeyExpiresAt
+}
+
+// switchTempKey начинает шифровать новым, уже привязанным временным ключом. сообщения в очереди
+// не отправятся, пока ключ меняется, см. flushOutgoing. прошлый ключ и его сессия запоминаются,
+// что бы расшифровать ответы на запросы, которые ушли до смены
+func (m *MTProto) switchTempKey(key []byte, salt int64, expiresAt time.Time) {
+	m.sendMutex.Lock()
+	defer m.sendMutex.Unlock()
+
+	session := m.GetSessionID()
+	m.mutex.Lock()
+	m.prevAuthKey, m.prevAuthKeyHash, m.prevSessionID = m.authKey, m.authKeyHash, session
+	m.tempKeyExpiresAt = expiresAt
+	m.mutex.Unlock()
+
+	m.SetAuthKey(key)
+	m.setServerSalt(salt)
+	m.seq.reset(utils.GenerateSessionID())
+}
+
+// decryptionKey выбирает ключ, которым зашифровано входящее сообщение: текущий или прошлый
+// временный, см. switchTempKey
+func (m *MTProto) decryptionKey(authKeyHash []byte) []byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.prevAuthKey != nil && bytes.Equal(authKeyHash, m.prevAuthKeyHash) {
+		return m.prevAuthKey
+	}
+	return m.authKey
+}
+
+// isKnownSession проверяет, что сообщение пришло в текущую сессию или в сессию прошлого
+// временного ключа
+func (m *MTProto) isKnownSession(session int64) bool {
+	if session == m.GetSessionID() {
+		return true
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.prevSessionID != 0 && session == m.prevSessionID
+}
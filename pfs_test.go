@@ -0,0 +1,210 @@
+package mtproto
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	ige "github.com/lonesta/mtproto/aes_ige"
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/transport"
+	"github.com/lonesta/mtproto/utils"
+)
+
+// decryptFromClient расшифровывает сообщение MTProto 1.0 так, как это делает сервер. ige.Decrypt
+// считает ключи со смещением 8 (сообщения от сервера), поэтому ключ сдвигается на 8 байт
+func decryptFromClient(t *testing.T, data, key, msgKey []byte) []byte {
+	t.Helper()
+	shifted := append(make([]byte, 8), key[:len(key)-8]...)
+	plain, err := ige.Decrypt(data, shifted, msgKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plain
+}
+
+func TestBindTempAuthKeyRequest(t *testing.T) {
+	permKey := make([]byte, 256)
+	_, _ = rand.Read(permKey)
+
+	m := &MTProto{
+		encrypted:            true,
+		mutex:                &sync.Mutex{},
+		outgoing:             newOutgoingQueue(),
+		responseChannels:     make(map[int64]chan serialize.TL),
+		msgsIdDecodeAsVector: make(map[int64]reflect.Type),
+	}
+	m.resetAck()
+
+	inner := &serialize.BindAuthKeyInner{
+		Nonce:         12345,
+		TempAuthKeyID: 1,
+		PermAuthKeyID: 2,
+		TempSessionID: 3,
+		ExpiresAt:     4,
+	}
+	_, msgID, err := m.sendPacketNew(context.Background(), &bindTempAuthKeyRequest{
+		BindTempAuthKeyParams: BindTempAuthKeyParams{PermAuthKeyID: 2, Nonce: 12345, ExpiresAt: 4},
+		inner:                 inner,
+		permKey:               permKey,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages, _ := m.outgoing.take()
+	if len(messages) != 1 || messages[0].msgID != msgID {
+		t.Fatalf("unexpected queue: %+v", messages)
+	}
+	d := serialize.NewDecoder(messages[0].body)
+	if crc := d.PopCRC(); crc != (&BindTempAuthKeyParams{}).CRC() {
+		t.Fatalf("unexpected constructor %#x", crc)
+	}
+	params := new(BindTempAuthKeyParams)
+	params.DecodeFrom(d)
+
+	encrypted := params.EncryptedMessage
+	if !bytes.Equal(encrypted[:8], utils.AuthKeyHash(permKey)) {
+		t.Error("message must start with id of permanent key")
+	}
+	msgKey := encrypted[8:24]
+	plain := decryptFromClient(t, encrypted[24:], permKey, msgKey)
+
+	d = serialize.NewDecoder(plain)
+	_ = d.PopRawBytes(serialize.Int128Len) // случайные байты
+	if got := d.PopLong(); got != msgID {
+		t.Errorf("inner msg_id %v doesn't match request msg_id %v", got, msgID)
+	}
+	if seqNo := d.PopInt(); seqNo != 0 {
+		t.Errorf("seqno must be 0, got %v", seqNo)
+	}
+	length := d.PopInt()
+	body := d.PopRawBytes(int(length))
+	if !bytes.Equal(body, inner.Encode()) {
+		t.Error("inner message mismatch")
+	}
+	if !bytes.Equal(ige.MessageKey(plain[:serialize.Int128Len+16+int(length)]), msgKey) {
+		t.Error("msg_key mismatch")
+	}
+}
+
+func TestTempKeyNotSaved(t *testing.T) {
+	permKey, tempKey := bytes.Repeat([]byte{1}, 256), bytes.Repeat([]byte{2}, 256)
+
+	m := &MTProto{mutex: &sync.Mutex{}, sessionStorage: NewMemorySessionStorage()}
+	m.SetAuthKey(permKey)
+	m.permAuthKey, m.permAuthKeyHash = m.authKey, m.authKeyHash
+	m.SetAuthKey(tempKey)
+
+	if s := m.ExportSession(); !bytes.Equal(s.Key, permKey) {
+		t.Error("exported session must contain permanent key")
+	}
+
+	if err := m.SaveSession(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.LoadSession(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m.GetAuthKey(), permKey) || m.permAuthKey != nil || !m.tempKeyNeedsRenewal() {
+		t.Error("loaded session must use permanent key until new temporary one is bound")
+	}
+}
+
+func TestTempKeyDC(t *testing.T) {
+	secret, err := transport.ParseProxySecret("00112233445566778899aabbccddeeff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxied, err := transport.NewObfuscated2(nil, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		dc        int
+		transport transport.Transport
+		config    Config
+		want      int32
+	}{
+		{"production", 2, nil, Config{}, 2},
+		{"test server", 2, nil, Config{TestServer: true}, 10002},
+		{"media only", 4, nil, Config{MediaOnly: true}, -4},
+		{"via proxy", 0, proxied, Config{}, 3},
+	}
+	for _, c := range cases {
+		m := &MTProto{mutex: &sync.Mutex{}, dc: c.dc, transport: c.transport}
+		m.config = c.config
+		got, err := m.tempKeyDC()
+		if err != nil || got != c.want {
+			t.Errorf("%v: got %v, %v, want %v", c.name, got, err, c.want)
+		}
+	}
+
+	m := &MTProto{mutex: &sync.Mutex{}, transport: transport.NewIntermediate()}
+	if _, err := m.tempKeyDC(); err == nil {
+		t.Error("expected error for unknown dc")
+	}
+}
+
+func TestSwitchTempKey(t *testing.T) {
+	conn := newRecordingConn()
+	m := newTestMTProto(t, conn)
+	defer m.Stop()
+	oldKey, oldSession := m.GetAuthKey(), m.GetSessionID()
+
+	// один запрос уже ушел со старым ключом, другой только получил msg_id
+	sent, _ := m.nextMessage(true)
+	queued, seqNo := m.nextMessage(true)
+	sentResp, queuedResp := make(chan serialize.TL, 1), make(chan serialize.TL, 1)
+	m.mutex.Lock()
+	m.responseChannels[sent] = sentResp
+	m.responseChannels[queued] = queuedResp
+	m.mutex.Unlock()
+
+	newKey := bytes.Repeat([]byte{3}, 256)
+	expiresAt := time.Now().Add(time.Hour)
+	m.switchTempKey(newKey, 5, expiresAt)
+
+	if !bytes.Equal(m.GetAuthKey(), newKey) || m.GetServerSalt() != 5 || !m.tempKeyExpiry().Equal(expiresAt) {
+		t.Error("new key wasn't applied")
+	}
+	if m.GetSessionID() == oldSession {
+		t.Error("new key must start new session")
+	}
+	// ответы на то, что ушло со старым ключом, еще придут
+	if !bytes.Equal(m.decryptionKey(utils.AuthKeyHash(oldKey)), oldKey) ||
+		!bytes.Equal(m.decryptionKey(utils.AuthKeyHash(newKey)), newKey) {
+		t.Error("wrong decryption key")
+	}
+	if !m.isKnownSession(oldSession) || !m.isKnownSession(m.GetSessionID()) || m.isKnownSession(oldSession+1) {
+		t.Error("wrong sessions accepted")
+	}
+
+	// seqno старой сессии в новую не отправляется, запрос уходит заново
+	m.outgoing.push(&outgoingMessage{msgID: queued, seqNo: seqNo, body: (&PingParams{}).Encode(), requireToAck: true})
+	select {
+	case resp := <-queuedResp:
+		if _, ok := resp.(*serialize.ErrorSessionConfigsChanged); !ok {
+			t.Errorf("unexpected response %T", resp)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued request wasn't resent")
+	}
+
+	// после обрыва соединения про запрос из старой сессии уже не спросить
+	m.resendPendingRequests(m.GetSessionID(), nil)
+	select {
+	case resp := <-sentResp:
+		if _, ok := resp.(*UnconfirmedRequestError); !ok {
+			t.Errorf("unexpected response %T", resp)
+		}
+	default:
+		t.Error("request from previous session wasn't failed")
+	}
+}
@@ -0,0 +1,279 @@
+package mtproto
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+const (
+	// первая попытка переподключения происходит почти сразу, каждая следующая ждет в два раза дольше
+	reconnectMinDelay = 500 * time.Millisecond
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// reconnectDelay возвращает сколько ждать перед попыткой переподключения с номером attempt (с нуля).
+// половина задержки случайная, что бы клиенты, которые отвалились одновременно, не ломились обратно
+// все в одну и ту же секунду
+func reconnectDelay(attempt int) time.Duration {
+	d := reconnectMaxDelay
+	if attempt < 16 {
+		if shifted := reconnectMinDelay << uint(attempt); shifted < d {
+			d = shifted
+		}
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// reconnect переподключается к тому же датацентру после обрыва соединения. ключ авторизации, соль и
+// id сессии обычно остаются прежними, поэтому после переподключения достаточно отправить заново
+// запросы, на которые так и не пришел rpc_result, см. resendPendingRequests
+func (m *MTProto) reconnect() {
+	defer m.recoverGoroutine()
+	if !atomic.CompareAndSwapInt32(&m.reconnecting, 0, 1) {
+		// кто-то уже переподключается
+		return
+	}
+	defer atomic.StoreInt32(&m.reconnecting, 0)
+
+	session := m.GetSessionID()
+	// старое соединение уже мертвое, так что ошибка закрытия никому не интересна
+	_ = m.Stop()
+	m.reconnectLoop(session, m.outgoing.dropMessages())
+}
+
+// reconnectWith подключается заново, когда соединение уже остановлено, а сообщения unsent сессии
+// session вынуты из очереди, например после неудачного переезда в другой датацентр
+func (m *MTProto) reconnectWith(session int64, unsent []*outgoingMessage) {
+	defer m.recoverGoroutine()
+	if !atomic.CompareAndSwapInt32(&m.reconnecting, 0, 1) {
+		// кто-то уже переподключается, он и отправит их
+		for _, msg := range unsent {
+			m.outgoing.push(msg)
+		}
+		return
+	}
+	defer atomic.StoreInt32(&m.reconnecting, 0)
+
+	m.reconnectLoop(session, unsent)
+}
+
+// reconnectLoop пытается подключиться, пока не выйдет, и отправляет заново то, что ждало ответа
+func (m *MTProto) reconnectLoop(session int64, unsent []*outgoingMessage) {
+	for attempt := 0; ; attempt++ {
+		if atomic.LoadInt32(&m.disconnected) != 0 {
+			return
+		}
+
+		err := m.connect()
+		if err == nil {
+			break
+		}
+		if isPermanentConnectError(err) {
+			// сколько ни пробуй, результат будет тот же
+			m.warn(errors.Wrap(err, "giving up reconnecting"))
+			m.failPendingRequests()
+			return
+		}
+		m.warn(errors.Wrapf(err, "reconnecting, attempt %v", attempt+1))
+
+		time.Sleep(reconnectDelay(attempt))
+	}
+
+	// пока подключались, клиент могли отключить. Disconnect закрыл прошлое соединение, а это
+	// закрываем сами
+	if atomic.LoadInt32(&m.disconnected) != 0 {
+		_ = m.Stop()
+		return
+	}
+
+	m.resendPendingRequests(session, unsent)
+}
+
+// isPermanentConnectError показывает, что переподключаться дальше бесполезно: адрес неверный,
+// такого хоста нет или у клиента нет ключа, которым сервер готов шифровать обмен ключами
+func isPermanentConnectError(err error) bool {
+	var addrErr *net.AddrError
+	var dnsErr *net.DNSError
+	var parseErr *net.ParseError
+	switch {
+	case errors.As(err, &addrErr), errors.As(err, &parseErr):
+		return true
+	case errors.As(err, &dnsErr):
+		return dnsErr.IsNotFound
+	default:
+		cause := errors.Cause(err)
+		return cause == errNoPublicKey || cause == errTempKeyDCUnknown
+	}
+}
+
+// failPendingRequests будит всех, кто ждет ответа, когда переподключиться уже не выйдет
+func (m *MTProto) failPendingRequests() {
+	m.outgoing.dropMessages()
+	m.mutex.Lock()
+	for msgID, c := range m.responseChannels {
+		c <- &UnconfirmedRequestError{MsgID: msgID}
+		delete(m.responseChannels, msgID)
+	}
+	m.mutex.Unlock()
+}
+
+// UnconfirmedRequestError получает тот, кто ждет ответа на запрос, если запрос мог дойти до
+// сервера, но проверить это уже нельзя. отправлять такой запрос заново небезопасно: сервер мог его
+// уже выполнить
+type UnconfirmedRequestError struct {
+	MsgID int64
+}
+
+func (*UnconfirmedRequestError) CRC() uint32 {
+	panic("not acceptable")
+}
+
+func (*UnconfirmedRequestError) Encode() []byte {
+	panic("not acceptable")
+}
+
+func (*UnconfirmedRequestError) DecodeFrom(d *serialize.Decoder) {
+	panic("not acceptable")
+}
+
+func (e *UnconfirmedRequestError) Error() string {
+	return fmt.Sprintf("connection was lost and it's unknown whether request %v was received", e.MsgID)
+}
+
+// resendPendingRequests отправляет заново запросы, на которые так и не пришел ответ. session это
+// сессия, в которой они отправлялись, unsent это сообщения, которые так и не ушли со старым
+// соединением.
+//
+// если сессия та же, то запросы уходят с теми же msg_id и seqno: если сервер их уже получил, то
+// второй раз не выполнит. если запрос уже забыт (или слишком старый, и сервер его не примет), то
+// сначала через msgs_state_req спрашиваем, получил ли его сервер, см. handleStateInfo
+func (m *MTProto) resendPendingRequests(session int64, unsent []*outgoingMessage) {
+	if m.GetSessionID() != session {
+		m.resendToNewSession(unsent)
+		return
+	}
+
+	for _, msg := range unsent {
+		m.outgoing.push(msg)
+	}
+
+	oldest := m.oldestLoggedMsgID()
+	var unknown, previous []int64
+	m.mutex.Lock()
+	resend := make([]*outgoingMessage, 0, len(m.responseChannels))
+	for msgID := range m.responseChannels {
+		if m.outgoing.queued(msgID) {
+			continue
+		}
+		// запрос ушел еще с прошлым временным ключом, в этой сессии про него ничего не узнать
+		if !m.seq.inSession(msgID) {
+			previous = append(previous, msgID)
+			continue
+		}
+		logged, ok := m.sentLog.get(msgID)
+		if !ok || msgID < oldest {
+			unknown = append(unknown, msgID)
+			continue
+		}
+		resend = append(resend, &outgoingMessage{
+			msgID:        msgID,
+			seqNo:        logged.seqNo,
+			body:         logged.body,
+			requireToAck: logged.seqNo&1 != 0,
+		})
+	}
+
+	// на старые msgs_state_req ответ уже может и не прийти, про эти запросы спрашиваем заново
+	m.stateRequests = make(map[int64][]int64)
+	var reqMsgID int64
+	var reqSeqNo int32
+	if len(unknown) > 0 {
+		reqMsgID, reqSeqNo = m.nextMessage(true)
+		m.stateRequests[reqMsgID] = unknown
+	}
+	m.mutex.Unlock()
+
+	for _, msgID := range previous {
+		_ = m.writeRPCResponse(int(msgID), &UnconfirmedRequestError{MsgID: msgID})
+	}
+
+	// по порядку msg_id, так сервер получит их так же, как в первый раз
+	sort.Slice(resend, func(i, j int) bool { return resend[i].msgID < resend[j].msgID })
+	for _, msg := range resend {
+		m.outgoing.push(msg)
+	}
+	if len(unknown) > 0 {
+		m.pushServiceMessage(reqMsgID, reqSeqNo, &serialize.MsgsStateReq{MsgIds: unknown})
+	}
+}
+
+// resendToNewSession разбирается с запросами, когда сессия сменилась (новый временный ключ или
+// другой датацентр). в новой сессии сервер про старые msg_id ничего не скажет, поэтому заново, с
+// новыми msg_id, отправляется только то, что до сервера точно не дошло. остальные получают
+// UnconfirmedRequestError
+func (m *MTProto) resendToNewSession(unsent []*outgoingMessage) {
+	// пока переподключались, в очередь могли положить что-то еще, с seqno старой сессии
+	unsent = append(unsent, m.outgoing.dropMessages()...)
+	notSent := make(map[int64]bool, len(unsent))
+	for _, msg := range unsent {
+		notSent[msg.msgID] = true
+	}
+
+	m.mutex.Lock()
+	pending := m.responseChannels
+	m.responseChannels = make(map[int64]chan serialize.TL)
+	for msgID := range pending {
+		// при повторной отправке у запроса будет новый msgID
+		delete(m.msgsIdDecodeAsVector, msgID)
+	}
+	// отправленное в старой сессии в новой уже не пригодится
+	m.sentLog = messageLog{}
+	m.stateRequests = nil
+	m.mutex.Unlock()
+
+	for msgID, v := range pending {
+		if notSent[msgID] {
+			v <- &serialize.ErrorSessionConfigsChanged{}
+		} else {
+			v <- &UnconfirmedRequestError{MsgID: msgID}
+		}
+	}
+}
+
+// handleStateInfo разбирает ответ на msgs_state_req из resendPendingRequests. запросы, которые до
+// сервера не дошли, отправляются заново с новыми msg_id. на полученные сервер сам пришлет ответ,
+// т.к. мы его не подтвердили. а про слишком старые уже ничего не узнать
+func (m *MTProto) handleStateInfo(message *serialize.MsgsStateInfo) error {
+	m.mutex.Lock()
+	msgIDs, ok := m.stateRequests[message.ReqMsgId]
+	delete(m.stateRequests, message.ReqMsgId)
+	m.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	if len(message.Info) != len(msgIDs) {
+		return errors.Errorf("got %v statuses for %v messages", len(message.Info), len(msgIDs))
+	}
+
+	for i, id := range msgIDs {
+		switch message.Info[i] & msgStateMask {
+		case msgStateNotReceived, msgStateTooHigh:
+			m.resendMessages([]int64{id})
+		case msgStateReceived:
+			m.gotAck(id)
+		default:
+			_ = m.writeRPCResponse(int(id), &UnconfirmedRequestError{MsgID: id})
+		}
+	}
+	return nil
+}
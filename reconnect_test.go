@@ -0,0 +1,170 @@
+package mtproto
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	cases := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 250 * time.Millisecond, 500 * time.Millisecond},
+		{1, 500 * time.Millisecond, time.Second},
+		{3, 2 * time.Second, 4 * time.Second},
+		{10, 15 * time.Second, 30 * time.Second},
+		{100, 15 * time.Second, 30 * time.Second},
+	}
+
+	for _, c := range cases {
+		for i := 0; i < 100; i++ {
+			d := reconnectDelay(c.attempt)
+			if d < c.min || d > c.max {
+				t.Fatalf("attempt %v: delay %v not in [%v, %v]", c.attempt, d, c.min, c.max)
+			}
+		}
+	}
+}
+
+func TestReconnectPermanentError(t *testing.T) {
+	key := bytes.Repeat([]byte{2}, 256)
+	storage := NewMemorySessionStorage()
+	// без порта адрес не станет правильным, сколько ни пробуй
+	storeTestSession(t, storage, HomeDC, &Session{
+		Key: key, Hash: utils.AuthKeyHash(key), Salt: make([]byte, 8), Hostname: "127.0.0.1", DC: 2,
+	})
+	m, err := NewMTProto(Config{SessionStorage: storage, Account: "acc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending := make(chan serialize.TL, 1)
+	m.mutex.Lock()
+	m.responseChannels[1] = pending
+	m.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.reconnectLoop(m.GetSessionID(), nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("client keeps reconnecting to invalid address")
+	}
+	select {
+	case resp := <-pending:
+		if _, ok := resp.(*UnconfirmedRequestError); !ok {
+			t.Errorf("unexpected response %T", resp)
+		}
+	default:
+		t.Error("pending request wasn't woken up")
+	}
+
+	if !isPermanentConnectError(errors.Wrap(errors.Wrap(errNoPublicKey, "handshake"), "making auth key")) {
+		t.Error("missing public key must be permanent")
+	}
+	if isPermanentConnectError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}) {
+		t.Error("refused connection must be retried")
+	}
+}
+
+func TestResendPendingRequests(t *testing.T) {
+	m := newTestClockMTProto()
+	m.seq.reset(1)
+
+	// первый запрос ушел и лежит в журнале, второй уже забыт, третий так и не ушел
+	sent := &outgoingMessage{msgID: m.newMsgID(), seqNo: 5, body: []byte{1, 2, 3, 4}, requireToAck: true}
+	m.logSent(sent)
+	forgotten := m.newMsgID()
+	unsent := &outgoingMessage{msgID: m.newMsgID(), seqNo: 7, body: []byte{5, 6, 7, 8}, requireToAck: true}
+	lost := make(chan serialize.TL, 1)
+	m.responseChannels[sent.msgID] = make(chan serialize.TL, 1)
+	m.responseChannels[forgotten] = lost
+	m.responseChannels[unsent.msgID] = make(chan serialize.TL, 1)
+
+	m.resendPendingRequests(1, []*outgoingMessage{unsent})
+
+	// то, что сервер мог получить, уходит с теми же msg_id и seqno, он сам отбросит повторы
+	messages := takeMessages(t, m, 3)
+	if !reflect.DeepEqual(messages[:2], []*outgoingMessage{unsent, sent}) {
+		t.Errorf("requests resent as %#v, %#v", messages[0], messages[1])
+	}
+	req, ok := serialize.NewDecoder(messages[2].body).PopObj().(*serialize.MsgsStateReq)
+	if !ok || !reflect.DeepEqual(req.MsgIds, []int64{forgotten}) {
+		t.Fatalf("expected msgs_state_req for %v, got %#v", forgotten, req)
+	}
+	if len(m.responseChannels) != 3 {
+		t.Error("requests must still wait for response")
+	}
+
+	// забытый запрос до сервера не дошел, значит его можно отправить с новым msg_id
+	err := m.processResponse(int(serverMsgID(time.Now())), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.MsgsStateInfo{ReqMsgId: messages[2].msgID, Info: []byte{msgStateNotReceived}}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := (<-lost).(*serialize.ErrorSessionConfigsChanged); !ok {
+		t.Error("request that server didn't receive was not resent")
+	}
+}
+
+func TestResendPendingRequestsUnknownState(t *testing.T) {
+	m := newTestClockMTProto()
+	m.seq.reset(1)
+	old := utils.MessageIDAt(time.Now().Add(-2 * maxMsgAge))
+	waiting := make(chan serialize.TL, 1)
+	m.responseChannels[old] = waiting
+
+	m.resendPendingRequests(1, nil)
+	reqID := takeMessage(t, m).msgID
+
+	// сервер уже не помнит, получал ли он запрос, отправлять его заново нельзя
+	err := m.processResponse(int(serverMsgID(time.Now())), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.MsgsStateInfo{ReqMsgId: reqID, Info: []byte{msgStateUnknown}}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := (<-waiting).(*UnconfirmedRequestError); !ok || e.MsgID != old {
+		t.Error("expected UnconfirmedRequestError")
+	}
+}
+
+func TestResendPendingRequestsNewSession(t *testing.T) {
+	m := newTestClockMTProto()
+	m.seq.reset(2)
+	sent, unsent := make(chan serialize.TL, 1), make(chan serialize.TL, 1)
+	m.responseChannels[4] = sent
+	m.responseChannels[8] = unsent
+	m.msgsIdDecodeAsVector[8] = reflect.TypeOf(int64(0))
+
+	m.resendPendingRequests(1, []*outgoingMessage{{msgID: 8, seqNo: 3, requireToAck: true}})
+
+	if _, ok := (<-unsent).(*serialize.ErrorSessionConfigsChanged); !ok {
+		t.Error("request that was never sent must be resent")
+	}
+	if _, ok := (<-sent).(*UnconfirmedRequestError); !ok {
+		t.Error("request that could be received by old session must not be resent")
+	}
+	if len(m.responseChannels) != 0 {
+		t.Errorf("response channels were not reset: %v", m.responseChannels)
+	}
+	if len(m.msgsIdDecodeAsVector) != 0 {
+		t.Errorf("vector hints for old msg ids were not removed: %v", m.msgsIdDecodeAsVector)
+	}
+	if messages, _ := m.outgoing.take(); len(messages) != 0 {
+		t.Errorf("messages of old session were sent: %v", messages)
+	}
+}
@@ -0,0 +1,144 @@
+package mtproto
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+// соль сервера действует ограниченное время (обычно полчаса-час), поэтому клиент заранее просит
+// следующие через get_future_salts и сам переключается на новую, когда текущая истекает. так
+// bad_server_salt приходит только если что-то пошло не так, например после нового ключа
+// https://core.telegram.org/mtproto/service_messages#request-for-several-future-salts
+
+const (
+	// сколько солей просить за раз, сервер отдает не больше 64
+	futureSaltsCount = 32
+	// новые соли запрашиваются, когда до конца последней известной остается столько
+	futureSaltsRefreshBefore = 30 * time.Minute
+	// если запросить соли не получилось, то следующая попытка через столько
+	futureSaltsRetryDelay = time.Minute
+	// сколько ждать ответа на get_future_salts
+	futureSaltsTimeout = 30 * time.Second
+)
+
+// setServerSalt задает соль, которую прислал сервер (например в bad_server_salt или
+// new_session_created). заранее полученные соли после этого уже не годятся, поэтому забываются, а
+// вместо них запрашиваются новые
+func (m *MTProto) setServerSalt(salt int64) {
+	m.saltMutex.Lock()
+	m.serverSalt = salt
+	m.futureSalts = nil
+	m.saltMutex.Unlock()
+
+	select {
+	case m.saltsInvalidated <- struct{}{}:
+	default:
+	}
+}
+
+// addFutureSalts запоминает соли из ответа на get_future_salts
+func (m *MTProto) addFutureSalts(salts []*serialize.FutureSalt) {
+	m.saltMutex.Lock()
+	defer m.saltMutex.Unlock()
+
+	known := make(map[int64]bool, len(m.futureSalts))
+	for _, salt := range m.futureSalts {
+		known[salt.Salt] = true
+	}
+	for _, salt := range salts {
+		if !known[salt.Salt] {
+			m.futureSalts = append(m.futureSalts, salt)
+		}
+	}
+	sort.Slice(m.futureSalts, func(i, j int) bool {
+		return m.futureSalts[i].ValidSince < m.futureSalts[j].ValidSince
+	})
+}
+
+// currentSalt возвращает соль, которая действует в момент now, переключаясь на следующую из
+// полученных заранее, если нужно
+func (m *MTProto) currentSalt(now time.Time) int64 {
+	m.saltMutex.Lock()
+	defer m.saltMutex.Unlock()
+
+	unix := int32(now.Unix())
+	// истекшие соли больше не нужны
+	for len(m.futureSalts) > 0 && m.futureSalts[0].ValidUntil <= unix {
+		m.futureSalts = m.futureSalts[1:]
+	}
+	if len(m.futureSalts) > 0 && m.futureSalts[0].ValidSince <= unix {
+		m.serverSalt = m.futureSalts[0].Salt
+	}
+
+	return m.serverSalt
+}
+
+// saltsRefreshTime возвращает, когда (по локальным часам) пора запрашивать новые соли: за
+// futureSaltsRefreshBefore до конца последней известной. если солей нет, то возвращается нулевое
+// время, то есть запрашивать нужно сразу
+func (m *MTProto) saltsRefreshTime() time.Time {
+	m.saltMutex.Lock()
+	defer m.saltMutex.Unlock()
+
+	if len(m.futureSalts) == 0 {
+		return time.Time{}
+	}
+	validUntil := time.Unix(int64(m.futureSalts[len(m.futureSalts)-1].ValidUntil), 0)
+	return validUntil.Add(-m.TimeOffset() - futureSaltsRefreshBefore)
+}
+
+// updateFutureSalts запрашивает у сервера следующие соли
+func (m *MTProto) updateFutureSalts(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, futureSaltsTimeout)
+	defer cancel()
+
+	res, err := m.GetFutureSalts(ctx, futureSaltsCount)
+	if err != nil {
+		return err
+	}
+	if len(res.Salts) == 0 {
+		return errors.New("server returned no salts")
+	}
+
+	m.addFutureSalts(res.Salts)
+	return nil
+}
+
+// startSaltsUpdating следит, что бы заранее полученных солей всегда хватало
+func (m *MTProto) startSaltsUpdating(ctx context.Context) {
+	m.routineswg.Add(1)
+	go func() {
+		defer m.recoverGoroutine()
+		defer m.routineswg.Done()
+
+		for {
+			if time.Until(m.saltsRefreshTime()) <= 0 {
+				err := m.updateFutureSalts(ctx)
+				if err != nil && ctx.Err() == nil {
+					m.warn(errors.Wrap(err, "getting future salts"))
+				}
+			}
+
+			// даже если что-то пошло не так, не заваливаем сервер запросами
+			wait := time.Until(m.saltsRefreshTime())
+			if wait < futureSaltsRetryDelay {
+				wait = futureSaltsRetryDelay
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-m.saltsInvalidated:
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,101 @@
+package mtproto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lonesta/mtproto/serialize"
+)
+
+func TestCurrentSalt(t *testing.T) {
+	m := newTestClockMTProto()
+	m.setServerSalt(1)
+
+	start := time.Unix(1600000000, 0)
+	unix := int32(start.Unix())
+	// сервер может прислать соли не по порядку, а одну и ту же дважды
+	m.addFutureSalts([]*serialize.FutureSalt{
+		{ValidSince: unix + 3600, ValidUntil: unix + 7200, Salt: 3},
+		{ValidSince: unix, ValidUntil: unix + 3600, Salt: 2},
+	})
+	m.addFutureSalts([]*serialize.FutureSalt{
+		{ValidSince: unix + 3600, ValidUntil: unix + 7200, Salt: 3},
+	})
+	if len(m.futureSalts) != 2 {
+		t.Fatalf("expected 2 salts, got %v", len(m.futureSalts))
+	}
+
+	cases := []struct {
+		at   time.Duration
+		salt int64
+	}{
+		{-time.Minute, 1},
+		{0, 2},
+		{time.Hour - time.Second, 2},
+		{time.Hour, 3},
+		// все известные соли истекли, остается последняя
+		{3 * time.Hour, 3},
+	}
+	for _, c := range cases {
+		if got := m.currentSalt(start.Add(c.at)); got != c.salt {
+			t.Errorf("at %v: salt %v, want %v", c.at, got, c.salt)
+		}
+	}
+	if len(m.futureSalts) != 0 {
+		t.Error("expired salts must be forgotten")
+	}
+
+	// соль от сервера важнее заранее полученных
+	m.addFutureSalts([]*serialize.FutureSalt{{ValidSince: unix, ValidUntil: unix + 3600, Salt: 4}})
+	m.setServerSalt(5)
+	if got := m.currentSalt(start); got != 5 {
+		t.Errorf("salt %v, want 5", got)
+	}
+	if !m.saltsRefreshTime().IsZero() {
+		t.Error("future salts must be requested again after server set salt")
+	}
+}
+
+func TestSaltsRefreshTime(t *testing.T) {
+	m := newTestClockMTProto()
+	validUntil := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	m.addFutureSalts([]*serialize.FutureSalt{
+		{ValidSince: int32(time.Now().Unix()), ValidUntil: int32(validUntil.Unix()), Salt: 1},
+	})
+
+	want := validUntil.Add(-futureSaltsRefreshBefore)
+	if got := m.saltsRefreshTime(); !got.Equal(want) {
+		t.Errorf("refresh at %v, want %v", got, want)
+	}
+}
+
+func TestUpdateFutureSalts(t *testing.T) {
+	m := newTestClockMTProto()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- m.updateFutureSalts(context.Background())
+	}()
+	request := takeMessage(t, m)
+
+	now := int32(time.Now().Unix())
+	// future_salts приходит не в rpc_result
+	err := m.processResponse(int(m.newMsgID()|1), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.FutureSalts{
+			ReqMsgID: request.msgID,
+			Now:      now,
+			Salts:    []*serialize.FutureSalt{{ValidSince: now - 10, ValidUntil: now + 3600, Salt: 77}},
+		}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+
+	if salt := m.GetServerSalt(); salt != 77 {
+		t.Errorf("salt %v, want 77", salt)
+	}
+}
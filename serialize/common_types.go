@@ -77,6 +77,82 @@ func (e *PQInnerData) DecodeFrom(d *Decoder) {
 	e.NewNonce = d.PopInt256()
 }
 
+// PQInnerDataTempDC то же самое, что и PQInnerData, но для временного ключа, который живет
+// ExpiresIn секунд
+// https://core.telegram.org/mtproto/auth_key#presenting-proof-of-work-server-authentication
+type PQInnerDataTempDC struct {
+	Pq          []byte
+	P           []byte
+	Q           []byte
+	Nonce       *Int128
+	ServerNonce *Int128
+	NewNonce    *Int256
+	Dc          int32
+	ExpiresIn   int32
+}
+
+func (_ *PQInnerDataTempDC) CRC() uint32 {
+	return 0x56fddf88
+}
+
+func (t *PQInnerDataTempDC) Encode() []byte {
+	buf := NewEncoder()
+	buf.PutUint(t.CRC())
+	buf.PutMessage(t.Pq)
+	buf.PutMessage(t.P)
+	buf.PutMessage(t.Q)
+	buf.PutInt128(t.Nonce)
+	buf.PutInt128(t.ServerNonce)
+	buf.PutInt256(t.NewNonce)
+	buf.PutInt(t.Dc)
+	buf.PutInt(t.ExpiresIn)
+	return buf.GetBuffer()
+}
+
+func (e *PQInnerDataTempDC) DecodeFrom(d *Decoder) {
+	e.Pq = d.PopMessage()
+	e.P = d.PopMessage()
+	e.Q = d.PopMessage()
+	e.Nonce = d.PopInt128()
+	e.ServerNonce = d.PopInt128()
+	e.NewNonce = d.PopInt256()
+	e.Dc = d.PopInt()
+	e.ExpiresIn = d.PopInt()
+}
+
+// BindAuthKeyInner это то, что шифруется постоянным ключом в auth.bindTempAuthKey
+// https://core.telegram.org/method/auth.bindTempAuthKey
+type BindAuthKeyInner struct {
+	Nonce         int64
+	TempAuthKeyID int64
+	PermAuthKeyID int64
+	TempSessionID int64
+	ExpiresAt     int32
+}
+
+func (_ *BindAuthKeyInner) CRC() uint32 {
+	return 0x75a3f765
+}
+
+func (t *BindAuthKeyInner) Encode() []byte {
+	buf := NewEncoder()
+	buf.PutUint(t.CRC())
+	buf.PutLong(t.Nonce)
+	buf.PutLong(t.TempAuthKeyID)
+	buf.PutLong(t.PermAuthKeyID)
+	buf.PutLong(t.TempSessionID)
+	buf.PutInt(t.ExpiresAt)
+	return buf.GetBuffer()
+}
+
+func (t *BindAuthKeyInner) DecodeFrom(d *Decoder) {
+	t.Nonce = d.PopLong()
+	t.TempAuthKeyID = d.PopLong()
+	t.PermAuthKeyID = d.PopLong()
+	t.TempSessionID = d.PopLong()
+	t.ExpiresAt = d.PopInt()
+}
+
 type ServerDHParamsFail struct {
 	Nonce        *Int128
 	ServerNonce  *Int128
@@ -384,7 +460,16 @@ func (_ *FutureSalt) CRC() uint32 {
 }
 
 func (t *FutureSalt) Encode() []byte {
-	panic("makes no sense")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	t.encodeBare(buf)
+	return buf.Result()
+}
+
+func (t *FutureSalt) encodeBare(buf *Encoder) {
+	buf.PutInt(t.ValidSince)
+	buf.PutInt(t.ValidUntil)
+	buf.PutLong(t.Salt)
 }
 
 func (t *FutureSalt) DecodeFrom(d *Decoder) {
@@ -403,14 +488,30 @@ func (_ *FutureSalts) CRC() uint32 {
 	return 0xae500895
 }
 
+// в схеме salts:vector<future_salt>, то есть и вектор, и соли в нем без crc
 func (t *FutureSalts) Encode() []byte {
-	panic("makes no sense")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutLong(t.ReqMsgID)
+	buf.PutInt(t.Now)
+	buf.PutInt(int32(len(t.Salts)))
+	for _, salt := range t.Salts {
+		salt.encodeBare(buf)
+	}
+	return buf.Result()
 }
 
 func (t *FutureSalts) DecodeFrom(d *Decoder) {
 	t.ReqMsgID = d.PopLong()
 	t.Now = d.PopInt()
-	t.Salts = d.PopVector(reflect.TypeOf(&FutureSalt{})).([]*FutureSalt)
+	count := int(d.PopInt())
+	// каждая соль занимает 16 байт, больше, чем влезает в сообщение, их быть не может
+	dry.PanicIf(count < 0 || count > d.buf.Len()/16, fmt.Sprintf("invalid number of future salts: %v", count))
+	t.Salts = make([]*FutureSalt, count)
+	for i := range t.Salts {
+		t.Salts[i] = new(FutureSalt)
+		t.Salts[i].DecodeFrom(d)
+	}
 }
 
 type Pong struct {
@@ -423,7 +524,11 @@ func (_ *Pong) CRC() uint32 {
 }
 
 func (t *Pong) Encode() []byte {
-	panic("not implemented")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutLong(t.MsgID)
+	buf.PutLong(t.PingID)
+	return buf.Result()
 }
 
 func (t *Pong) DecodeFrom(d *Decoder) {
@@ -445,7 +550,12 @@ func (_ *NewSessionCreated) CRC() uint32 {
 }
 
 func (t *NewSessionCreated) Encode() []byte {
-	panic("not implemented")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutLong(t.FirstMsgID)
+	buf.PutLong(t.UniqueID)
+	buf.PutLong(t.ServerSalt)
+	return buf.Result()
 }
 
 func (t *NewSessionCreated) DecodeFrom(d *Decoder) {
@@ -472,8 +582,8 @@ func (t *MessageContainer) Encode() []byte {
 	for _, msg := range *t {
 		buf.PutLong(msg.MsgID)
 		buf.PutInt(msg.SeqNo)
-		//         msgID     seqNo     len             object
-		buf.PutInt(LongLen + WordLen + WordLen + int32(len(msg.Msg)))
+		// bytes это длина только самого объекта, без msg_id и seqno
+		buf.PutInt(int32(len(msg.Msg)))
 		buf.PutRawBytes(msg.Msg)
 	}
 	return buf.GetBuffer()
@@ -525,8 +635,22 @@ func (*GzipPacked) CRC() uint32 {
 	return CrcGzipPacked
 }
 
-func (*GzipPacked) Encode() []byte {
-	panic("not implemented")
+func (t *GzipPacked) Encode() []byte {
+	return GzipPack(t.Obj.Encode())
+}
+
+// GzipPack сжимает уже закодированный объект и заворачивает его в gzip_packed
+func GzipPack(data []byte) []byte {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	// в bytes.Buffer запись не ломается
+	_, _ = gz.Write(data)
+	_ = gz.Close()
+
+	buf := NewEncoder()
+	buf.PutCRC(CrcGzipPacked)
+	buf.PutMessage(compressed.Bytes())
+	return buf.Result()
 }
 
 func (t *GzipPacked) DecodeFrom(d *Decoder) {
@@ -660,11 +784,14 @@ func (_ *MsgResendReq) CRC() uint32 {
 }
 
 func (t *MsgResendReq) Encode() []byte {
-	panic("not implemented")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutVector(t.MsgIds)
+	return buf.Result()
 }
 
 func (t *MsgResendReq) DecodeFrom(d *Decoder) {
-	panic("not implemented")
+	t.MsgIds = d.PopVector(int64Type).([]int64)
 }
 
 type MsgsStateReq struct {
@@ -677,11 +804,14 @@ func (_ *MsgsStateReq) CRC() uint32 {
 }
 
 func (t *MsgsStateReq) Encode() []byte {
-	panic("not implemented")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutVector(t.MsgIds)
+	return buf.Result()
 }
 
 func (t *MsgsStateReq) DecodeFrom(d *Decoder) {
-	panic("not implemented")
+	t.MsgIds = d.PopVector(int64Type).([]int64)
 }
 
 type MsgsStateInfo struct {
@@ -695,11 +825,16 @@ func (_ *MsgsStateInfo) CRC() uint32 {
 }
 
 func (t *MsgsStateInfo) Encode() []byte {
-	panic("not implemented")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutLong(t.ReqMsgId)
+	buf.PutMessage(t.Info)
+	return buf.Result()
 }
 
 func (t *MsgsStateInfo) DecodeFrom(d *Decoder) {
-	panic("not implemented")
+	t.ReqMsgId = d.PopLong()
+	t.Info = d.PopMessage()
 }
 
 type MsgsAllInfo struct {
@@ -713,11 +848,16 @@ func (_ *MsgsAllInfo) CRC() uint32 {
 }
 
 func (t *MsgsAllInfo) Encode() []byte {
-	panic("not implemented")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutVector(t.MsgIds)
+	buf.PutMessage(t.Info)
+	return buf.Result()
 }
 
 func (t *MsgsAllInfo) DecodeFrom(d *Decoder) {
-	panic("not implemented")
+	t.MsgIds = d.PopVector(int64Type).([]int64)
+	t.Info = d.PopMessage()
 }
 
 type MsgsDetailedInfo struct {
@@ -733,11 +873,20 @@ func (_ *MsgsDetailedInfo) CRC() uint32 {
 }
 
 func (t *MsgsDetailedInfo) Encode() []byte {
-	panic("not implemented")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutLong(t.MsgId)
+	buf.PutLong(t.AnswerMsgId)
+	buf.PutInt(t.Bytes)
+	buf.PutInt(t.Status)
+	return buf.Result()
 }
 
 func (t *MsgsDetailedInfo) DecodeFrom(d *Decoder) {
-	panic("not implemented")
+	t.MsgId = d.PopLong()
+	t.AnswerMsgId = d.PopLong()
+	t.Bytes = d.PopInt()
+	t.Status = d.PopInt()
 }
 
 type MsgsNewDetailedInfo struct {
@@ -752,11 +901,18 @@ func (_ *MsgsNewDetailedInfo) CRC() uint32 {
 }
 
 func (t *MsgsNewDetailedInfo) Encode() []byte {
-	panic("not implemented")
+	buf := NewEncoder()
+	buf.PutCRC(t.CRC())
+	buf.PutLong(t.AnswerMsgId)
+	buf.PutInt(t.Bytes)
+	buf.PutInt(t.Status)
+	return buf.Result()
 }
 
 func (t *MsgsNewDetailedInfo) DecodeFrom(d *Decoder) {
-	panic("not implemented")
+	t.AnswerMsgId = d.PopLong()
+	t.Bytes = d.PopInt()
+	t.Status = d.PopInt()
 }
 
 type ServerDHParams interface {
@@ -769,12 +925,21 @@ type SetClientDHParamsAnswer interface {
 	ImplementsSetClientDHParamsAnswer()
 }
 
+type RpcDropAnswer interface {
+	TL
+	ImplementsRpcDropAnswer()
+}
+
 func GenerateCommonObject(constructorID uint32) (obj TL, isEnum bool, err error) {
 	switch constructorID {
 	case 0x05162463:
 		return &ResPQ{}, false, nil
 	case 0x83c95aec:
 		return &PQInnerData{}, false, nil
+	case 0x56fddf88:
+		return &PQInnerDataTempDC{}, false, nil
+	case 0x75a3f765:
+		return &BindAuthKeyInner{}, false, nil
 	case 0x79cb045d:
 		return &ServerDHParamsFail{}, false, nil
 	case 0xd0e8075c:
@@ -835,6 +1000,10 @@ func GenerateCommonObject(constructorID uint32) (obj TL, isEnum bool, err error)
 		return &MsgsDetailedInfo{}, false, nil
 	case 0x809db6df:
 		return &MsgsNewDetailedInfo{}, false, nil
+	case crcTrue:
+		return &Bool{Value: true}, true, nil
+	case crcFalse:
+		return &Bool{Value: false}, true, nil
 	default:
 		return nil, false, errs.NotFound("constructorID", fmt.Sprintf("%#v", constructorID))
 	}
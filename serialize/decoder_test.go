@@ -330,6 +330,16 @@ func TestPoppingBasicObjects(t *testing.T) {
 	}
 }
 
+func TestPoppingMessageContainer(t *testing.T) {
+	container := &MessageContainer{
+		{MsgID: 1, SeqNo: 1, Msg: (&MsgsAck{MsgIds: []int64{2}}).Encode()},
+		{MsgID: 5, SeqNo: 2, Msg: (&MsgsAck{MsgIds: []int64{3}}).Encode()},
+	}
+	d := NewDecoder(container.Encode())
+	assert.Equal(t, container, d.PopObj())
+	assert.Empty(t, d.GetRestOfMessage())
+}
+
 /*
 var data = []uint8{
 	0x48, 0x0f, 0x00, 0x00, 0x51, 0xb0, 0x73, 0x5f, 0x82, 0xc0, 0x73, 0x5f, 0x37, 0x97, 0x79, 0xbc,
@@ -659,3 +669,58 @@ var res = &telegram.Config{
 	BaseLangPackVersion:     0,
 }
 */
+
+func TestPoppingBool(t *testing.T) {
+	for _, value := range []bool{true, false} {
+		d := NewDecoder((&Bool{Value: value}).Encode())
+		obj := d.PopObj()
+		assert.Equal(t, &Bool{Value: value}, obj)
+		assert.Empty(t, d.GetRestOfMessage())
+	}
+}
+
+func TestPoppingFutureSalts(t *testing.T) {
+	// future_salts#ae500895 req_msg_id:long now:int salts:vector<future_salt>, вектор и соли без crc
+	input := []byte{
+		0x95, 0x08, 0x50, 0xae, // crc
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // req_msg_id
+		0x10, 0x00, 0x00, 0x00, // now
+		0x02, 0x00, 0x00, 0x00, // длина вектора
+		0x10, 0x00, 0x00, 0x00, 0x20, 0x00, 0x00, 0x00, 0x2a, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x20, 0x00, 0x00, 0x00, 0x30, 0x00, 0x00, 0x00, 0x2b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	expected := &FutureSalts{
+		ReqMsgID: 1,
+		Now:      0x10,
+		Salts: []*FutureSalt{
+			{ValidSince: 0x10, ValidUntil: 0x20, Salt: 42},
+			{ValidSince: 0x20, ValidUntil: 0x30, Salt: 43},
+		},
+	}
+
+	d := NewDecoder(input)
+	assert.Equal(t, expected, d.PopObj())
+	assert.Empty(t, d.GetRestOfMessage())
+	assert.Equal(t, input, expected.Encode())
+
+	// длина вектора больше, чем данных в сообщении
+	broken := append([]byte{}, input[:20]...)
+	broken[16] = 0xff
+	assert.Panics(t, func() { NewDecoder(broken).PopObj() })
+}
+
+func TestPoppingServiceMessages(t *testing.T) {
+	for _, obj := range []TL{
+		&MsgResendReq{MsgIds: []int64{1, 2}},
+		&MsgsStateReq{MsgIds: []int64{3}},
+		&MsgsStateInfo{ReqMsgId: 4, Info: []byte{1, 4, 12}},
+		&MsgsAllInfo{MsgIds: []int64{5, 6}, Info: []byte{2, 3}},
+		&MsgsDetailedInfo{MsgId: 7, AnswerMsgId: 8, Bytes: 100, Status: 0},
+		&MsgsNewDetailedInfo{AnswerMsgId: 9, Bytes: 200, Status: 0},
+		&Pong{MsgID: 10, PingID: 11},
+	} {
+		d := NewDecoder(obj.Encode())
+		assert.Equal(t, obj, d.PopObj())
+		assert.Empty(t, d.GetRestOfMessage())
+	}
+}
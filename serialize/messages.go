@@ -32,8 +32,8 @@ type EncryptedMessage struct {
 	MsgKey    []byte
 }
 
-func (msg *EncryptedMessage) Serialize(client MessageInformator, requireToAck bool) ([]byte, error) {
-	obj := serializePacket(client, msg.Msg, msg.MsgID, requireToAck)
+func (msg *EncryptedMessage) Serialize(client MessageInformator) ([]byte, error) {
+	obj := serializePacket(client, msg.Msg, msg.MsgID, msg.SeqNo)
 	encryptedData, err := ige.Encrypt(obj, client.GetAuthKey())
 	if err != nil {
 		return nil, errors.Wrap(err, "encrypting")
@@ -157,13 +157,12 @@ func (msg *UnencryptedMessage) GetSeqNo() int {
 // по факту это *MTProto структура
 type MessageInformator interface {
 	GetSessionID() int64
-	GetLastSeqNo() int32
 	GetServerSalt() int64
 	GetAuthKey() []byte
 	MakeRequest(msg TL) (TL, error)
 }
 
-func serializePacket(client MessageInformator, msg []byte, messageID int64, requireToAck bool) []byte {
+func serializePacket(client MessageInformator, msg []byte, messageID int64, seqNo int32) []byte {
 	buf := NewEncoder()
 
 	saltBytes := make([]byte, LongLen)
@@ -171,11 +170,8 @@ func serializePacket(client MessageInformator, msg []byte, messageID int64, requ
 	buf.PutRawBytes(saltBytes)
 	buf.PutLong(client.GetSessionID())
 	buf.PutLong(messageID)
-	if requireToAck { // не спрашивай, как это работает
-		buf.PutInt(client.GetLastSeqNo() | 1) // почему тут добавляется бит не ебу
-	} else {
-		buf.PutInt(client.GetLastSeqNo())
-	}
+	// seqno считает клиент: он зависит от того, сколько сообщений уже отправлено в этой сессии
+	buf.PutInt(seqNo)
 	buf.PutInt(int32(len(msg)))
 	buf.PutRawBytes(msg)
 	return buf.buf
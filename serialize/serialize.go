@@ -117,19 +117,23 @@ func (*ErrorSessionConfigsChanged) Error() string {
 
 // --------------------------------------------------------------------------------------
 
-// dummy bool struct for methods generation
-type Bool struct{}
-
-func (*Bool) CRC() uint32 {
-	panic("it's a dummy constructor!")
+// Bool это ответ boolTrue или boolFalse. в схеме это enum, а не объект, поэтому декодер создает его
+// сам, см. GenerateCommonObject
+type Bool struct {
+	Value bool
 }
 
-func (t *Bool) Encode() []byte {
-	panic("it's a dummy constructor!")
+func (t *Bool) CRC() uint32 {
+	if t.Value {
+		return crcTrue
+	}
+	return crcFalse
 }
 
-func (t *Bool) DecodeFrom(d *Decoder) {
-	panic("it's a dummy constructor!")
+func (t *Bool) Encode() []byte {
+	buf := NewEncoder()
+	buf.PutBool(t.Value)
+	return buf.Result()
 }
 
 // dummy bool struct for methods generation
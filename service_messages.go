@@ -0,0 +1,234 @@
+package mtproto
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+// служебные сообщения о сообщениях: стороны могут спросить друг у друга, что стало с
+// отправленными сообщениями, и попросить отправить их заново. поэтому клиент помнит, что он
+// отправил и что получил за последние maxMsgAge
+// https://core.telegram.org/mtproto/service_messages_about_messages
+
+const (
+	// больше стольких сообщений в журнале не хранится
+	maxLoggedMessages = 4096
+	// и отправленные сообщения в сумме занимают не больше этого, т.к. там бывают куски файлов
+	maxSentLogSize = 4 << 20
+)
+
+// статусы сообщений в msgs_state_info и msgs_all_info, по байту на сообщение
+const (
+	msgStateUnknown     byte = 1 // ничего не известно, msg_id слишком маленький
+	msgStateNotReceived byte = 2 // msg_id в пределах известных, но такого сообщения не было
+	msgStateTooHigh     byte = 3 // msg_id больше всех известных, такого сообщения точно не было
+	msgStateReceived    byte = 4
+
+	// флаги, которые добавляются к msgStateReceived
+	msgStateNoAckNeeded byte = 16
+
+	msgStateMask byte = 7
+)
+
+type loggedMessage struct {
+	seqNo int32
+	body  []byte
+}
+
+// messageLog помнит последние сообщения по их msg_id. старые забываются по времени, а если сообщений
+// слишком много, то и раньше
+type messageLog struct {
+	ids   []int64 // в порядке добавления
+	items map[int64]*loggedMessage
+	size  int
+	// самый большой id из тех, что пришлось забыть, и самый большой из всех, что были
+	forgotten int64
+	maxID     int64
+}
+
+// add добавляет сообщение и выкидывает те, что старше oldest, или не влезают в maxSize
+func (l *messageLog) add(msgID int64, msg *loggedMessage, oldest int64, maxSize int) {
+	if l.items == nil {
+		l.items = make(map[int64]*loggedMessage)
+	}
+	if _, ok := l.items[msgID]; !ok {
+		l.ids = append(l.ids, msgID)
+		l.items[msgID] = msg
+		l.size += len(msg.body)
+	}
+	if msgID > l.maxID {
+		l.maxID = msgID
+	}
+
+	for len(l.ids) > 0 {
+		id := l.ids[0]
+		first, ok := l.items[id]
+		if ok && id >= oldest && len(l.items) <= maxLoggedMessages && l.size <= maxSize {
+			break
+		}
+		if ok {
+			l.size -= len(first.body)
+			delete(l.items, id)
+		}
+		if id > l.forgotten {
+			l.forgotten = id
+		}
+		l.ids = l.ids[1:]
+	}
+}
+
+func (l *messageLog) get(msgID int64) (*loggedMessage, bool) {
+	msg, ok := l.items[msgID]
+	return msg, ok
+}
+
+func (l *messageLog) remove(msgID int64) {
+	if msg, ok := l.items[msgID]; ok {
+		l.size -= len(msg.body)
+		delete(l.items, msgID)
+	}
+}
+
+// state возвращает статус сообщения для msgs_state_info
+func (l *messageLog) state(msgID, oldest int64) byte {
+	if msg, ok := l.items[msgID]; ok {
+		state := msgStateReceived
+		if msg.seqNo&1 == 0 {
+			state |= msgStateNoAckNeeded
+		}
+		return state
+	}
+
+	switch {
+	case msgID < oldest || msgID <= l.forgotten:
+		return msgStateUnknown
+	case msgID > l.maxID:
+		return msgStateTooHigh
+	default:
+		return msgStateNotReceived
+	}
+}
+
+// oldestLoggedMsgID возвращает msg_id, про сообщения старше которого уже можно забыть
+func (m *MTProto) oldestLoggedMsgID() int64 {
+	return utils.MessageIDAt(m.serverTime().Add(-maxMsgAge))
+}
+
+// logSent запоминает отправленное сообщение, что бы его можно было отправить заново, если сервер
+// попросит. сообщения, которые не нужно подтверждать, сервер заново не просит
+func (m *MTProto) logSent(msg *outgoingMessage) {
+	if !msg.requireToAck {
+		return
+	}
+	oldest := m.oldestLoggedMsgID()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sentLog.add(msg.msgID, &loggedMessage{seqNo: msg.seqNo, body: msg.body}, oldest, maxSentLogSize)
+}
+
+// forgetSent забывает сообщение, которое сервер точно получил
+func (m *MTProto) forgetSent(msgID int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sentLog.remove(msgID)
+}
+
+// wasReceived проверяет, получали ли мы сообщение с таким id
+func (m *MTProto) wasReceived(msgID int64) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, ok := m.receivedLog.get(msgID)
+	return ok
+}
+
+// receivedStates возвращает статусы сообщений сервера для msgs_state_info
+func (m *MTProto) receivedStates(msgIDs []int64) []byte {
+	oldest := m.oldestLoggedMsgID()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	info := make([]byte, len(msgIDs))
+	for i, id := range msgIDs {
+		info[i] = m.receivedLog.state(id, oldest)
+	}
+	return info
+}
+
+// sendServiceMessage ставит в очередь служебное сообщение, ответа на которое никто не ждет
+func (m *MTProto) sendServiceMessage(msg serialize.TL) {
+	msgID, seqNo := m.nextMessage(true)
+	m.pushServiceMessage(msgID, seqNo, msg)
+}
+
+func (m *MTProto) pushServiceMessage(msgID int64, seqNo int32, msg serialize.TL) {
+	outgoing := &outgoingMessage{
+		msgID:        msgID,
+		seqNo:        seqNo,
+		body:         msg.Encode(),
+		requireToAck: true,
+	}
+	m.logSent(outgoing)
+	m.outgoing.push(outgoing)
+}
+
+// handleResendReq отправляет заново сообщения, которые просит сервер, с теми же msg_id и seqno.
+// если хотя бы одно из них уже забыто, то, как требует протокол, вместо этого отвечаем
+// msgs_state_info, как будто это был msgs_state_req
+func (m *MTProto) handleResendReq(reqMsgID int64, msgIDs []int64) {
+	m.mutex.Lock()
+	messages := make([]*outgoingMessage, 0, len(msgIDs))
+	for _, id := range msgIDs {
+		logged, ok := m.sentLog.get(id)
+		if !ok {
+			break
+		}
+		messages = append(messages, &outgoingMessage{
+			msgID:        id,
+			seqNo:        logged.seqNo,
+			body:         logged.body,
+			requireToAck: logged.seqNo&1 != 0,
+		})
+	}
+	m.mutex.Unlock()
+
+	if len(messages) != len(msgIDs) {
+		m.sendServiceMessage(&serialize.MsgsStateInfo{ReqMsgId: reqMsgID, Info: m.receivedStates(msgIDs)})
+		return
+	}
+	for _, msg := range messages {
+		m.outgoing.push(msg)
+	}
+}
+
+// handleAllInfo разбирает msgs_all_info: сервер сам рассказывает, что стало с нашими сообщениями.
+// запросы, которые до него не дошли, отправляются заново
+func (m *MTProto) handleAllInfo(message *serialize.MsgsAllInfo) error {
+	if len(message.Info) != len(message.MsgIds) {
+		return errors.Errorf("got %v statuses for %v messages", len(message.Info), len(message.MsgIds))
+	}
+
+	for i, id := range message.MsgIds {
+		switch message.Info[i] & msgStateMask {
+		case msgStateUnknown, msgStateNotReceived, msgStateTooHigh:
+			m.resendMessages(m.affectedMessages(id))
+		default:
+			m.gotAck(id)
+			m.forgetSent(id)
+		}
+	}
+	return nil
+}
+
+// handleDetailedInfo разбирает msg_detailed_info и msg_new_detailed_info: сервер вместо того,
+// что бы присылать ответ (обычно большой) еще раз, сообщает его id. если ответ уже есть, его
+// нужно подтвердить, иначе попросить прислать заново
+func (m *MTProto) handleDetailedInfo(answerMsgID int64) {
+	if m.wasReceived(answerMsgID) {
+		m.outgoing.pushAck(answerMsgID)
+		return
+	}
+	m.sendServiceMessage(&serialize.MsgResendReq{MsgIds: []int64{answerMsgID}})
+}
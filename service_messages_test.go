@@ -0,0 +1,188 @@
+package mtproto
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+// serverMsgID создает msg_id, как будто его создал сервер
+func serverMsgID(t time.Time) int64 {
+	return utils.MessageIDAt(t) | 1
+}
+
+// takeService ждет сообщение в очереди и декодирует его
+func takeService(t *testing.T, m *MTProto) serialize.TL {
+	t.Helper()
+	return serialize.NewDecoder(takeMessage(t, m).body).PopObj()
+}
+
+func TestMsgsStateReq(t *testing.T) {
+	m := newTestClockMTProto()
+	now := time.Now()
+	received := serverMsgID(now)
+	missed := serverMsgID(now.Add(time.Second))
+	last := serverMsgID(now.Add(2 * time.Second))
+	future := serverMsgID(now.Add(3 * time.Second))
+	old := serverMsgID(now.Add(-time.Hour))
+
+	ack := (&serialize.MsgsAck{}).Encode()
+	for _, id := range []int64{received, last} {
+		if err := m.processResponse(int(id), 1, &serialize.EncryptedMessage{Msg: ack}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reqID := last + 4
+	err := m.processResponse(int(reqID), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.MsgsStateReq{MsgIds: []int64{received, missed, future, old, reqID}}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, ok := takeService(t, m).(*serialize.MsgsStateInfo)
+	if !ok {
+		t.Fatal("expected msgs_state_info")
+	}
+	if info.ReqMsgId != reqID {
+		t.Errorf("req_msg_id %v, want %v", info.ReqMsgId, reqID)
+	}
+	expected := []byte{
+		msgStateReceived,
+		msgStateNotReceived,
+		msgStateTooHigh,
+		msgStateUnknown,
+		msgStateReceived | msgStateNoAckNeeded,
+	}
+	if !bytes.Equal(info.Info, expected) {
+		t.Errorf("states %v, want %v", info.Info, expected)
+	}
+}
+
+func TestMsgResendReq(t *testing.T) {
+	m := newTestClockMTProto()
+	sent := &outgoingMessage{msgID: m.newMsgID(), seqNo: 7, body: []byte{1, 2, 3, 4}, requireToAck: true}
+	m.logSent(sent)
+
+	err := m.processResponse(int(serverMsgID(time.Now())), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.MsgResendReq{MsgIds: []int64{sent.msgID}}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resent := takeMessage(t, m)
+	if resent.msgID != sent.msgID || resent.seqNo != 7 || !bytes.Equal(resent.body, sent.body) {
+		t.Errorf("message resent as %#v", resent)
+	}
+
+	// сервер получил сообщение, больше его хранить не нужно
+	err = m.processResponse(int(serverMsgID(time.Now())), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.MsgsAck{MsgIds: []int64{sent.msgID}}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqID := serverMsgID(time.Now())
+	err = m.processResponse(int(reqID), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.MsgResendReq{MsgIds: []int64{sent.msgID}}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, ok := takeService(t, m).(*serialize.MsgsStateInfo); !ok || info.ReqMsgId != reqID {
+		t.Error("forgotten message must be answered with msgs_state_info")
+	}
+}
+
+func TestMsgDetailedInfo(t *testing.T) {
+	m := newTestClockMTProto()
+	answer := serverMsgID(time.Now())
+	err := m.processResponse(int(answer), 1, &serialize.EncryptedMessage{Msg: (&serialize.MsgsAck{}).Encode()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.outgoing.take()
+
+	// ответ уже есть, его нужно только подтвердить
+	err = m.processResponse(int(answer+4), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.MsgsDetailedInfo{MsgId: 4, AnswerMsgId: answer}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, acks := m.outgoing.take()
+	if len(messages) != 0 || len(acks) != 1 || acks[0] != answer {
+		t.Errorf("expected only ack of %v, got %v messages and acks %v", answer, len(messages), acks)
+	}
+
+	// а этого ответа не было, его нужно попросить заново
+	missed := answer + 8
+	err = m.processResponse(int(answer+12), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.MsgsNewDetailedInfo{AnswerMsgId: missed}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, ok := takeService(t, m).(*serialize.MsgResendReq)
+	if !ok || len(req.MsgIds) != 1 || req.MsgIds[0] != missed {
+		t.Errorf("expected msg_resend_req for %v, got %#v", missed, req)
+	}
+}
+
+func TestMsgsAllInfo(t *testing.T) {
+	m := newTestClockMTProto()
+	lost, delivered := make(chan serialize.TL, 1), make(chan serialize.TL, 1)
+	m.responseChannels[1] = lost
+	m.responseChannels[2] = delivered
+
+	err := m.processResponse(int(serverMsgID(time.Now())), 0, &serialize.EncryptedMessage{
+		Msg: (&serialize.MsgsAllInfo{MsgIds: []int64{1, 2}, Info: []byte{msgStateNotReceived, msgStateReceived}}).Encode(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case resp := <-lost:
+		if _, ok := resp.(*serialize.ErrorSessionConfigsChanged); !ok {
+			t.Errorf("expected resend, got %#v", resp)
+		}
+	default:
+		t.Error("lost message was not resent")
+	}
+	if len(delivered) != 0 {
+		t.Error("delivered message must not be resent")
+	}
+}
+
+func TestMessageLogLimits(t *testing.T) {
+	var l messageLog
+	now := time.Now()
+	tooOld := utils.MessageIDAt(now.Add(-2 * maxMsgAge))
+	l.add(tooOld, &loggedMessage{}, 0, maxSentLogSize)
+
+	oldest := utils.MessageIDAt(now.Add(-maxMsgAge))
+	big := &loggedMessage{body: make([]byte, maxSentLogSize)}
+	first := utils.MessageIDAt(now)
+	l.add(first, big, oldest, maxSentLogSize)
+	if _, ok := l.get(tooOld); ok {
+		t.Error("old message must be forgotten")
+	}
+	if _, ok := l.get(first); !ok {
+		t.Error("message must be kept")
+	}
+
+	second := first + 4
+	l.add(second, &loggedMessage{body: []byte{1}}, oldest, maxSentLogSize)
+	if _, ok := l.get(first); ok {
+		t.Error("message must be forgotten when log is too big")
+	}
+	if state := l.state(first, oldest); state != msgStateUnknown {
+		t.Errorf("state of forgotten message %v, want %v", state, msgStateUnknown)
+	}
+}
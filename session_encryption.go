@@ -0,0 +1,285 @@
+package mtproto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// формат зашифрованной сессии, одинаковый для шифрования ключом и паролем:
+//
+//	magic "MTPS" | версия (1 байт) | откуда ключ (1 байт) | параметры ключа |
+//	nonce (12 байт) | сессия, зашифрованная AES-256-GCM
+//
+// у ключа, который передали напрямую, параметров нет. у ключа из пароля это log2(N), r, p для
+// scrypt (по 1 байту) и соль (16 байт). весь заголовок, аккаунт и номер датацентра входят в
+// additional data, так что ни параметры scrypt, ни саму сессию подменить нельзя
+
+const (
+	sessionMagic   = "MTPS"
+	sessionVersion = 1
+
+	sessionKeyRaw    = 0
+	sessionKeyScrypt = 1
+
+	passphraseSaltLen = 16
+	scryptLogN        = 15
+	scryptR           = 8
+	scryptP           = 1
+	// параметры scrypt читаются из файла, и без ограничений файл может заставить выделить гигабайты
+	// памяти или считать ключ часами
+	scryptMinLogN = 14
+	scryptMaxLogN = 20
+	scryptMaxR    = 16
+	scryptMaxP    = 4
+
+	// log2(N), r, p + соль
+	scryptParamsLen = 3 + passphraseSaltLen
+)
+
+// encryptedSessionStorage шифрует сессии AES-256-GCM перед тем, как отдать их в другое хранилище.
+// ключ либо передан напрямую (NewEncryptedSessionStorage), либо получается из пароля
+// (NewPassphraseSessionStorage)
+type encryptedSessionStorage struct {
+	inner SessionStorage
+	// ключ, переданный напрямую. если его нет, то ключ получается из passphrase
+	aead       cipher.AEAD
+	passphrase []byte
+
+	mutex sync.Mutex
+	// scrypt специально медленный, поэтому ключи кешируются по заголовку, с которым они получены
+	keys map[string]cipher.AEAD
+	// заголовок, с которым шифруются новые записи
+	header []byte
+}
+
+// NewEncryptedSessionStorage оборачивает inner так, что все сессии в нем хранятся зашифрованными.
+// key должен быть длиной 32 байта
+func NewEncryptedSessionStorage(inner SessionStorage, key []byte) (SessionStorage, error) {
+	if len(key) != 32 {
+		return nil, errors.Errorf("key must be 32 bytes, got %v", len(key))
+	}
+
+	aead, err := newSessionCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedSessionStorage{
+		inner:  inner,
+		aead:   aead,
+		header: append([]byte(sessionMagic), sessionVersion, sessionKeyRaw),
+	}, nil
+}
+
+// NewPassphraseSessionStorage оборачивает inner так, что все сессии шифруются ключом, полученным из
+// passphrase. сессии, сохраненные раньше без шифрования, при первой же загрузке перезаписываются
+// зашифрованными
+func NewPassphraseSessionStorage(inner SessionStorage, passphrase string) SessionStorage {
+	return &encryptedSessionStorage{
+		inner:      inner,
+		passphrase: []byte(passphrase),
+		keys:       make(map[string]cipher.AEAD),
+	}
+}
+
+func (s *encryptedSessionStorage) Load(account string, dc int) ([]byte, error) {
+	data, err := s.inner.Load(account, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, []byte(sessionMagic)) {
+		return s.loadPlain(account, dc, data)
+	}
+
+	header, err := s.splitHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := s.cipherFor(header)
+	if err != nil {
+		return nil, err
+	}
+
+	body := data[len(header):]
+	nonceSize := aead.NonceSize()
+	if len(body) < nonceSize {
+		return nil, errors.New("encrypted session is too short")
+	}
+
+	res, err := aead.Open(nil, body[:nonceSize], body[nonceSize:], sessionAdditionalData(header, account, dc))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting session (wrong key or passphrase?)")
+	}
+	return res, nil
+}
+
+// loadPlain читает сессию, сохраненную еще без шифрования, и сразу же перезаписывает ее
+// зашифрованной. если записать не вышло, то ничего страшного, попробуем в следующий раз. так
+// переходят на пароль, а в хранилище с ключом незашифрованных сессий быть не должно
+func (s *encryptedSessionStorage) loadPlain(account string, dc int, data []byte) ([]byte, error) {
+	if s.aead != nil || !json.Valid(data) {
+		return nil, errors.New("session is not encrypted")
+	}
+	_ = s.Store(account, dc, data)
+	return data, nil
+}
+
+func (s *encryptedSessionStorage) Store(account string, dc int, data []byte) error {
+	header, err := s.currentHeader()
+	if err != nil {
+		return err
+	}
+	aead, err := s.cipherFor(header)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return errors.Wrap(err, "generating nonce")
+	}
+
+	res := append(append([]byte(nil), header...), nonce...)
+	res = aead.Seal(res, nonce, data, sessionAdditionalData(header, account, dc))
+
+	return s.inner.Store(account, dc, res)
+}
+
+func (s *encryptedSessionStorage) Delete(account string, dc int) error {
+	return s.inner.Delete(account, dc)
+}
+
+// splitHeader возвращает заголовок сессии, проверив, что он целиком есть и что его версия известна
+func (s *encryptedSessionStorage) splitHeader(data []byte) ([]byte, error) {
+	// magic, версия и откуда ключ
+	headerLen := len(sessionMagic) + 2
+	if len(data) < headerLen {
+		return nil, errors.New("encrypted session is too short")
+	}
+
+	if version := data[len(sessionMagic)]; version != sessionVersion {
+		return nil, errors.Errorf("unsupported session version %v", version)
+	}
+	switch kind := data[len(sessionMagic)+1]; kind {
+	case sessionKeyRaw:
+	case sessionKeyScrypt:
+		headerLen += scryptParamsLen
+	default:
+		return nil, errors.Errorf("unsupported session key type %v", kind)
+	}
+
+	if len(data) < headerLen {
+		return nil, errors.New("encrypted session is too short")
+	}
+	return data[:headerLen], nil
+}
+
+// currentHeader возвращает заголовок, с которым сохраняются сессии. соль для пароля случайная, одна
+// на все время жизни хранилища
+func (s *encryptedSessionStorage) currentHeader() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.header != nil {
+		return s.header, nil
+	}
+
+	salt := make([]byte, passphraseSaltLen)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating salt")
+	}
+
+	header := []byte(sessionMagic)
+	header = append(header, sessionVersion, sessionKeyScrypt, scryptLogN, scryptR, scryptP)
+	s.header = append(header, salt...)
+	return s.header, nil
+}
+
+// cipherFor возвращает шифр для заголовка, который уже проверен splitHeader (или собран
+// currentHeader)
+func (s *encryptedSessionStorage) cipherFor(header []byte) (cipher.AEAD, error) {
+	if header[len(sessionMagic)+1] == sessionKeyRaw {
+		if s.aead == nil {
+			return nil, errors.New("session is encrypted with a key, not a passphrase")
+		}
+		return s.aead, nil
+	}
+	params := header[len(sessionMagic)+2:]
+	if s.passphrase == nil {
+		return nil, errors.New("session is encrypted with a passphrase, not a key")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if aead, ok := s.keys[string(header)]; ok {
+		return aead, nil
+	}
+
+	logN, r, p := params[0], int(params[1]), int(params[2])
+	if logN < scryptMinLogN || logN > scryptMaxLogN || r < 1 || r > scryptMaxR || p < 1 || p > scryptMaxP {
+		return nil, errors.Errorf("invalid scrypt parameters N=2^%v, r=%v, p=%v", logN, r, p)
+	}
+	salt := params[3:]
+
+	key, err := scrypt.Key(s.passphrase, salt, 1<<logN, r, p, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "deriving key")
+	}
+
+	aead, err := newSessionCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.keys[string(header)] = aead
+	return aead, nil
+}
+
+func newSessionCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating cipher")
+	}
+	return aead, nil
+}
+
+func sessionAdditionalData(header []byte, account string, dc int) []byte {
+	return append(append([]byte(nil), header...), sessionKey(account, dc)...)
+}
+
+// LoadSessionWithPassphrase читает сессию, сохраненную через SaveSessionWithPassphrase. файлы в
+// старом формате без шифрования тоже читаются и сразу же перешифровываются
+func LoadSessionWithPassphrase(path, passphrase string) (*Session, error) {
+	data, err := NewPassphraseSessionStorage(&singleFileSessionStorage{path: path}, passphrase).Load("", HomeDC)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeSession(data)
+}
+
+// SaveSessionWithPassphrase то же самое, что и SaveSession, только ключ авторизации не лежит в файле
+// в открытом виде
+func SaveSessionWithPassphrase(s *Session, path, passphrase string) error {
+	data, err := EncodeSession(s)
+	if err != nil {
+		return errors.Wrap(err, "encoding session")
+	}
+
+	return NewPassphraseSessionStorage(&singleFileSessionStorage{path: path}, passphrase).Store("", HomeDC, data)
+}
@@ -0,0 +1,128 @@
+package mtproto
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPassphraseSessionFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtproto-sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "session.bin")
+
+	session := &Session{
+		Key:      bytes.Repeat([]byte{0xaa}, 256),
+		Hash:     []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		Salt:     []byte{1, 0, 0, 0, 0, 0, 0, 0},
+		Hostname: "149.154.167.50:443",
+	}
+	if err = SaveSessionWithPassphrase(session, path, "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := ioutil.ReadFile(path)
+	if !bytes.HasPrefix(raw, []byte(sessionMagic)) || raw[len(sessionMagic)] != sessionVersion || raw[len(sessionMagic)+1] != sessionKeyScrypt {
+		t.Fatalf("unexpected header: %x", raw[:len(sessionMagic)+2])
+	}
+	if bytes.Contains(raw, session.Key[:16]) {
+		t.Fatal("auth key is stored in plain text")
+	}
+
+	got, err := LoadSessionWithPassphrase(path, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, session) {
+		t.Errorf("got %+v, want %+v", got, session)
+	}
+
+	if _, err = LoadSessionWithPassphrase(path, "wrong"); err == nil {
+		t.Error("session decrypted with wrong passphrase")
+	}
+
+	raw[len(sessionMagic)] = 99
+	_ = ioutil.WriteFile(path, raw, 0600)
+	if _, err = LoadSessionWithPassphrase(path, "hunter2"); err == nil {
+		t.Error("unknown version must not be accepted")
+	}
+}
+
+func TestPassphraseSessionMigration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtproto-sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "session.json")
+
+	session := &Session{Key: []byte{1, 2, 3}, Hash: []byte{4, 5}, Salt: make([]byte, 8), Hostname: "localhost:443"}
+	if err = SaveSession(session, path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadSessionWithPassphrase(path, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, session) {
+		t.Errorf("got %+v, want %+v", got, session)
+	}
+
+	raw, _ := ioutil.ReadFile(path)
+	if !bytes.HasPrefix(raw, []byte(sessionMagic)) {
+		t.Fatal("plain session was not re-encrypted")
+	}
+	got, err = LoadSessionWithPassphrase(path, "hunter2")
+	if err != nil || !reflect.DeepEqual(got, session) {
+		t.Errorf("migrated session: %+v, %v", got, err)
+	}
+}
+
+func TestSessionFormat(t *testing.T) {
+	inner := NewMemorySessionStorage()
+	secret := []byte(`{"key":"AQID"}`)
+
+	passphrase := NewPassphraseSessionStorage(inner, "hunter2")
+	encrypted, err := NewEncryptedSessionStorage(inner, bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = encrypted.Store("bob", HomeDC, secret); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := inner.Load("bob", HomeDC)
+	if !bytes.HasPrefix(raw, append([]byte(sessionMagic), sessionVersion, sessionKeyRaw)) {
+		t.Error("session encrypted with key has wrong header")
+	}
+	if got, err := encrypted.Load("bob", HomeDC); err != nil || !bytes.Equal(got, secret) {
+		t.Errorf("session encrypted with key: %q, %v", got, err)
+	}
+	if _, err = passphrase.Load("bob", HomeDC); err == nil {
+		t.Error("session encrypted with key must not be opened with passphrase")
+	}
+
+	// без шифрования сессии бывают только у тех, кто переходит на пароль
+	_ = inner.Store("dave", HomeDC, secret)
+	if _, err = encrypted.Load("dave", HomeDC); err == nil {
+		t.Error("plain session must not be accepted by storage with key")
+	}
+
+	// параметры scrypt берутся из файла, поэтому слишком дорогие не принимаются
+	if err = passphrase.Store("carol", HomeDC, secret); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ = inner.Load("carol", HomeDC)
+	raw[len(sessionMagic)+2] = 30
+	_ = inner.Store("carol", HomeDC, raw)
+	if _, err = passphrase.Load("carol", HomeDC); err == nil {
+		t.Error("scrypt with N=2^30 must be rejected")
+	}
+}
@@ -9,46 +9,107 @@ import (
 	"path/filepath"
 
 	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/transport"
 	"github.com/pkg/errors"
 	"github.com/xelaj/errs"
 	"github.com/xelaj/go-dry"
 )
 
 func (m *MTProto) SaveSession() (err error) {
-	m.encrypted = true
-	s := new(Session)
-	s.Key = m.authKey
-	s.Hash = m.authKeyHash
-	buf := make([]byte, serialize.LongLen)
-	binary.LittleEndian.PutUint64(buf, uint64(m.serverSalt))
-	s.Salt = buf
-	s.Hostname = m.addr
-	err = SaveSession(s, m.tokensStorage)
-	dry.PanicIfErr(err)
+	return m.saveSession(m.sessionDC)
+}
+
+// saveSession сохраняет текущую сессию в SessionStorage под номером slot
+func (m *MTProto) saveSession(slot int) error {
+	data, err := EncodeSession(m.ExportSession())
+	if err != nil {
+		return errors.Wrap(err, "encoding session")
+	}
+
+	err = m.sessionStorage.Store(m.account, slot, data)
+	if err != nil {
+		return errors.Wrap(err, "storing session")
+	}
 
 	return nil
 }
 
 func (m *MTProto) LoadSession() (err error) {
-	s, err := LoadSession(m.tokensStorage)
-	if errs.IsNotFound(err) {
-		return err
+	return m.loadSession(m.sessionDC)
+}
+
+// loadSession загружает сессию, которая лежит в SessionStorage под номером slot
+func (m *MTProto) loadSession(slot int) error {
+	data, err := m.sessionStorage.Load(m.account, slot)
+	if err != nil {
+		if errs.IsNotFound(err) {
+			return err
+		}
+		return errors.Wrap(err, "loading session")
 	}
-	dry.PanicIfErr(err)
 
+	s, err := DecodeSession(data)
+	if err != nil {
+		return errors.Wrap(err, "decoding session")
+	}
+
+	m.resetTempKey()
+	m.setServerSalt(int64(binary.LittleEndian.Uint64(s.Salt))) // СОЛЬ ЭТО LONG
+	m.mutex.Lock()
 	m.authKey = s.Key
 	m.authKeyHash = s.Hash
-	m.serverSalt = int64(binary.LittleEndian.Uint64(s.Salt)) // СОЛЬ ЭТО LONG
-	m.addr = s.Hostname
+	// через прокси подключаемся только к нему, сохраненный адрес датацентра тут не нужен
+	if !transport.ViaProxy(m.transport) {
+		m.addr = s.Hostname
+	}
+	m.dc = s.DC
+	m.userID = s.UserID
+	m.authorized = s.Authorized
+	m.mutex.Unlock()
 
 	return nil
 }
 
+// ExportSession возвращает текущую сессию, например что бы перенести ее на другую машину через
+// EncodeSessionString
+func (m *MTProto) ExportSession() *Session {
+	salt := make([]byte, serialize.LongLen)
+	binary.LittleEndian.PutUint64(salt, uint64(m.GetServerSalt()))
+	dc := m.DC()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	hostname := m.addr
+	if addr, ok := m.dclist[dc]; ok && transport.ViaProxy(m.transport) {
+		// адрес прокси сессии не принадлежит, сохраняем адрес самого датацентра
+		hostname = addr
+	}
+	key, hash := m.authKey, m.authKeyHash
+	if m.permAuthKey != nil {
+		// временный ключ не сохраняется никогда, иначе в PFS нет смысла
+		key, hash = m.permAuthKey, m.permAuthKeyHash
+	}
+
+	return &Session{
+		Key:        key,
+		Hash:       hash,
+		Salt:       salt,
+		Hostname:   hostname,
+		DC:         dc,
+		UserID:     m.userID,
+		Authorized: m.authorized,
+	}
+}
+
 type tokenStorageFormat struct {
 	Key      string `json:"key"`
 	Hash     string `json:"hash"`
 	Salt     string `json:"salt"`
 	Hostname string `json:"hostname"`
+	DC       int    `json:"dc,omitempty"`
+	UserID   int64  `json:"user_id,omitempty"`
+	// Authorized есть только у ключей других датацентров, см. Session
+	Authorized bool `json:"authorized,omitempty"`
 }
 
 type Session struct {
@@ -56,20 +117,33 @@ type Session struct {
 	Hash     []byte
 	Salt     []byte
 	Hostname string
+	// DC это номер датацентра, 0 если неизвестен
+	DC int
+	// UserID это id пользователя, которому принадлежит сессия, 0 если неизвестен
+	UserID int64
+	// Authorized означает, что в ключ другого датацентра уже перенесена авторизация (см.
+	// SetDCConnectHandler), и при следующем подключении делать это заново не нужно
+	Authorized bool
 }
 
-func LoadSession(path string) (*Session, error) {
-	if !dry.FileExists(path) {
-		return nil, errs.NotFound("file", path)
-	}
+// EncodeSession кодирует сессию в формат, в котором она лежит в SessionStorage
+func EncodeSession(s *Session) ([]byte, error) {
+	file := new(tokenStorageFormat)
+	file.Key = base64.StdEncoding.EncodeToString(s.Key)
+	file.Hash = base64.StdEncoding.EncodeToString(s.Hash)
+	file.Salt = base64.StdEncoding.EncodeToString(s.Salt)
+	file.Hostname = s.Hostname
+	file.DC = s.DC
+	file.UserID = s.UserID
+	file.Authorized = s.Authorized
 
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, errors.Wrap(err, "reading file")
-	}
+	return json.Marshal(file)
+}
 
+// DecodeSession обратна EncodeSession
+func DecodeSession(data []byte) (*Session, error) {
 	file := new(tokenStorageFormat)
-	err = json.Unmarshal(data, file)
+	err := json.Unmarshal(data, file)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing file")
 	}
@@ -89,27 +163,53 @@ func LoadSession(path string) (*Session, error) {
 		return nil, errors.Wrap(err, "invalid binary data of 'salt'")
 	}
 	res.Hostname = file.Hostname
+	res.DC = file.DC
+	res.UserID = file.UserID
+	res.Authorized = file.Authorized
 
 	return res, nil
 }
 
+func LoadSession(path string) (*Session, error) {
+	data, err := readSessionFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeSession(data)
+}
+
 func SaveSession(s *Session, path string) error {
-	file := new(tokenStorageFormat)
-	file.Key = base64.StdEncoding.EncodeToString(s.Key)
-	file.Hash = base64.StdEncoding.EncodeToString(s.Hash)
-	file.Salt = base64.StdEncoding.EncodeToString(s.Salt)
-	file.Hostname = s.Hostname
+	data, err := EncodeSession(s)
+	if err != nil {
+		return errors.Wrap(err, "encoding session")
+	}
+
+	return writeSessionFile(path, data)
+}
 
-	data, _ := json.Marshal(file)
+func readSessionFile(path string) ([]byte, error) {
+	if !dry.FileExists(path) {
+		return nil, errs.NotFound("file", path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading file")
+	}
+
+	return data, nil
+}
 
+func writeSessionFile(path string, data []byte) error {
 	dir, _ := filepath.Split(path)
-	if !dry.FileExists(dir) {
+	if dir != "" && !dry.FileExists(dir) {
 		err := os.MkdirAll(dir, 0700)
 		if err != nil {
 			return errors.Wrap(err, "creating directory")
 		}
 	}
-	if !dry.FileIsDir(dir) {
+	if dir != "" && !dry.FileIsDir(dir) {
 		return errors.New(path + ": not a directory")
 	}
 
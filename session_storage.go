@@ -0,0 +1,147 @@
+package mtproto
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xelaj/errs"
+)
+
+// HomeDC это номер, под которым в SessionStorage лежит основная сессия аккаунта (та, через
+// которую идут все запросы). ключи для остальных датацентров хранятся под их настоящими номерами
+const HomeDC = 0
+
+// SessionStorage хранит сессии в закодированном виде (см. EncodeSession). данные для хранилища
+// непрозрачны, поэтому хранилища можно оборачивать друг в друга, например
+// NewEncryptedSessionStorage(NewFileSessionStorage(dir), key)
+type SessionStorage interface {
+	// Load возвращает сохраненную сессию, или ошибку errs.NotFound, если сессии нет
+	Load(account string, dc int) ([]byte, error)
+	Store(account string, dc int, data []byte) error
+	// Delete удаляет сессию. если сессии нет, то это не ошибка
+	Delete(account string, dc int) error
+}
+
+func sessionKey(account string, dc int) string {
+	return account + "/" + strconv.Itoa(dc)
+}
+
+// MemorySessionStorage хранит сессии только в памяти, подходит для воркеров, которым не нужно ничего
+// сохранять между запусками
+type MemorySessionStorage struct {
+	mutex    sync.RWMutex
+	sessions map[string][]byte
+}
+
+func NewMemorySessionStorage() *MemorySessionStorage {
+	return &MemorySessionStorage{sessions: make(map[string][]byte)}
+}
+
+func (s *MemorySessionStorage) Load(account string, dc int) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	data, ok := s.sessions[sessionKey(account, dc)]
+	if !ok {
+		return nil, errs.NotFound("session", sessionKey(account, dc))
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (s *MemorySessionStorage) Store(account string, dc int, data []byte) error {
+	s.mutex.Lock()
+	s.sessions[sessionKey(account, dc)] = append([]byte(nil), data...)
+	s.mutex.Unlock()
+	return nil
+}
+
+func (s *MemorySessionStorage) Delete(account string, dc int) error {
+	s.mutex.Lock()
+	delete(s.sessions, sessionKey(account, dc))
+	s.mutex.Unlock()
+	return nil
+}
+
+// FileSessionStorage хранит каждую сессию в отдельном файле: Dir/<account>/<dc>.json. если аккаунт
+// не указан, то файл лежит прямо в Dir
+type FileSessionStorage struct {
+	Dir string
+}
+
+func NewFileSessionStorage(dir string) *FileSessionStorage {
+	return &FileSessionStorage{Dir: dir}
+}
+
+func (s *FileSessionStorage) path(account string, dc int) (string, error) {
+	name := strconv.Itoa(dc) + ".json"
+	if account == "" {
+		return filepath.Join(s.Dir, name), nil
+	}
+	// аккаунт становится именем директории, так что из Dir выйти не должно быть возможности
+	if account == "." || account == ".." || strings.ContainsAny(account, `/\`) {
+		return "", errors.New("invalid account name: " + account)
+	}
+	return filepath.Join(s.Dir, account, name), nil
+}
+
+func (s *FileSessionStorage) Load(account string, dc int) ([]byte, error) {
+	path, err := s.path(account, dc)
+	if err != nil {
+		return nil, err
+	}
+	return readSessionFile(path)
+}
+
+func (s *FileSessionStorage) Store(account string, dc int, data []byte) error {
+	path, err := s.path(account, dc)
+	if err != nil {
+		return err
+	}
+	return writeSessionFile(path, data)
+}
+
+func (s *FileSessionStorage) Delete(account string, dc int) error {
+	path, err := s.path(account, dc)
+	if err != nil {
+		return err
+	}
+	return removeSessionFile(path)
+}
+
+// singleFileSessionStorage нужен для Config.AuthKeyFile: основная сессия лежит ровно в этом файле,
+// как и раньше, а сессии других датацентров рядом, с номером датацентра в конце имени. аккаунт
+// игнорируется, в одном файле может быть только один аккаунт
+type singleFileSessionStorage struct {
+	path string
+}
+
+func (s *singleFileSessionStorage) pathFor(dc int) string {
+	if dc == HomeDC {
+		return s.path
+	}
+	return s.path + "." + strconv.Itoa(dc)
+}
+
+func (s *singleFileSessionStorage) Load(_ string, dc int) ([]byte, error) {
+	return readSessionFile(s.pathFor(dc))
+}
+
+func (s *singleFileSessionStorage) Store(_ string, dc int, data []byte) error {
+	return writeSessionFile(s.pathFor(dc), data)
+}
+
+func (s *singleFileSessionStorage) Delete(_ string, dc int) error {
+	return removeSessionFile(s.pathFor(dc))
+}
+
+func removeSessionFile(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "removing file")
+	}
+	return nil
+}
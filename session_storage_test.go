@@ -0,0 +1,167 @@
+package mtproto
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/xelaj/errs"
+
+	"github.com/lonesta/mtproto/transport"
+	"github.com/lonesta/mtproto/utils"
+)
+
+func testSessionStorage(t *testing.T, name string, s SessionStorage) {
+	_, err := s.Load("alice", 2)
+	if !errs.IsNotFound(err) {
+		t.Fatalf("%v: expected not found error for empty storage, got %v", name, err)
+	}
+
+	for _, dc := range []int{HomeDC, 2, 4} {
+		err = s.Store("alice", dc, []byte{byte(dc)})
+		if err != nil {
+			t.Fatalf("%v: storing session: %v", name, err)
+		}
+	}
+
+	for _, dc := range []int{HomeDC, 2, 4} {
+		data, err := s.Load("alice", dc)
+		if err != nil {
+			t.Fatalf("%v: loading session: %v", name, err)
+		}
+		if !bytes.Equal(data, []byte{byte(dc)}) {
+			t.Errorf("%v: dc %v: got %v", name, dc, data)
+		}
+	}
+
+	err = s.Delete("alice", 2)
+	if err != nil {
+		t.Fatalf("%v: deleting session: %v", name, err)
+	}
+	_, err = s.Load("alice", 2)
+	if !errs.IsNotFound(err) {
+		t.Errorf("%v: session was not deleted: %v", name, err)
+	}
+	if err = s.Delete("alice", 2); err != nil {
+		t.Errorf("%v: deleting missing session: %v", name, err)
+	}
+}
+
+func TestSessionStorages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtproto-sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	encrypted, err := NewEncryptedSessionStorage(NewMemorySessionStorage(), make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testSessionStorage(t, "memory", NewMemorySessionStorage())
+	testSessionStorage(t, "file", NewFileSessionStorage(dir))
+	testSessionStorage(t, "single file", &singleFileSessionStorage{path: filepath.Join(dir, "session.json")})
+	testSessionStorage(t, "encrypted", encrypted)
+}
+
+func TestFileSessionStorageAccountName(t *testing.T) {
+	s := NewFileSessionStorage("/nonexistent")
+	for _, account := range []string{"..", "../../etc", `a\b`} {
+		if err := s.Store(account, HomeDC, nil); err == nil {
+			t.Errorf("account %q must be rejected", account)
+		}
+	}
+}
+
+func TestEncryptedSessionStorage(t *testing.T) {
+	inner := NewMemorySessionStorage()
+	key := bytes.Repeat([]byte{1}, 32)
+	s, err := NewEncryptedSessionStorage(inner, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := []byte("auth key goes here")
+	if err = s.Store("alice", HomeDC, secret); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := inner.Load("alice", HomeDC)
+	if bytes.Contains(raw, secret) {
+		t.Fatal("session is stored in plain text")
+	}
+
+	// сессию одного аккаунта нельзя подсунуть другому
+	_ = inner.Store("bob", HomeDC, raw)
+	if _, err = s.Load("bob", HomeDC); err == nil {
+		t.Error("moved session must not decrypt")
+	}
+
+	wrong, _ := NewEncryptedSessionStorage(inner, bytes.Repeat([]byte{2}, 32))
+	if _, err = wrong.Load("alice", HomeDC); err == nil {
+		t.Error("session must not decrypt with wrong key")
+	}
+}
+
+func TestLegacySessionFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtproto-sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "session.json")
+	legacy := []byte(`{"key":"AQID","hash":"BAU=","salt":"AQAAAAAAAAA=","hostname":"149.154.167.50:443"}`)
+	if err = ioutil.WriteFile(path, legacy, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &MTProto{mutex: &sync.Mutex{}, sessionStorage: &singleFileSessionStorage{path: path}}
+	if err = m.LoadSession(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m.authKey, []byte{1, 2, 3}) || m.serverSalt != 1 || m.addr != "149.154.167.50:443" {
+		t.Errorf("session loaded incorrectly: %x %v %v", m.authKey, m.serverSalt, m.addr)
+	}
+}
+
+func TestLoadSessionViaProxy(t *testing.T) {
+	key := bytes.Repeat([]byte{2}, 256)
+	storage := NewMemorySessionStorage()
+	storeTestSession(t, storage, HomeDC, &Session{
+		Key: key, Hash: utils.AuthKeyHash(key), Salt: make([]byte, 8), Hostname: "149.154.167.50:443", DC: 2,
+	})
+	secret, err := transport.ParseProxySecret("00112233445566778899aabbccddeeff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxied, err := transport.NewObfuscated2(transport.NewIntermediate(), 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// с прокси подключаемся к нему, а не к адресу из сессии
+	m, err := NewMTProto(Config{SessionStorage: storage, Account: "acc", ServerHost: "127.0.0.1:8888", Transport: proxied})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.addr != "127.0.0.1:8888" {
+		t.Errorf("connecting to %v instead of proxy", m.addr)
+	}
+	// а в сессию попадает адрес датацентра, что бы ее можно было открыть и без прокси
+	if s := m.ExportSession(); s.Hostname != "149.154.167.50:443" {
+		t.Errorf("exported hostname %v", s.Hostname)
+	}
+
+	m, err = NewMTProto(Config{SessionStorage: storage, Account: "acc", ServerHost: "127.0.0.1:8888"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.addr != "149.154.167.50:443" {
+		t.Errorf("direct connection must use saved hostname, got %v", m.addr)
+	}
+}
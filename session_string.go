@@ -0,0 +1,119 @@
+package mtproto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/utils"
+)
+
+// строка сессии это версия (один символ) и base64 (url-safe, без паддинга) от:
+//
+//	dc (uint16) | длина адреса (uint8) | адрес | длина ключа (uint16) | ключ | соль (8 байт) | user id (int64)
+//
+// все числа little endian. хеш ключа не хранится, он считается из самого ключа
+
+const sessionStringVersion = '1'
+
+// ключ авторизации всегда 2048 бит, см. dhPrimeBits
+const sessionStringKeyLen = dhPrimeBits / 8
+
+// EncodeSessionString кодирует сессию в одну строку, которую удобно переносить между машинами
+func EncodeSessionString(s *Session) (string, error) {
+	if len(s.Hostname) > 0xff {
+		return "", errors.New("hostname is too long")
+	}
+	if len(s.Key) != sessionStringKeyLen {
+		return "", errors.Errorf("auth key must be %v bytes, got %v", sessionStringKeyLen, len(s.Key))
+	}
+	if len(s.Salt) != serialize.LongLen {
+		return "", errors.Errorf("salt must be %v bytes, got %v", serialize.LongLen, len(s.Salt))
+	}
+	if s.DC < 0 || s.DC > 0xffff {
+		return "", errors.Errorf("invalid dc %v", s.DC)
+	}
+
+	buf := new(bytes.Buffer)
+	_ = binary.Write(buf, binary.LittleEndian, uint16(s.DC))
+	buf.WriteByte(byte(len(s.Hostname)))
+	buf.WriteString(s.Hostname)
+	_ = binary.Write(buf, binary.LittleEndian, uint16(len(s.Key)))
+	buf.Write(s.Key)
+	buf.Write(s.Salt)
+	_ = binary.Write(buf, binary.LittleEndian, s.UserID)
+
+	return string(sessionStringVersion) + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeSessionString обратна EncodeSessionString
+func DecodeSessionString(str string) (*Session, error) {
+	if str == "" {
+		return nil, errors.New("empty session string")
+	}
+	if str[0] != sessionStringVersion {
+		return nil, errors.Errorf("unsupported session string version %q", str[0])
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(str[1:])
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding base64")
+	}
+	r := bytes.NewReader(data)
+
+	var dc uint16
+	if err := binary.Read(r, binary.LittleEndian, &dc); err != nil {
+		return nil, errors.Wrap(err, "reading dc")
+	}
+	hostLen, err := r.ReadByte()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading hostname")
+	}
+	host, err := readExactly(r, int(hostLen))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading hostname")
+	}
+	var keyLen uint16
+	if err := binary.Read(r, binary.LittleEndian, &keyLen); err != nil {
+		return nil, errors.Wrap(err, "reading auth key")
+	}
+	if keyLen != sessionStringKeyLen {
+		return nil, errors.Errorf("auth key must be %v bytes, got %v", sessionStringKeyLen, keyLen)
+	}
+	key, err := readExactly(r, int(keyLen))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading auth key")
+	}
+	salt, err := readExactly(r, serialize.LongLen)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading salt")
+	}
+	var userID int64
+	if err := binary.Read(r, binary.LittleEndian, &userID); err != nil {
+		return nil, errors.Wrap(err, "reading user id")
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("unexpected data at the end of session string")
+	}
+
+	return &Session{
+		Key:      key,
+		Hash:     utils.AuthKeyHash(key),
+		Salt:     salt,
+		Hostname: string(host),
+		DC:       int(dc),
+		UserID:   userID,
+	}, nil
+}
+
+func readExactly(r *bytes.Reader, n int) ([]byte, error) {
+	if r.Len() < n {
+		return nil, errors.New("session string is too short")
+	}
+	res := make([]byte, n)
+	_, _ = r.Read(res)
+	return res, nil
+}
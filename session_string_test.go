@@ -0,0 +1,87 @@
+package mtproto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/lonesta/mtproto/utils"
+)
+
+func TestSessionString(t *testing.T) {
+	key := bytes.Repeat([]byte{0xfe, 0xff}, 128)
+	cases := []*Session{
+		{
+			Key:      key,
+			Hash:     utils.AuthKeyHash(key),
+			Salt:     []byte{1, 2, 3, 4, 5, 6, 7, 8},
+			Hostname: "149.154.167.50:443",
+			DC:       2,
+			UserID:   123456789,
+		},
+		{
+			Key:      key,
+			Hash:     utils.AuthKeyHash(key),
+			Salt:     make([]byte, 8),
+			Hostname: "[2001:67c:4e8:f002::a]:443",
+			DC:       2,
+		},
+	}
+
+	urlSafe := regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	for _, session := range cases {
+		str, err := EncodeSessionString(session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if str[0] != sessionStringVersion || !urlSafe.MatchString(str) {
+			t.Errorf("session string is not versioned or not url-safe: %v", str)
+		}
+
+		got, err := DecodeSessionString(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, session) {
+			t.Errorf("got %+v, want %+v", got, session)
+		}
+	}
+}
+
+func TestSessionStringInvalid(t *testing.T) {
+	valid, err := EncodeSessionString(&Session{Key: make([]byte, 256), Salt: make([]byte, 8), Hostname: "localhost:443"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, str := range []string{
+		"",
+		"2" + valid[1:],          // неизвестная версия
+		valid[:len(valid)-4],     // обрезанная строка
+		valid + "AAAA",           // мусор в конце
+		"1" + "!!!not base64!!!", // не base64
+	} {
+		if _, err := DecodeSessionString(str); err == nil {
+			t.Errorf("%q must not decode", str)
+		}
+	}
+
+	if _, err = EncodeSessionString(&Session{Key: make([]byte, 256), Salt: []byte{1}}); err == nil {
+		t.Error("short salt must be rejected")
+	}
+	if _, err = EncodeSessionString(&Session{Key: []byte{1}, Salt: make([]byte, 8)}); err == nil {
+		t.Error("short key must be rejected")
+	}
+
+	// ключ другой длины, но в остальном строка правильная
+	short := new(bytes.Buffer)
+	short.Write([]byte{2, 0, 0})
+	_ = binary.Write(short, binary.LittleEndian, uint16(1))
+	short.Write(make([]byte, 1+8+8))
+	if _, err = DecodeSessionString("1" + base64.RawURLEncoding.EncodeToString(short.Bytes())); err == nil {
+		t.Error("session string with short key must not decode")
+	}
+}
@@ -1,8 +1,12 @@
 package telegram
 
 import (
+	"crypto/rsa"
+	"net"
 	"reflect"
 	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/k0kubun/pp"
 	"github.com/pkg/errors"
@@ -12,8 +16,12 @@ import (
 	"github.com/lonesta/mtproto"
 	"github.com/lonesta/mtproto/keys"
 	"github.com/lonesta/mtproto/serialize"
+	"github.com/lonesta/mtproto/transport"
 )
 
+// defaultDC is a datacenter which is used for connecting through proxy if nothing else is set
+const defaultDC = 2
+
 type Client struct {
 	*mtproto.MTProto
 	config       *ClientConfig
@@ -21,24 +29,63 @@ type Client struct {
 }
 
 type ClientConfig struct {
-	SessionFile    string
-	ServerHost     string
+	// SessionFile is a path to session file. it's used only if SessionStorage is nil
+	SessionFile string
+	// SessionStorage keeps auth keys of account. if both SessionStorage and SessionFile are empty,
+	// session is kept only in memory
+	SessionStorage mtproto.SessionStorage
+	// Account is a name of account in SessionStorage, it's required only if storage keeps more than
+	// one account
+	Account string
+	// SessionPassphrase encrypts sessions with key derived from this passphrase. existing
+	// unencrypted sessions are encrypted on first load
+	SessionPassphrase string
+	// SessionString is a session exported with Client.ExportSessionString. if set, client uses it
+	// instead of session from file or storage
+	SessionString string
+
+	ServerHost string
+	// PublicKeysFile is a path to PEM file with public keys of telegram servers. keys of production
+	// and test servers are built in, so it's needed only if telegram changes them
 	PublicKeysFile string
 	DeviceModel    string
 	SystemVersion  string
 	AppVersion     string
 	AppID          int
 	AppHash        string
+	// Transport is a way to pack mtproto packets into the connection. intermediate is used if nil
+	Transport transport.Transport
+	// Proxy is a MTProxy link (tg://proxy?server=...&port=...&secret=...). if set, client connects
+	// through this proxy instead of ServerHost. fake-TLS (ee) secrets are supported too
+	Proxy string
+	// DC is a number of datacenter which proxy must connect to. 2 by default
+	DC int
+	// Dialer opens all connections to telegram servers, use it to connect through SOCKS5 or HTTP
+	// proxies (see transport.DialerFromURL). direct connection is used if nil
+	Dialer transport.Dialer
+	// CompressionThreshold is a size of request in bytes, starting from which request is packed
+	// into gzip_packed. 1024 if zero, negative value disables compression
+	CompressionThreshold int
+	// TempKeyTTL enables perfect forward secrecy: traffic is encrypted with temporary keys bound to
+	// the permanent one, and new temporary key is created before the old one expires. temporary keys
+	// are never saved. PFS is disabled if zero
+	TempKeyTTL time.Duration
+	// TestServer must be set when client connects to telegram test servers, it changes the number of
+	// datacenter sent while creating temporary keys
+	TestServer bool
 }
 
 func NewClient(c ClientConfig) (*Client, error) { //nolint: gocritic arg is not ptr cause we call
 	//                                                               it only once, don't care
 	//                                                               about copying big args.
-	if !dry.FileExists(c.PublicKeysFile) {
-		return nil, errs.NotFound("file", c.PublicKeysFile)
+	if c.SessionString != "" {
+		err := importSessionString(&c)
+		if err != nil {
+			return nil, errors.Wrap(err, "importing session string")
+		}
 	}
 
-	if !dry.PathIsWirtable(c.SessionFile) {
+	if c.SessionStorage == nil && c.SessionFile != "" && !dry.PathIsWirtable(c.SessionFile) {
 		return nil, errs.Permission(c.SessionFile).Scope("write")
 	}
 
@@ -54,15 +101,44 @@ func NewClient(c ClientConfig) (*Client, error) { //nolint: gocritic arg is not
 		c.AppVersion = "v0.0.0"
 	}
 
-	publicKeys, err := keys.ReadFromFile(c.PublicKeysFile)
-	if err != nil {
-		return nil, errors.Wrap(err, "reading public keys")
+	// without the file, keys built into the package are used
+	var publicKeys []*rsa.PublicKey
+	if c.PublicKeysFile != "" {
+		var err error
+		publicKeys, err = keys.ReadFromFile(c.PublicKeysFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading public keys")
+		}
+	}
+
+	if c.Proxy != "" {
+		addr, secret, err := transport.ParseProxyLink(c.Proxy)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing proxy link")
+		}
+		if c.DC == 0 {
+			c.DC = defaultDC
+		}
+
+		c.Transport, err = transport.NewObfuscated2(c.Transport, c.DC, secret)
+		if err != nil {
+			return nil, errors.Wrap(err, "setting up proxy transport")
+		}
+		c.ServerHost = addr
 	}
 
 	m, err := mtproto.NewMTProto(mtproto.Config{
-		AuthKeyFile: c.SessionFile,
-		ServerHost:  c.ServerHost,
-		PublicKey:   publicKeys[0],
+		AuthKeyFile:          c.SessionFile,
+		SessionStorage:       c.SessionStorage,
+		Account:              c.Account,
+		SessionPassphrase:    c.SessionPassphrase,
+		ServerHost:           c.ServerHost,
+		PublicKeys:           publicKeys,
+		Transport:            c.Transport,
+		Dialer:               c.Dialer,
+		CompressionThreshold: c.CompressionThreshold,
+		TempKeyTTL:           c.TempKeyTTL,
+		TestServer:           c.TestServer,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "setup common MTProto client")
@@ -80,15 +156,11 @@ func NewClient(c ClientConfig) (*Client, error) { //nolint: gocritic arg is not
 
 	client.AddCustomServerRequestHandler(client.handleSpecialRequests())
 
-	resp, err := client.InvokeWithLayer(ApiVersion, &InitConnectionParams{
-		ApiID:          int32(c.AppID),
-		DeviceModel:    c.DeviceModel,
-		SystemVersion:  c.SystemVersion,
-		AppVersion:     c.AppVersion,
-		SystemLangCode: "en", // can't be edited, cause docs says that a single possible parameter
-		LangCode:       "en",
-		Query:          &HelpGetConfigParams{},
-	})
+	client.SetDCConnectHandler(client.transferAuthorization)
+	// the first temporary key is already bound, connection with it is initialized right below
+	client.SetTempKeyBoundHandler(client.reinitConnection)
+
+	resp, err := client.InvokeWithLayer(ApiVersion, client.initConnectionParams(&HelpGetConfigParams{}))
 
 	if err != nil {
 		return nil, errors.Wrap(err, "getting server configs")
@@ -103,17 +175,66 @@ func NewClient(c ClientConfig) (*Client, error) { //nolint: gocritic arg is not
 
 	dcList := make(map[int]string)
 	for _, dc := range config.DcOptions {
-		if dc.Cdn {
+		// cdn and media datacenters can't be used for regular requests, ipv6 isn't supported yet
+		if dc.Cdn || dc.MediaOnly || dc.Ipv6 || dc.TcpoOnly {
+			continue
+		}
+		if _, ok := dcList[int(dc.Id)]; ok {
 			continue
 		}
 
-		dcList[int(dc.Id)] = dc.IpAddress
+		dcList[int(dc.Id)] = net.JoinHostPort(dc.IpAddress, strconv.Itoa(int(dc.Port)))
 	}
 	client.SetDCStorages(dcList)
 
 	return client, nil
 }
 
+// initConnectionParams wraps query into initConnection with parameters of this client
+func (c *Client) initConnectionParams(query serialize.TLEncoder) *InitConnectionParams {
+	return &InitConnectionParams{
+		ApiID:          int32(c.config.AppID),
+		DeviceModel:    c.config.DeviceModel,
+		SystemVersion:  c.config.SystemVersion,
+		AppVersion:     c.config.AppVersion,
+		SystemLangCode: "en", // can't be edited, cause docs says that a single possible parameter
+		LangCode:       "en",
+		Query:          query,
+	}
+}
+
+// reinitConnection is called after every new temporary key is bound: server treats connection with
+// new key as a new one, so it must be initialized again
+func (c *Client) reinitConnection(conn *mtproto.MTProto) error {
+	_, err := c.withConn(conn).InvokeWithLayer(ApiVersion, c.initConnectionParams(&HelpGetConfigParams{}))
+	return errors.Wrap(err, "initializing connection")
+}
+
+// importSessionString puts session from config into session storage, so mtproto client will load it
+// like any other stored session
+func importSessionString(c *ClientConfig) error {
+	session, err := mtproto.DecodeSessionString(c.SessionString)
+	if err != nil {
+		return errors.Wrap(err, "decoding")
+	}
+	data, err := mtproto.EncodeSession(session)
+	if err != nil {
+		return errors.Wrap(err, "encoding")
+	}
+
+	if c.SessionStorage == nil {
+		c.SessionStorage = mtproto.NewMemorySessionStorage()
+	}
+	// passphrase storage will encrypt this plain session on first load
+	return c.SessionStorage.Store(c.Account, mtproto.HomeDC, data)
+}
+
+// ExportSessionString returns current session as a single string, which can be passed to
+// ClientConfig.SessionString on another machine
+func (c *Client) ExportSessionString() (string, error) {
+	return mtproto.EncodeSessionString(c.ExportSession())
+}
+
 func (c *Client) handleSpecialRequests() func(interface{}) bool {
 	return func(i interface{}) bool {
 		switch msg := i.(type) {
@@ -0,0 +1,53 @@
+package telegram
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/lonesta/mtproto"
+)
+
+// ForDC returns client connected to datacenter dc, e.g. to download files stored there. connection
+// is opened on first call and kept until the main client is disconnected. each datacenter has its
+// own auth key, which is saved in the same session storage under the number of datacenter
+func (c *Client) ForDC(dc int) (*Client, error) {
+	conn, err := c.ConnectDC(dc)
+	if err != nil {
+		return nil, err
+	}
+	if conn == c.MTProto {
+		return c, nil
+	}
+
+	return c.withConn(conn), nil
+}
+
+func (c *Client) withConn(conn *mtproto.MTProto) *Client {
+	return &Client{
+		MTProto:      conn,
+		config:       c.config,
+		serverConfig: c.serverConfig,
+	}
+}
+
+// transferAuthorization is called for every new connection to another datacenter. it exports
+// authorization from the home datacenter and imports it into the new one, so the new connection is
+// signed in as the same user
+func (c *Client) transferAuthorization(conn *mtproto.MTProto) error {
+	exported, err := c.AuthExportAuthorization(&AuthExportAuthorizationParams{
+		DcId: int32(conn.DC()),
+	})
+	if err != nil {
+		return errors.Wrap(err, "exporting authorization")
+	}
+
+	// connection must be initialized with the first request, so import is wrapped into initConnection
+	_, err = c.withConn(conn).InvokeWithLayer(ApiVersion, c.initConnectionParams(&AuthImportAuthorizationParams{
+		Id:    exported.Id,
+		Bytes: exported.Bytes,
+	}))
+	if err != nil {
+		return errors.Wrap(err, "importing authorization")
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@
 package telegram
 
 import (
+	"context"
 	"reflect"
 
 	validator "github.com/go-playground/validator"
@@ -37,7 +38,11 @@ func (e *AuthSendCodeParams) Encode() []byte {
 }
 
 func (c *Client) AuthSendCode(params *AuthSendCodeParams) (*AuthSentCode, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthSendCodeContext(context.Background(), params)
+}
+
+func (c *Client) AuthSendCodeContext(ctx context.Context, params *AuthSendCodeParams) (*AuthSentCode, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthSendCode")
 	}
@@ -75,7 +80,11 @@ func (e *AuthSignUpParams) Encode() []byte {
 }
 
 func (c *Client) AuthSignUp(params *AuthSignUpParams) (AuthAuthorization, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthSignUpContext(context.Background(), params)
+}
+
+func (c *Client) AuthSignUpContext(ctx context.Context, params *AuthSignUpParams) (AuthAuthorization, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthSignUp")
 	}
@@ -111,7 +120,11 @@ func (e *AuthSignInParams) Encode() []byte {
 }
 
 func (c *Client) AuthSignIn(params *AuthSignInParams) (AuthAuthorization, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthSignInContext(context.Background(), params)
+}
+
+func (c *Client) AuthSignInContext(ctx context.Context, params *AuthSignInParams) (AuthAuthorization, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthSignIn")
 	}
@@ -137,7 +150,11 @@ func (e *AuthLogOutParams) Encode() []byte {
 }
 
 func (c *Client) AuthLogOut() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&AuthLogOutParams{})
+	return c.AuthLogOutContext(context.Background())
+}
+
+func (c *Client) AuthLogOutContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &AuthLogOutParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthLogOut")
 	}
@@ -163,7 +180,11 @@ func (e *AuthResetAuthorizationsParams) Encode() []byte {
 }
 
 func (c *Client) AuthResetAuthorizations() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&AuthResetAuthorizationsParams{})
+	return c.AuthResetAuthorizationsContext(context.Background())
+}
+
+func (c *Client) AuthResetAuthorizationsContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &AuthResetAuthorizationsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthResetAuthorizations")
 	}
@@ -195,7 +216,11 @@ func (e *AuthExportAuthorizationParams) Encode() []byte {
 }
 
 func (c *Client) AuthExportAuthorization(params *AuthExportAuthorizationParams) (*AuthExportedAuthorization, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthExportAuthorizationContext(context.Background(), params)
+}
+
+func (c *Client) AuthExportAuthorizationContext(ctx context.Context, params *AuthExportAuthorizationParams) (*AuthExportedAuthorization, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthExportAuthorization")
 	}
@@ -229,7 +254,11 @@ func (e *AuthImportAuthorizationParams) Encode() []byte {
 }
 
 func (c *Client) AuthImportAuthorization(params *AuthImportAuthorizationParams) (AuthAuthorization, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthImportAuthorizationContext(context.Background(), params)
+}
+
+func (c *Client) AuthImportAuthorizationContext(ctx context.Context, params *AuthImportAuthorizationParams) (AuthAuthorization, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthImportAuthorization")
 	}
@@ -267,7 +296,11 @@ func (e *AuthBindTempAuthKeyParams) Encode() []byte {
 }
 
 func (c *Client) AuthBindTempAuthKey(params *AuthBindTempAuthKeyParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthBindTempAuthKeyContext(context.Background(), params)
+}
+
+func (c *Client) AuthBindTempAuthKeyContext(ctx context.Context, params *AuthBindTempAuthKeyParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthBindTempAuthKey")
 	}
@@ -305,7 +338,11 @@ func (e *AuthImportBotAuthorizationParams) Encode() []byte {
 }
 
 func (c *Client) AuthImportBotAuthorization(params *AuthImportBotAuthorizationParams) (AuthAuthorization, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthImportBotAuthorizationContext(context.Background(), params)
+}
+
+func (c *Client) AuthImportBotAuthorizationContext(ctx context.Context, params *AuthImportBotAuthorizationParams) (AuthAuthorization, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthImportBotAuthorization")
 	}
@@ -337,7 +374,11 @@ func (e *AuthCheckPasswordParams) Encode() []byte {
 }
 
 func (c *Client) AuthCheckPassword(params *AuthCheckPasswordParams) (AuthAuthorization, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthCheckPasswordContext(context.Background(), params)
+}
+
+func (c *Client) AuthCheckPasswordContext(ctx context.Context, params *AuthCheckPasswordParams) (AuthAuthorization, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthCheckPassword")
 	}
@@ -363,7 +404,11 @@ func (e *AuthRequestPasswordRecoveryParams) Encode() []byte {
 }
 
 func (c *Client) AuthRequestPasswordRecovery() (*AuthPasswordRecovery, error) {
-	data, err := c.MakeRequest(&AuthRequestPasswordRecoveryParams{})
+	return c.AuthRequestPasswordRecoveryContext(context.Background())
+}
+
+func (c *Client) AuthRequestPasswordRecoveryContext(ctx context.Context) (*AuthPasswordRecovery, error) {
+	data, err := c.MakeRequestContext(ctx, &AuthRequestPasswordRecoveryParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthRequestPasswordRecovery")
 	}
@@ -395,7 +440,11 @@ func (e *AuthRecoverPasswordParams) Encode() []byte {
 }
 
 func (c *Client) AuthRecoverPassword(params *AuthRecoverPasswordParams) (AuthAuthorization, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthRecoverPasswordContext(context.Background(), params)
+}
+
+func (c *Client) AuthRecoverPasswordContext(ctx context.Context, params *AuthRecoverPasswordParams) (AuthAuthorization, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthRecoverPassword")
 	}
@@ -429,7 +478,11 @@ func (e *AuthResendCodeParams) Encode() []byte {
 }
 
 func (c *Client) AuthResendCode(params *AuthResendCodeParams) (*AuthSentCode, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthResendCodeContext(context.Background(), params)
+}
+
+func (c *Client) AuthResendCodeContext(ctx context.Context, params *AuthResendCodeParams) (*AuthSentCode, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthResendCode")
 	}
@@ -463,7 +516,11 @@ func (e *AuthCancelCodeParams) Encode() []byte {
 }
 
 func (c *Client) AuthCancelCode(params *AuthCancelCodeParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthCancelCodeContext(context.Background(), params)
+}
+
+func (c *Client) AuthCancelCodeContext(ctx context.Context, params *AuthCancelCodeParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthCancelCode")
 	}
@@ -495,7 +552,11 @@ func (e *AuthDropTempAuthKeysParams) Encode() []byte {
 }
 
 func (c *Client) AuthDropTempAuthKeys(params *AuthDropTempAuthKeysParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthDropTempAuthKeysContext(context.Background(), params)
+}
+
+func (c *Client) AuthDropTempAuthKeysContext(ctx context.Context, params *AuthDropTempAuthKeysParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthDropTempAuthKeys")
 	}
@@ -531,7 +592,11 @@ func (e *AuthExportLoginTokenParams) Encode() []byte {
 }
 
 func (c *Client) AuthExportLoginToken(params *AuthExportLoginTokenParams) (AuthLoginToken, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthExportLoginTokenContext(context.Background(), params)
+}
+
+func (c *Client) AuthExportLoginTokenContext(ctx context.Context, params *AuthExportLoginTokenParams) (AuthLoginToken, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthExportLoginToken")
 	}
@@ -563,7 +628,11 @@ func (e *AuthImportLoginTokenParams) Encode() []byte {
 }
 
 func (c *Client) AuthImportLoginToken(params *AuthImportLoginTokenParams) (AuthLoginToken, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthImportLoginTokenContext(context.Background(), params)
+}
+
+func (c *Client) AuthImportLoginTokenContext(ctx context.Context, params *AuthImportLoginTokenParams) (AuthLoginToken, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthImportLoginToken")
 	}
@@ -595,7 +664,11 @@ func (e *AuthAcceptLoginTokenParams) Encode() []byte {
 }
 
 func (c *Client) AuthAcceptLoginToken(params *AuthAcceptLoginTokenParams) (*Authorization, error) {
-	data, err := c.MakeRequest(params)
+	return c.AuthAcceptLoginTokenContext(context.Background(), params)
+}
+
+func (c *Client) AuthAcceptLoginTokenContext(ctx context.Context, params *AuthAcceptLoginTokenParams) (*Authorization, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AuthAcceptLoginToken")
 	}
@@ -642,7 +715,11 @@ func (e *AccountRegisterDeviceParams) Encode() []byte {
 }
 
 func (c *Client) AccountRegisterDevice(params *AccountRegisterDeviceParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountRegisterDeviceContext(context.Background(), params)
+}
+
+func (c *Client) AccountRegisterDeviceContext(ctx context.Context, params *AccountRegisterDeviceParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountRegisterDevice")
 	}
@@ -678,7 +755,11 @@ func (e *AccountUnregisterDeviceParams) Encode() []byte {
 }
 
 func (c *Client) AccountUnregisterDevice(params *AccountUnregisterDeviceParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUnregisterDeviceContext(context.Background(), params)
+}
+
+func (c *Client) AccountUnregisterDeviceContext(ctx context.Context, params *AccountUnregisterDeviceParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUnregisterDevice")
 	}
@@ -712,7 +793,11 @@ func (e *AccountUpdateNotifySettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountUpdateNotifySettings(params *AccountUpdateNotifySettingsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUpdateNotifySettingsContext(context.Background(), params)
+}
+
+func (c *Client) AccountUpdateNotifySettingsContext(ctx context.Context, params *AccountUpdateNotifySettingsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUpdateNotifySettings")
 	}
@@ -744,7 +829,11 @@ func (e *AccountGetNotifySettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetNotifySettings(params *AccountGetNotifySettingsParams) (*PeerNotifySettings, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetNotifySettingsContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetNotifySettingsContext(ctx context.Context, params *AccountGetNotifySettingsParams) (*PeerNotifySettings, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetNotifySettings")
 	}
@@ -770,7 +859,11 @@ func (e *AccountResetNotifySettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountResetNotifySettings() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&AccountResetNotifySettingsParams{})
+	return c.AccountResetNotifySettingsContext(context.Background())
+}
+
+func (c *Client) AccountResetNotifySettingsContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountResetNotifySettingsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountResetNotifySettings")
 	}
@@ -824,7 +917,11 @@ func (e *AccountUpdateProfileParams) Encode() []byte {
 }
 
 func (c *Client) AccountUpdateProfile(params *AccountUpdateProfileParams) (User, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUpdateProfileContext(context.Background(), params)
+}
+
+func (c *Client) AccountUpdateProfileContext(ctx context.Context, params *AccountUpdateProfileParams) (User, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUpdateProfile")
 	}
@@ -856,7 +953,11 @@ func (e *AccountUpdateStatusParams) Encode() []byte {
 }
 
 func (c *Client) AccountUpdateStatus(params *AccountUpdateStatusParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUpdateStatusContext(context.Background(), params)
+}
+
+func (c *Client) AccountUpdateStatusContext(ctx context.Context, params *AccountUpdateStatusParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUpdateStatus")
 	}
@@ -888,7 +989,11 @@ func (e *AccountGetWallPapersParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetWallPapers(params *AccountGetWallPapersParams) (AccountWallPapers, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetWallPapersContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetWallPapersContext(ctx context.Context, params *AccountGetWallPapersParams) (AccountWallPapers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetWallPapers")
 	}
@@ -922,7 +1027,11 @@ func (e *AccountReportPeerParams) Encode() []byte {
 }
 
 func (c *Client) AccountReportPeer(params *AccountReportPeerParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountReportPeerContext(context.Background(), params)
+}
+
+func (c *Client) AccountReportPeerContext(ctx context.Context, params *AccountReportPeerParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountReportPeer")
 	}
@@ -954,7 +1063,11 @@ func (e *AccountCheckUsernameParams) Encode() []byte {
 }
 
 func (c *Client) AccountCheckUsername(params *AccountCheckUsernameParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountCheckUsernameContext(context.Background(), params)
+}
+
+func (c *Client) AccountCheckUsernameContext(ctx context.Context, params *AccountCheckUsernameParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountCheckUsername")
 	}
@@ -986,7 +1099,11 @@ func (e *AccountUpdateUsernameParams) Encode() []byte {
 }
 
 func (c *Client) AccountUpdateUsername(params *AccountUpdateUsernameParams) (User, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUpdateUsernameContext(context.Background(), params)
+}
+
+func (c *Client) AccountUpdateUsernameContext(ctx context.Context, params *AccountUpdateUsernameParams) (User, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUpdateUsername")
 	}
@@ -1018,7 +1135,11 @@ func (e *AccountGetPrivacyParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetPrivacy(params *AccountGetPrivacyParams) (*AccountPrivacyRules, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetPrivacyContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetPrivacyContext(ctx context.Context, params *AccountGetPrivacyParams) (*AccountPrivacyRules, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetPrivacy")
 	}
@@ -1052,7 +1173,11 @@ func (e *AccountSetPrivacyParams) Encode() []byte {
 }
 
 func (c *Client) AccountSetPrivacy(params *AccountSetPrivacyParams) (*AccountPrivacyRules, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSetPrivacyContext(context.Background(), params)
+}
+
+func (c *Client) AccountSetPrivacyContext(ctx context.Context, params *AccountSetPrivacyParams) (*AccountPrivacyRules, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSetPrivacy")
 	}
@@ -1084,7 +1209,11 @@ func (e *AccountDeleteAccountParams) Encode() []byte {
 }
 
 func (c *Client) AccountDeleteAccount(params *AccountDeleteAccountParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountDeleteAccountContext(context.Background(), params)
+}
+
+func (c *Client) AccountDeleteAccountContext(ctx context.Context, params *AccountDeleteAccountParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountDeleteAccount")
 	}
@@ -1110,7 +1239,11 @@ func (e *AccountGetAccountTTLParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetAccountTTL() (*AccountDaysTTL, error) {
-	data, err := c.MakeRequest(&AccountGetAccountTTLParams{})
+	return c.AccountGetAccountTTLContext(context.Background())
+}
+
+func (c *Client) AccountGetAccountTTLContext(ctx context.Context) (*AccountDaysTTL, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountGetAccountTTLParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetAccountTTL")
 	}
@@ -1142,7 +1275,11 @@ func (e *AccountSetAccountTTLParams) Encode() []byte {
 }
 
 func (c *Client) AccountSetAccountTTL(params *AccountSetAccountTTLParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSetAccountTTLContext(context.Background(), params)
+}
+
+func (c *Client) AccountSetAccountTTLContext(ctx context.Context, params *AccountSetAccountTTLParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSetAccountTTL")
 	}
@@ -1176,7 +1313,11 @@ func (e *AccountSendChangePhoneCodeParams) Encode() []byte {
 }
 
 func (c *Client) AccountSendChangePhoneCode(params *AccountSendChangePhoneCodeParams) (*AuthSentCode, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSendChangePhoneCodeContext(context.Background(), params)
+}
+
+func (c *Client) AccountSendChangePhoneCodeContext(ctx context.Context, params *AccountSendChangePhoneCodeParams) (*AuthSentCode, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSendChangePhoneCode")
 	}
@@ -1212,7 +1353,11 @@ func (e *AccountChangePhoneParams) Encode() []byte {
 }
 
 func (c *Client) AccountChangePhone(params *AccountChangePhoneParams) (User, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountChangePhoneContext(context.Background(), params)
+}
+
+func (c *Client) AccountChangePhoneContext(ctx context.Context, params *AccountChangePhoneParams) (User, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountChangePhone")
 	}
@@ -1244,7 +1389,11 @@ func (e *AccountUpdateDeviceLockedParams) Encode() []byte {
 }
 
 func (c *Client) AccountUpdateDeviceLocked(params *AccountUpdateDeviceLockedParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUpdateDeviceLockedContext(context.Background(), params)
+}
+
+func (c *Client) AccountUpdateDeviceLockedContext(ctx context.Context, params *AccountUpdateDeviceLockedParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUpdateDeviceLocked")
 	}
@@ -1270,7 +1419,11 @@ func (e *AccountGetAuthorizationsParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetAuthorizations() (*AccountAuthorizations, error) {
-	data, err := c.MakeRequest(&AccountGetAuthorizationsParams{})
+	return c.AccountGetAuthorizationsContext(context.Background())
+}
+
+func (c *Client) AccountGetAuthorizationsContext(ctx context.Context) (*AccountAuthorizations, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountGetAuthorizationsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetAuthorizations")
 	}
@@ -1302,7 +1455,11 @@ func (e *AccountResetAuthorizationParams) Encode() []byte {
 }
 
 func (c *Client) AccountResetAuthorization(params *AccountResetAuthorizationParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountResetAuthorizationContext(context.Background(), params)
+}
+
+func (c *Client) AccountResetAuthorizationContext(ctx context.Context, params *AccountResetAuthorizationParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountResetAuthorization")
 	}
@@ -1328,7 +1485,11 @@ func (e *AccountGetPasswordParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetPassword() (*AccountPassword, error) {
-	data, err := c.MakeRequest(&AccountGetPasswordParams{})
+	return c.AccountGetPasswordContext(context.Background())
+}
+
+func (c *Client) AccountGetPasswordContext(ctx context.Context) (*AccountPassword, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountGetPasswordParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetPassword")
 	}
@@ -1360,7 +1521,11 @@ func (e *AccountGetPasswordSettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetPasswordSettings(params *AccountGetPasswordSettingsParams) (*AccountPasswordSettings, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetPasswordSettingsContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetPasswordSettingsContext(ctx context.Context, params *AccountGetPasswordSettingsParams) (*AccountPasswordSettings, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetPasswordSettings")
 	}
@@ -1394,7 +1559,11 @@ func (e *AccountUpdatePasswordSettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountUpdatePasswordSettings(params *AccountUpdatePasswordSettingsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUpdatePasswordSettingsContext(context.Background(), params)
+}
+
+func (c *Client) AccountUpdatePasswordSettingsContext(ctx context.Context, params *AccountUpdatePasswordSettingsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUpdatePasswordSettings")
 	}
@@ -1428,7 +1597,11 @@ func (e *AccountSendConfirmPhoneCodeParams) Encode() []byte {
 }
 
 func (c *Client) AccountSendConfirmPhoneCode(params *AccountSendConfirmPhoneCodeParams) (*AuthSentCode, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSendConfirmPhoneCodeContext(context.Background(), params)
+}
+
+func (c *Client) AccountSendConfirmPhoneCodeContext(ctx context.Context, params *AccountSendConfirmPhoneCodeParams) (*AuthSentCode, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSendConfirmPhoneCode")
 	}
@@ -1462,7 +1635,11 @@ func (e *AccountConfirmPhoneParams) Encode() []byte {
 }
 
 func (c *Client) AccountConfirmPhone(params *AccountConfirmPhoneParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountConfirmPhoneContext(context.Background(), params)
+}
+
+func (c *Client) AccountConfirmPhoneContext(ctx context.Context, params *AccountConfirmPhoneParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountConfirmPhone")
 	}
@@ -1496,7 +1673,11 @@ func (e *AccountGetTmpPasswordParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetTmpPassword(params *AccountGetTmpPasswordParams) (*AccountTmpPassword, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetTmpPasswordContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetTmpPasswordContext(ctx context.Context, params *AccountGetTmpPasswordParams) (*AccountTmpPassword, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetTmpPassword")
 	}
@@ -1522,7 +1703,11 @@ func (e *AccountGetWebAuthorizationsParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetWebAuthorizations() (*AccountWebAuthorizations, error) {
-	data, err := c.MakeRequest(&AccountGetWebAuthorizationsParams{})
+	return c.AccountGetWebAuthorizationsContext(context.Background())
+}
+
+func (c *Client) AccountGetWebAuthorizationsContext(ctx context.Context) (*AccountWebAuthorizations, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountGetWebAuthorizationsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetWebAuthorizations")
 	}
@@ -1554,7 +1739,11 @@ func (e *AccountResetWebAuthorizationParams) Encode() []byte {
 }
 
 func (c *Client) AccountResetWebAuthorization(params *AccountResetWebAuthorizationParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountResetWebAuthorizationContext(context.Background(), params)
+}
+
+func (c *Client) AccountResetWebAuthorizationContext(ctx context.Context, params *AccountResetWebAuthorizationParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountResetWebAuthorization")
 	}
@@ -1580,7 +1769,11 @@ func (e *AccountResetWebAuthorizationsParams) Encode() []byte {
 }
 
 func (c *Client) AccountResetWebAuthorizations() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&AccountResetWebAuthorizationsParams{})
+	return c.AccountResetWebAuthorizationsContext(context.Background())
+}
+
+func (c *Client) AccountResetWebAuthorizationsContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountResetWebAuthorizationsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountResetWebAuthorizations")
 	}
@@ -1606,7 +1799,11 @@ func (e *AccountGetAllSecureValuesParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetAllSecureValues() (*SecureValue, error) {
-	data, err := c.MakeRequest(&AccountGetAllSecureValuesParams{})
+	return c.AccountGetAllSecureValuesContext(context.Background())
+}
+
+func (c *Client) AccountGetAllSecureValuesContext(ctx context.Context) (*SecureValue, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountGetAllSecureValuesParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetAllSecureValues")
 	}
@@ -1638,7 +1835,11 @@ func (e *AccountGetSecureValueParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetSecureValue(params *AccountGetSecureValueParams) (*SecureValue, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetSecureValueContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetSecureValueContext(ctx context.Context, params *AccountGetSecureValueParams) (*SecureValue, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetSecureValue")
 	}
@@ -1672,7 +1873,11 @@ func (e *AccountSaveSecureValueParams) Encode() []byte {
 }
 
 func (c *Client) AccountSaveSecureValue(params *AccountSaveSecureValueParams) (*SecureValue, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSaveSecureValueContext(context.Background(), params)
+}
+
+func (c *Client) AccountSaveSecureValueContext(ctx context.Context, params *AccountSaveSecureValueParams) (*SecureValue, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSaveSecureValue")
 	}
@@ -1704,7 +1909,11 @@ func (e *AccountDeleteSecureValueParams) Encode() []byte {
 }
 
 func (c *Client) AccountDeleteSecureValue(params *AccountDeleteSecureValueParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountDeleteSecureValueContext(context.Background(), params)
+}
+
+func (c *Client) AccountDeleteSecureValueContext(ctx context.Context, params *AccountDeleteSecureValueParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountDeleteSecureValue")
 	}
@@ -1740,7 +1949,11 @@ func (e *AccountGetAuthorizationFormParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetAuthorizationForm(params *AccountGetAuthorizationFormParams) (*AccountAuthorizationForm, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetAuthorizationFormContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetAuthorizationFormContext(ctx context.Context, params *AccountGetAuthorizationFormParams) (*AccountAuthorizationForm, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetAuthorizationForm")
 	}
@@ -1780,7 +1993,11 @@ func (e *AccountAcceptAuthorizationParams) Encode() []byte {
 }
 
 func (c *Client) AccountAcceptAuthorization(params *AccountAcceptAuthorizationParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountAcceptAuthorizationContext(context.Background(), params)
+}
+
+func (c *Client) AccountAcceptAuthorizationContext(ctx context.Context, params *AccountAcceptAuthorizationParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountAcceptAuthorization")
 	}
@@ -1814,7 +2031,11 @@ func (e *AccountSendVerifyPhoneCodeParams) Encode() []byte {
 }
 
 func (c *Client) AccountSendVerifyPhoneCode(params *AccountSendVerifyPhoneCodeParams) (*AuthSentCode, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSendVerifyPhoneCodeContext(context.Background(), params)
+}
+
+func (c *Client) AccountSendVerifyPhoneCodeContext(ctx context.Context, params *AccountSendVerifyPhoneCodeParams) (*AuthSentCode, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSendVerifyPhoneCode")
 	}
@@ -1850,7 +2071,11 @@ func (e *AccountVerifyPhoneParams) Encode() []byte {
 }
 
 func (c *Client) AccountVerifyPhone(params *AccountVerifyPhoneParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountVerifyPhoneContext(context.Background(), params)
+}
+
+func (c *Client) AccountVerifyPhoneContext(ctx context.Context, params *AccountVerifyPhoneParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountVerifyPhone")
 	}
@@ -1882,7 +2107,11 @@ func (e *AccountSendVerifyEmailCodeParams) Encode() []byte {
 }
 
 func (c *Client) AccountSendVerifyEmailCode(params *AccountSendVerifyEmailCodeParams) (*AccountSentEmailCode, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSendVerifyEmailCodeContext(context.Background(), params)
+}
+
+func (c *Client) AccountSendVerifyEmailCodeContext(ctx context.Context, params *AccountSendVerifyEmailCodeParams) (*AccountSentEmailCode, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSendVerifyEmailCode")
 	}
@@ -1916,7 +2145,11 @@ func (e *AccountVerifyEmailParams) Encode() []byte {
 }
 
 func (c *Client) AccountVerifyEmail(params *AccountVerifyEmailParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountVerifyEmailContext(context.Background(), params)
+}
+
+func (c *Client) AccountVerifyEmailContext(ctx context.Context, params *AccountVerifyEmailParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountVerifyEmail")
 	}
@@ -1977,7 +2210,11 @@ func (e *AccountInitTakeoutSessionParams) Encode() []byte {
 }
 
 func (c *Client) AccountInitTakeoutSession(params *AccountInitTakeoutSessionParams) (*AccountTakeout, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountInitTakeoutSessionContext(context.Background(), params)
+}
+
+func (c *Client) AccountInitTakeoutSessionContext(ctx context.Context, params *AccountInitTakeoutSessionParams) (*AccountTakeout, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountInitTakeoutSession")
 	}
@@ -2014,7 +2251,11 @@ func (e *AccountFinishTakeoutSessionParams) Encode() []byte {
 }
 
 func (c *Client) AccountFinishTakeoutSession(params *AccountFinishTakeoutSessionParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountFinishTakeoutSessionContext(context.Background(), params)
+}
+
+func (c *Client) AccountFinishTakeoutSessionContext(ctx context.Context, params *AccountFinishTakeoutSessionParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountFinishTakeoutSession")
 	}
@@ -2046,7 +2287,11 @@ func (e *AccountConfirmPasswordEmailParams) Encode() []byte {
 }
 
 func (c *Client) AccountConfirmPasswordEmail(params *AccountConfirmPasswordEmailParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountConfirmPasswordEmailContext(context.Background(), params)
+}
+
+func (c *Client) AccountConfirmPasswordEmailContext(ctx context.Context, params *AccountConfirmPasswordEmailParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountConfirmPasswordEmail")
 	}
@@ -2072,7 +2317,11 @@ func (e *AccountResendPasswordEmailParams) Encode() []byte {
 }
 
 func (c *Client) AccountResendPasswordEmail() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&AccountResendPasswordEmailParams{})
+	return c.AccountResendPasswordEmailContext(context.Background())
+}
+
+func (c *Client) AccountResendPasswordEmailContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountResendPasswordEmailParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountResendPasswordEmail")
 	}
@@ -2098,7 +2347,11 @@ func (e *AccountCancelPasswordEmailParams) Encode() []byte {
 }
 
 func (c *Client) AccountCancelPasswordEmail() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&AccountCancelPasswordEmailParams{})
+	return c.AccountCancelPasswordEmailContext(context.Background())
+}
+
+func (c *Client) AccountCancelPasswordEmailContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountCancelPasswordEmailParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountCancelPasswordEmail")
 	}
@@ -2124,7 +2377,11 @@ func (e *AccountGetContactSignUpNotificationParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetContactSignUpNotification() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&AccountGetContactSignUpNotificationParams{})
+	return c.AccountGetContactSignUpNotificationContext(context.Background())
+}
+
+func (c *Client) AccountGetContactSignUpNotificationContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountGetContactSignUpNotificationParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetContactSignUpNotification")
 	}
@@ -2156,7 +2413,11 @@ func (e *AccountSetContactSignUpNotificationParams) Encode() []byte {
 }
 
 func (c *Client) AccountSetContactSignUpNotification(params *AccountSetContactSignUpNotificationParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSetContactSignUpNotificationContext(context.Background(), params)
+}
+
+func (c *Client) AccountSetContactSignUpNotificationContext(ctx context.Context, params *AccountSetContactSignUpNotificationParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSetContactSignUpNotification")
 	}
@@ -2200,7 +2461,11 @@ func (e *AccountGetNotifyExceptionsParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetNotifyExceptions(params *AccountGetNotifyExceptionsParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetNotifyExceptionsContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetNotifyExceptionsContext(ctx context.Context, params *AccountGetNotifyExceptionsParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetNotifyExceptions")
 	}
@@ -2232,7 +2497,11 @@ func (e *AccountGetWallPaperParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetWallPaper(params *AccountGetWallPaperParams) (WallPaper, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetWallPaperContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetWallPaperContext(ctx context.Context, params *AccountGetWallPaperParams) (WallPaper, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetWallPaper")
 	}
@@ -2268,7 +2537,11 @@ func (e *AccountUploadWallPaperParams) Encode() []byte {
 }
 
 func (c *Client) AccountUploadWallPaper(params *AccountUploadWallPaperParams) (WallPaper, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUploadWallPaperContext(context.Background(), params)
+}
+
+func (c *Client) AccountUploadWallPaperContext(ctx context.Context, params *AccountUploadWallPaperParams) (WallPaper, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUploadWallPaper")
 	}
@@ -2304,7 +2577,11 @@ func (e *AccountSaveWallPaperParams) Encode() []byte {
 }
 
 func (c *Client) AccountSaveWallPaper(params *AccountSaveWallPaperParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSaveWallPaperContext(context.Background(), params)
+}
+
+func (c *Client) AccountSaveWallPaperContext(ctx context.Context, params *AccountSaveWallPaperParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSaveWallPaper")
 	}
@@ -2338,7 +2615,11 @@ func (e *AccountInstallWallPaperParams) Encode() []byte {
 }
 
 func (c *Client) AccountInstallWallPaper(params *AccountInstallWallPaperParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountInstallWallPaperContext(context.Background(), params)
+}
+
+func (c *Client) AccountInstallWallPaperContext(ctx context.Context, params *AccountInstallWallPaperParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountInstallWallPaper")
 	}
@@ -2364,7 +2645,11 @@ func (e *AccountResetWallPapersParams) Encode() []byte {
 }
 
 func (c *Client) AccountResetWallPapers() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&AccountResetWallPapersParams{})
+	return c.AccountResetWallPapersContext(context.Background())
+}
+
+func (c *Client) AccountResetWallPapersContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountResetWallPapersParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountResetWallPapers")
 	}
@@ -2390,7 +2675,11 @@ func (e *AccountGetAutoDownloadSettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetAutoDownloadSettings() (*AccountAutoDownloadSettings, error) {
-	data, err := c.MakeRequest(&AccountGetAutoDownloadSettingsParams{})
+	return c.AccountGetAutoDownloadSettingsContext(context.Background())
+}
+
+func (c *Client) AccountGetAutoDownloadSettingsContext(ctx context.Context) (*AccountAutoDownloadSettings, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountGetAutoDownloadSettingsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetAutoDownloadSettings")
 	}
@@ -2433,7 +2722,11 @@ func (e *AccountSaveAutoDownloadSettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountSaveAutoDownloadSettings(params *AccountSaveAutoDownloadSettingsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSaveAutoDownloadSettingsContext(context.Background(), params)
+}
+
+func (c *Client) AccountSaveAutoDownloadSettingsContext(ctx context.Context, params *AccountSaveAutoDownloadSettingsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSaveAutoDownloadSettings")
 	}
@@ -2479,7 +2772,11 @@ func (e *AccountUploadThemeParams) Encode() []byte {
 }
 
 func (c *Client) AccountUploadTheme(params *AccountUploadThemeParams) (Document, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUploadThemeContext(context.Background(), params)
+}
+
+func (c *Client) AccountUploadThemeContext(ctx context.Context, params *AccountUploadThemeParams) (Document, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUploadTheme")
 	}
@@ -2530,7 +2827,11 @@ func (e *AccountCreateThemeParams) Encode() []byte {
 }
 
 func (c *Client) AccountCreateTheme(params *AccountCreateThemeParams) (*Theme, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountCreateThemeContext(context.Background(), params)
+}
+
+func (c *Client) AccountCreateThemeContext(ctx context.Context, params *AccountCreateThemeParams) (*Theme, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountCreateTheme")
 	}
@@ -2595,7 +2896,11 @@ func (e *AccountUpdateThemeParams) Encode() []byte {
 }
 
 func (c *Client) AccountUpdateTheme(params *AccountUpdateThemeParams) (*Theme, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountUpdateThemeContext(context.Background(), params)
+}
+
+func (c *Client) AccountUpdateThemeContext(ctx context.Context, params *AccountUpdateThemeParams) (*Theme, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountUpdateTheme")
 	}
@@ -2629,7 +2934,11 @@ func (e *AccountSaveThemeParams) Encode() []byte {
 }
 
 func (c *Client) AccountSaveTheme(params *AccountSaveThemeParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSaveThemeContext(context.Background(), params)
+}
+
+func (c *Client) AccountSaveThemeContext(ctx context.Context, params *AccountSaveThemeParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSaveTheme")
 	}
@@ -2677,7 +2986,11 @@ func (e *AccountInstallThemeParams) Encode() []byte {
 }
 
 func (c *Client) AccountInstallTheme(params *AccountInstallThemeParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountInstallThemeContext(context.Background(), params)
+}
+
+func (c *Client) AccountInstallThemeContext(ctx context.Context, params *AccountInstallThemeParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountInstallTheme")
 	}
@@ -2713,7 +3026,11 @@ func (e *AccountGetThemeParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetTheme(params *AccountGetThemeParams) (*Theme, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetThemeContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetThemeContext(ctx context.Context, params *AccountGetThemeParams) (*Theme, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetTheme")
 	}
@@ -2747,7 +3064,11 @@ func (e *AccountGetThemesParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetThemes(params *AccountGetThemesParams) (AccountThemes, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetThemesContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetThemesContext(ctx context.Context, params *AccountGetThemesParams) (AccountThemes, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetThemes")
 	}
@@ -2784,7 +3105,11 @@ func (e *AccountSetContentSettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountSetContentSettings(params *AccountSetContentSettingsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSetContentSettingsContext(context.Background(), params)
+}
+
+func (c *Client) AccountSetContentSettingsContext(ctx context.Context, params *AccountSetContentSettingsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSetContentSettings")
 	}
@@ -2810,7 +3135,11 @@ func (e *AccountGetContentSettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetContentSettings() (*AccountContentSettings, error) {
-	data, err := c.MakeRequest(&AccountGetContentSettingsParams{})
+	return c.AccountGetContentSettingsContext(context.Background())
+}
+
+func (c *Client) AccountGetContentSettingsContext(ctx context.Context) (*AccountContentSettings, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountGetContentSettingsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetContentSettings")
 	}
@@ -2842,7 +3171,11 @@ func (e *AccountGetMultiWallPapersParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetMultiWallPapers(params *AccountGetMultiWallPapersParams) (WallPaper, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountGetMultiWallPapersContext(context.Background(), params)
+}
+
+func (c *Client) AccountGetMultiWallPapersContext(ctx context.Context, params *AccountGetMultiWallPapersParams) (WallPaper, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetMultiWallPapers")
 	}
@@ -2868,7 +3201,11 @@ func (e *AccountGetGlobalPrivacySettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountGetGlobalPrivacySettings() (*GlobalPrivacySettings, error) {
-	data, err := c.MakeRequest(&AccountGetGlobalPrivacySettingsParams{})
+	return c.AccountGetGlobalPrivacySettingsContext(context.Background())
+}
+
+func (c *Client) AccountGetGlobalPrivacySettingsContext(ctx context.Context) (*GlobalPrivacySettings, error) {
+	data, err := c.MakeRequestContext(ctx, &AccountGetGlobalPrivacySettingsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountGetGlobalPrivacySettings")
 	}
@@ -2900,7 +3237,11 @@ func (e *AccountSetGlobalPrivacySettingsParams) Encode() []byte {
 }
 
 func (c *Client) AccountSetGlobalPrivacySettings(params *AccountSetGlobalPrivacySettingsParams) (*GlobalPrivacySettings, error) {
-	data, err := c.MakeRequest(params)
+	return c.AccountSetGlobalPrivacySettingsContext(context.Background(), params)
+}
+
+func (c *Client) AccountSetGlobalPrivacySettingsContext(ctx context.Context, params *AccountSetGlobalPrivacySettingsParams) (*GlobalPrivacySettings, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning AccountSetGlobalPrivacySettings")
 	}
@@ -2932,7 +3273,11 @@ func (e *UsersGetUsersParams) Encode() []byte {
 }
 
 func (c *Client) UsersGetUsers(params *UsersGetUsersParams) (User, error) {
-	data, err := c.MakeRequest(params)
+	return c.UsersGetUsersContext(context.Background(), params)
+}
+
+func (c *Client) UsersGetUsersContext(ctx context.Context, params *UsersGetUsersParams) (User, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UsersGetUsers")
 	}
@@ -2964,7 +3309,11 @@ func (e *UsersGetFullUserParams) Encode() []byte {
 }
 
 func (c *Client) UsersGetFullUser(params *UsersGetFullUserParams) (*UserFull, error) {
-	data, err := c.MakeRequest(params)
+	return c.UsersGetFullUserContext(context.Background(), params)
+}
+
+func (c *Client) UsersGetFullUserContext(ctx context.Context, params *UsersGetFullUserParams) (*UserFull, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UsersGetFullUser")
 	}
@@ -2998,7 +3347,11 @@ func (e *UsersSetSecureValueErrorsParams) Encode() []byte {
 }
 
 func (c *Client) UsersSetSecureValueErrors(params *UsersSetSecureValueErrorsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.UsersSetSecureValueErrorsContext(context.Background(), params)
+}
+
+func (c *Client) UsersSetSecureValueErrorsContext(ctx context.Context, params *UsersSetSecureValueErrorsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UsersSetSecureValueErrors")
 	}
@@ -3030,7 +3383,11 @@ func (e *ContactsGetContactIDsParams) Encode() []byte {
 }
 
 func (c *Client) ContactsGetContactIDs(params *ContactsGetContactIDsParams) (*serialize.Int, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsGetContactIDsContext(context.Background(), params)
+}
+
+func (c *Client) ContactsGetContactIDsContext(ctx context.Context, params *ContactsGetContactIDsParams) (*serialize.Int, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsGetContactIDs")
 	}
@@ -3056,7 +3413,11 @@ func (e *ContactsGetStatusesParams) Encode() []byte {
 }
 
 func (c *Client) ContactsGetStatuses() (*ContactStatus, error) {
-	data, err := c.MakeRequest(&ContactsGetStatusesParams{})
+	return c.ContactsGetStatusesContext(context.Background())
+}
+
+func (c *Client) ContactsGetStatusesContext(ctx context.Context) (*ContactStatus, error) {
+	data, err := c.MakeRequestContext(ctx, &ContactsGetStatusesParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsGetStatuses")
 	}
@@ -3088,7 +3449,11 @@ func (e *ContactsGetContactsParams) Encode() []byte {
 }
 
 func (c *Client) ContactsGetContacts(params *ContactsGetContactsParams) (ContactsContacts, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsGetContactsContext(context.Background(), params)
+}
+
+func (c *Client) ContactsGetContactsContext(ctx context.Context, params *ContactsGetContactsParams) (ContactsContacts, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsGetContacts")
 	}
@@ -3120,7 +3485,11 @@ func (e *ContactsImportContactsParams) Encode() []byte {
 }
 
 func (c *Client) ContactsImportContacts(params *ContactsImportContactsParams) (*ContactsImportedContacts, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsImportContactsContext(context.Background(), params)
+}
+
+func (c *Client) ContactsImportContactsContext(ctx context.Context, params *ContactsImportContactsParams) (*ContactsImportedContacts, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsImportContacts")
 	}
@@ -3152,7 +3521,11 @@ func (e *ContactsDeleteContactsParams) Encode() []byte {
 }
 
 func (c *Client) ContactsDeleteContacts(params *ContactsDeleteContactsParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsDeleteContactsContext(context.Background(), params)
+}
+
+func (c *Client) ContactsDeleteContactsContext(ctx context.Context, params *ContactsDeleteContactsParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsDeleteContacts")
 	}
@@ -3184,7 +3557,11 @@ func (e *ContactsDeleteByPhonesParams) Encode() []byte {
 }
 
 func (c *Client) ContactsDeleteByPhones(params *ContactsDeleteByPhonesParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsDeleteByPhonesContext(context.Background(), params)
+}
+
+func (c *Client) ContactsDeleteByPhonesContext(ctx context.Context, params *ContactsDeleteByPhonesParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsDeleteByPhones")
 	}
@@ -3216,7 +3593,11 @@ func (e *ContactsBlockParams) Encode() []byte {
 }
 
 func (c *Client) ContactsBlock(params *ContactsBlockParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsBlockContext(context.Background(), params)
+}
+
+func (c *Client) ContactsBlockContext(ctx context.Context, params *ContactsBlockParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsBlock")
 	}
@@ -3248,7 +3629,11 @@ func (e *ContactsUnblockParams) Encode() []byte {
 }
 
 func (c *Client) ContactsUnblock(params *ContactsUnblockParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsUnblockContext(context.Background(), params)
+}
+
+func (c *Client) ContactsUnblockContext(ctx context.Context, params *ContactsUnblockParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsUnblock")
 	}
@@ -3282,7 +3667,11 @@ func (e *ContactsGetBlockedParams) Encode() []byte {
 }
 
 func (c *Client) ContactsGetBlocked(params *ContactsGetBlockedParams) (ContactsBlocked, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsGetBlockedContext(context.Background(), params)
+}
+
+func (c *Client) ContactsGetBlockedContext(ctx context.Context, params *ContactsGetBlockedParams) (ContactsBlocked, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsGetBlocked")
 	}
@@ -3316,7 +3705,11 @@ func (e *ContactsSearchParams) Encode() []byte {
 }
 
 func (c *Client) ContactsSearch(params *ContactsSearchParams) (*ContactsFound, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsSearchContext(context.Background(), params)
+}
+
+func (c *Client) ContactsSearchContext(ctx context.Context, params *ContactsSearchParams) (*ContactsFound, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsSearch")
 	}
@@ -3348,7 +3741,11 @@ func (e *ContactsResolveUsernameParams) Encode() []byte {
 }
 
 func (c *Client) ContactsResolveUsername(params *ContactsResolveUsernameParams) (*ContactsResolvedPeer, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsResolveUsernameContext(context.Background(), params)
+}
+
+func (c *Client) ContactsResolveUsernameContext(ctx context.Context, params *ContactsResolveUsernameParams) (*ContactsResolvedPeer, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsResolveUsername")
 	}
@@ -3419,7 +3816,11 @@ func (e *ContactsGetTopPeersParams) Encode() []byte {
 }
 
 func (c *Client) ContactsGetTopPeers(params *ContactsGetTopPeersParams) (ContactsTopPeers, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsGetTopPeersContext(context.Background(), params)
+}
+
+func (c *Client) ContactsGetTopPeersContext(ctx context.Context, params *ContactsGetTopPeersParams) (ContactsTopPeers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsGetTopPeers")
 	}
@@ -3453,7 +3854,11 @@ func (e *ContactsResetTopPeerRatingParams) Encode() []byte {
 }
 
 func (c *Client) ContactsResetTopPeerRating(params *ContactsResetTopPeerRatingParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsResetTopPeerRatingContext(context.Background(), params)
+}
+
+func (c *Client) ContactsResetTopPeerRatingContext(ctx context.Context, params *ContactsResetTopPeerRatingParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsResetTopPeerRating")
 	}
@@ -3479,7 +3884,11 @@ func (e *ContactsResetSavedParams) Encode() []byte {
 }
 
 func (c *Client) ContactsResetSaved() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&ContactsResetSavedParams{})
+	return c.ContactsResetSavedContext(context.Background())
+}
+
+func (c *Client) ContactsResetSavedContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &ContactsResetSavedParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsResetSaved")
 	}
@@ -3505,7 +3914,11 @@ func (e *ContactsGetSavedParams) Encode() []byte {
 }
 
 func (c *Client) ContactsGetSaved() (*SavedContact, error) {
-	data, err := c.MakeRequest(&ContactsGetSavedParams{})
+	return c.ContactsGetSavedContext(context.Background())
+}
+
+func (c *Client) ContactsGetSavedContext(ctx context.Context) (*SavedContact, error) {
+	data, err := c.MakeRequestContext(ctx, &ContactsGetSavedParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsGetSaved")
 	}
@@ -3537,7 +3950,11 @@ func (e *ContactsToggleTopPeersParams) Encode() []byte {
 }
 
 func (c *Client) ContactsToggleTopPeers(params *ContactsToggleTopPeersParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsToggleTopPeersContext(context.Background(), params)
+}
+
+func (c *Client) ContactsToggleTopPeersContext(ctx context.Context, params *ContactsToggleTopPeersParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsToggleTopPeers")
 	}
@@ -3582,7 +3999,11 @@ func (e *ContactsAddContactParams) Encode() []byte {
 }
 
 func (c *Client) ContactsAddContact(params *ContactsAddContactParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsAddContactContext(context.Background(), params)
+}
+
+func (c *Client) ContactsAddContactContext(ctx context.Context, params *ContactsAddContactParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsAddContact")
 	}
@@ -3614,7 +4035,11 @@ func (e *ContactsAcceptContactParams) Encode() []byte {
 }
 
 func (c *Client) ContactsAcceptContact(params *ContactsAcceptContactParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsAcceptContactContext(context.Background(), params)
+}
+
+func (c *Client) ContactsAcceptContactContext(ctx context.Context, params *ContactsAcceptContactParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsAcceptContact")
 	}
@@ -3660,7 +4085,11 @@ func (e *ContactsGetLocatedParams) Encode() []byte {
 }
 
 func (c *Client) ContactsGetLocated(params *ContactsGetLocatedParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ContactsGetLocatedContext(context.Background(), params)
+}
+
+func (c *Client) ContactsGetLocatedContext(ctx context.Context, params *ContactsGetLocatedParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ContactsGetLocated")
 	}
@@ -3692,7 +4121,11 @@ func (e *MessagesGetMessagesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetMessages(params *MessagesGetMessagesParams) (MessagesMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetMessagesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetMessagesContext(ctx context.Context, params *MessagesGetMessagesParams) (MessagesMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetMessages")
 	}
@@ -3746,7 +4179,11 @@ func (e *MessagesGetDialogsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetDialogs(params *MessagesGetDialogsParams) (MessagesDialogs, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetDialogsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetDialogsContext(ctx context.Context, params *MessagesGetDialogsParams) (MessagesDialogs, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetDialogs")
 	}
@@ -3792,7 +4229,11 @@ func (e *MessagesGetHistoryParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetHistory(params *MessagesGetHistoryParams) (MessagesMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetHistoryContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetHistoryContext(ctx context.Context, params *MessagesGetHistoryParams) (MessagesMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetHistory")
 	}
@@ -3854,7 +4295,11 @@ func (e *MessagesSearchParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSearch(params *MessagesSearchParams) (MessagesMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSearchContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSearchContext(ctx context.Context, params *MessagesSearchParams) (MessagesMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSearch")
 	}
@@ -3888,7 +4333,11 @@ func (e *MessagesReadHistoryParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReadHistory(params *MessagesReadHistoryParams) (*MessagesAffectedMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReadHistoryContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReadHistoryContext(ctx context.Context, params *MessagesReadHistoryParams) (*MessagesAffectedMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReadHistory")
 	}
@@ -3933,7 +4382,11 @@ func (e *MessagesDeleteHistoryParams) Encode() []byte {
 }
 
 func (c *Client) MessagesDeleteHistory(params *MessagesDeleteHistoryParams) (*MessagesAffectedHistory, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesDeleteHistoryContext(context.Background(), params)
+}
+
+func (c *Client) MessagesDeleteHistoryContext(ctx context.Context, params *MessagesDeleteHistoryParams) (*MessagesAffectedHistory, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesDeleteHistory")
 	}
@@ -3972,7 +4425,11 @@ func (e *MessagesDeleteMessagesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesDeleteMessages(params *MessagesDeleteMessagesParams) (*MessagesAffectedMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesDeleteMessagesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesDeleteMessagesContext(ctx context.Context, params *MessagesDeleteMessagesParams) (*MessagesAffectedMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesDeleteMessages")
 	}
@@ -4004,7 +4461,11 @@ func (e *MessagesReceivedMessagesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReceivedMessages(params *MessagesReceivedMessagesParams) (*ReceivedNotifyMessage, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReceivedMessagesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReceivedMessagesContext(ctx context.Context, params *MessagesReceivedMessagesParams) (*ReceivedNotifyMessage, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReceivedMessages")
 	}
@@ -4038,7 +4499,11 @@ func (e *MessagesSetTypingParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSetTyping(params *MessagesSetTypingParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSetTypingContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSetTypingContext(ctx context.Context, params *MessagesSetTypingParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSetTyping")
 	}
@@ -4121,7 +4586,11 @@ func (e *MessagesSendMessageParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendMessage(params *MessagesSendMessageParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendMessageContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendMessageContext(ctx context.Context, params *MessagesSendMessageParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendMessage")
 	}
@@ -4202,7 +4671,11 @@ func (e *MessagesSendMediaParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendMedia(params *MessagesSendMediaParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendMediaContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendMediaContext(ctx context.Context, params *MessagesSendMediaParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendMedia")
 	}
@@ -4266,7 +4739,11 @@ func (e *MessagesForwardMessagesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesForwardMessages(params *MessagesForwardMessagesParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesForwardMessagesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesForwardMessagesContext(ctx context.Context, params *MessagesForwardMessagesParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesForwardMessages")
 	}
@@ -4298,7 +4775,11 @@ func (e *MessagesReportSpamParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReportSpam(params *MessagesReportSpamParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReportSpamContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReportSpamContext(ctx context.Context, params *MessagesReportSpamParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReportSpam")
 	}
@@ -4330,7 +4811,11 @@ func (e *MessagesGetPeerSettingsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetPeerSettings(params *MessagesGetPeerSettingsParams) (*PeerSettings, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetPeerSettingsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetPeerSettingsContext(ctx context.Context, params *MessagesGetPeerSettingsParams) (*PeerSettings, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetPeerSettings")
 	}
@@ -4366,7 +4851,11 @@ func (e *MessagesReportParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReport(params *MessagesReportParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReportContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReportContext(ctx context.Context, params *MessagesReportParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReport")
 	}
@@ -4398,7 +4887,11 @@ func (e *MessagesGetChatsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetChats(params *MessagesGetChatsParams) (MessagesChats, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetChatsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetChatsContext(ctx context.Context, params *MessagesGetChatsParams) (MessagesChats, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetChats")
 	}
@@ -4430,7 +4923,11 @@ func (e *MessagesGetFullChatParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetFullChat(params *MessagesGetFullChatParams) (*MessagesChatFull, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetFullChatContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetFullChatContext(ctx context.Context, params *MessagesGetFullChatParams) (*MessagesChatFull, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetFullChat")
 	}
@@ -4464,7 +4961,11 @@ func (e *MessagesEditChatTitleParams) Encode() []byte {
 }
 
 func (c *Client) MessagesEditChatTitle(params *MessagesEditChatTitleParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesEditChatTitleContext(context.Background(), params)
+}
+
+func (c *Client) MessagesEditChatTitleContext(ctx context.Context, params *MessagesEditChatTitleParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesEditChatTitle")
 	}
@@ -4498,7 +4999,11 @@ func (e *MessagesEditChatPhotoParams) Encode() []byte {
 }
 
 func (c *Client) MessagesEditChatPhoto(params *MessagesEditChatPhotoParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesEditChatPhotoContext(context.Background(), params)
+}
+
+func (c *Client) MessagesEditChatPhotoContext(ctx context.Context, params *MessagesEditChatPhotoParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesEditChatPhoto")
 	}
@@ -4534,7 +5039,11 @@ func (e *MessagesAddChatUserParams) Encode() []byte {
 }
 
 func (c *Client) MessagesAddChatUser(params *MessagesAddChatUserParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesAddChatUserContext(context.Background(), params)
+}
+
+func (c *Client) MessagesAddChatUserContext(ctx context.Context, params *MessagesAddChatUserParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesAddChatUser")
 	}
@@ -4568,7 +5077,11 @@ func (e *MessagesDeleteChatUserParams) Encode() []byte {
 }
 
 func (c *Client) MessagesDeleteChatUser(params *MessagesDeleteChatUserParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesDeleteChatUserContext(context.Background(), params)
+}
+
+func (c *Client) MessagesDeleteChatUserContext(ctx context.Context, params *MessagesDeleteChatUserParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesDeleteChatUser")
 	}
@@ -4602,7 +5115,11 @@ func (e *MessagesCreateChatParams) Encode() []byte {
 }
 
 func (c *Client) MessagesCreateChat(params *MessagesCreateChatParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesCreateChatContext(context.Background(), params)
+}
+
+func (c *Client) MessagesCreateChatContext(ctx context.Context, params *MessagesCreateChatParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesCreateChat")
 	}
@@ -4636,7 +5153,11 @@ func (e *MessagesGetDhConfigParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetDhConfig(params *MessagesGetDhConfigParams) (MessagesDhConfig, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetDhConfigContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetDhConfigContext(ctx context.Context, params *MessagesGetDhConfigParams) (MessagesDhConfig, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetDhConfig")
 	}
@@ -4672,7 +5193,11 @@ func (e *MessagesRequestEncryptionParams) Encode() []byte {
 }
 
 func (c *Client) MessagesRequestEncryption(params *MessagesRequestEncryptionParams) (EncryptedChat, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesRequestEncryptionContext(context.Background(), params)
+}
+
+func (c *Client) MessagesRequestEncryptionContext(ctx context.Context, params *MessagesRequestEncryptionParams) (EncryptedChat, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesRequestEncryption")
 	}
@@ -4708,7 +5233,11 @@ func (e *MessagesAcceptEncryptionParams) Encode() []byte {
 }
 
 func (c *Client) MessagesAcceptEncryption(params *MessagesAcceptEncryptionParams) (EncryptedChat, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesAcceptEncryptionContext(context.Background(), params)
+}
+
+func (c *Client) MessagesAcceptEncryptionContext(ctx context.Context, params *MessagesAcceptEncryptionParams) (EncryptedChat, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesAcceptEncryption")
 	}
@@ -4740,7 +5269,11 @@ func (e *MessagesDiscardEncryptionParams) Encode() []byte {
 }
 
 func (c *Client) MessagesDiscardEncryption(params *MessagesDiscardEncryptionParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesDiscardEncryptionContext(context.Background(), params)
+}
+
+func (c *Client) MessagesDiscardEncryptionContext(ctx context.Context, params *MessagesDiscardEncryptionParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesDiscardEncryption")
 	}
@@ -4774,7 +5307,11 @@ func (e *MessagesSetEncryptedTypingParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSetEncryptedTyping(params *MessagesSetEncryptedTypingParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSetEncryptedTypingContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSetEncryptedTypingContext(ctx context.Context, params *MessagesSetEncryptedTypingParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSetEncryptedTyping")
 	}
@@ -4808,7 +5345,11 @@ func (e *MessagesReadEncryptedHistoryParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReadEncryptedHistory(params *MessagesReadEncryptedHistoryParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReadEncryptedHistoryContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReadEncryptedHistoryContext(ctx context.Context, params *MessagesReadEncryptedHistoryParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReadEncryptedHistory")
 	}
@@ -4844,7 +5385,11 @@ func (e *MessagesSendEncryptedParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendEncrypted(params *MessagesSendEncryptedParams) (MessagesSentEncryptedMessage, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendEncryptedContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendEncryptedContext(ctx context.Context, params *MessagesSendEncryptedParams) (MessagesSentEncryptedMessage, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendEncrypted")
 	}
@@ -4882,7 +5427,11 @@ func (e *MessagesSendEncryptedFileParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendEncryptedFile(params *MessagesSendEncryptedFileParams) (MessagesSentEncryptedMessage, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendEncryptedFileContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendEncryptedFileContext(ctx context.Context, params *MessagesSendEncryptedFileParams) (MessagesSentEncryptedMessage, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendEncryptedFile")
 	}
@@ -4918,7 +5467,11 @@ func (e *MessagesSendEncryptedServiceParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendEncryptedService(params *MessagesSendEncryptedServiceParams) (MessagesSentEncryptedMessage, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendEncryptedServiceContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendEncryptedServiceContext(ctx context.Context, params *MessagesSendEncryptedServiceParams) (MessagesSentEncryptedMessage, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendEncryptedService")
 	}
@@ -4950,7 +5503,11 @@ func (e *MessagesReceivedQueueParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReceivedQueue(params *MessagesReceivedQueueParams) (*serialize.Long, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReceivedQueueContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReceivedQueueContext(ctx context.Context, params *MessagesReceivedQueueParams) (*serialize.Long, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReceivedQueue")
 	}
@@ -4982,7 +5539,11 @@ func (e *MessagesReportEncryptedSpamParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReportEncryptedSpam(params *MessagesReportEncryptedSpamParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReportEncryptedSpamContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReportEncryptedSpamContext(ctx context.Context, params *MessagesReportEncryptedSpamParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReportEncryptedSpam")
 	}
@@ -5014,7 +5575,11 @@ func (e *MessagesReadMessageContentsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReadMessageContents(params *MessagesReadMessageContentsParams) (*MessagesAffectedMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReadMessageContentsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReadMessageContentsContext(ctx context.Context, params *MessagesReadMessageContentsParams) (*MessagesAffectedMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReadMessageContents")
 	}
@@ -5048,7 +5613,11 @@ func (e *MessagesGetStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetStickers(params *MessagesGetStickersParams) (MessagesStickers, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetStickersContext(ctx context.Context, params *MessagesGetStickersParams) (MessagesStickers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetStickers")
 	}
@@ -5080,7 +5649,11 @@ func (e *MessagesGetAllStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetAllStickers(params *MessagesGetAllStickersParams) (MessagesAllStickers, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetAllStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetAllStickersContext(ctx context.Context, params *MessagesGetAllStickersParams) (MessagesAllStickers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetAllStickers")
 	}
@@ -5122,7 +5695,11 @@ func (e *MessagesGetWebPagePreviewParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetWebPagePreview(params *MessagesGetWebPagePreviewParams) (MessageMedia, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetWebPagePreviewContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetWebPagePreviewContext(ctx context.Context, params *MessagesGetWebPagePreviewParams) (MessageMedia, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetWebPagePreview")
 	}
@@ -5154,7 +5731,11 @@ func (e *MessagesExportChatInviteParams) Encode() []byte {
 }
 
 func (c *Client) MessagesExportChatInvite(params *MessagesExportChatInviteParams) (ExportedChatInvite, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesExportChatInviteContext(context.Background(), params)
+}
+
+func (c *Client) MessagesExportChatInviteContext(ctx context.Context, params *MessagesExportChatInviteParams) (ExportedChatInvite, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesExportChatInvite")
 	}
@@ -5186,7 +5767,11 @@ func (e *MessagesCheckChatInviteParams) Encode() []byte {
 }
 
 func (c *Client) MessagesCheckChatInvite(params *MessagesCheckChatInviteParams) (ChatInvite, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesCheckChatInviteContext(context.Background(), params)
+}
+
+func (c *Client) MessagesCheckChatInviteContext(ctx context.Context, params *MessagesCheckChatInviteParams) (ChatInvite, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesCheckChatInvite")
 	}
@@ -5218,7 +5803,11 @@ func (e *MessagesImportChatInviteParams) Encode() []byte {
 }
 
 func (c *Client) MessagesImportChatInvite(params *MessagesImportChatInviteParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesImportChatInviteContext(context.Background(), params)
+}
+
+func (c *Client) MessagesImportChatInviteContext(ctx context.Context, params *MessagesImportChatInviteParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesImportChatInvite")
 	}
@@ -5250,7 +5839,11 @@ func (e *MessagesGetStickerSetParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetStickerSet(params *MessagesGetStickerSetParams) (*MessagesStickerSet, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetStickerSetContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetStickerSetContext(ctx context.Context, params *MessagesGetStickerSetParams) (*MessagesStickerSet, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetStickerSet")
 	}
@@ -5284,7 +5877,11 @@ func (e *MessagesInstallStickerSetParams) Encode() []byte {
 }
 
 func (c *Client) MessagesInstallStickerSet(params *MessagesInstallStickerSetParams) (MessagesStickerSetInstallResult, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesInstallStickerSetContext(context.Background(), params)
+}
+
+func (c *Client) MessagesInstallStickerSetContext(ctx context.Context, params *MessagesInstallStickerSetParams) (MessagesStickerSetInstallResult, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesInstallStickerSet")
 	}
@@ -5316,7 +5913,11 @@ func (e *MessagesUninstallStickerSetParams) Encode() []byte {
 }
 
 func (c *Client) MessagesUninstallStickerSet(params *MessagesUninstallStickerSetParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesUninstallStickerSetContext(context.Background(), params)
+}
+
+func (c *Client) MessagesUninstallStickerSetContext(ctx context.Context, params *MessagesUninstallStickerSetParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesUninstallStickerSet")
 	}
@@ -5354,7 +5955,11 @@ func (e *MessagesStartBotParams) Encode() []byte {
 }
 
 func (c *Client) MessagesStartBot(params *MessagesStartBotParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesStartBotContext(context.Background(), params)
+}
+
+func (c *Client) MessagesStartBotContext(ctx context.Context, params *MessagesStartBotParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesStartBot")
 	}
@@ -5390,7 +5995,11 @@ func (e *MessagesGetMessagesViewsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetMessagesViews(params *MessagesGetMessagesViewsParams) (*serialize.Int, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetMessagesViewsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetMessagesViewsContext(ctx context.Context, params *MessagesGetMessagesViewsParams) (*serialize.Int, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetMessagesViews")
 	}
@@ -5426,7 +6035,11 @@ func (e *MessagesEditChatAdminParams) Encode() []byte {
 }
 
 func (c *Client) MessagesEditChatAdmin(params *MessagesEditChatAdminParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesEditChatAdminContext(context.Background(), params)
+}
+
+func (c *Client) MessagesEditChatAdminContext(ctx context.Context, params *MessagesEditChatAdminParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesEditChatAdmin")
 	}
@@ -5458,7 +6071,11 @@ func (e *MessagesMigrateChatParams) Encode() []byte {
 }
 
 func (c *Client) MessagesMigrateChat(params *MessagesMigrateChatParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesMigrateChatContext(context.Background(), params)
+}
+
+func (c *Client) MessagesMigrateChatContext(ctx context.Context, params *MessagesMigrateChatParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesMigrateChat")
 	}
@@ -5508,7 +6125,11 @@ func (e *MessagesSearchGlobalParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSearchGlobal(params *MessagesSearchGlobalParams) (MessagesMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSearchGlobalContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSearchGlobalContext(ctx context.Context, params *MessagesSearchGlobalParams) (MessagesMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSearchGlobal")
 	}
@@ -5547,7 +6168,11 @@ func (e *MessagesReorderStickerSetsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReorderStickerSets(params *MessagesReorderStickerSetsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReorderStickerSetsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReorderStickerSetsContext(ctx context.Context, params *MessagesReorderStickerSetsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReorderStickerSets")
 	}
@@ -5583,7 +6208,11 @@ func (e *MessagesGetDocumentByHashParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetDocumentByHash(params *MessagesGetDocumentByHashParams) (Document, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetDocumentByHashContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetDocumentByHashContext(ctx context.Context, params *MessagesGetDocumentByHashParams) (Document, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetDocumentByHash")
 	}
@@ -5615,7 +6244,11 @@ func (e *MessagesGetSavedGifsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetSavedGifs(params *MessagesGetSavedGifsParams) (MessagesSavedGifs, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetSavedGifsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetSavedGifsContext(ctx context.Context, params *MessagesGetSavedGifsParams) (MessagesSavedGifs, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetSavedGifs")
 	}
@@ -5649,7 +6282,11 @@ func (e *MessagesSaveGifParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSaveGif(params *MessagesSaveGifParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSaveGifContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSaveGifContext(ctx context.Context, params *MessagesSaveGifParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSaveGif")
 	}
@@ -5697,7 +6334,11 @@ func (e *MessagesGetInlineBotResultsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetInlineBotResults(params *MessagesGetInlineBotResultsParams) (*MessagesBotResults, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetInlineBotResultsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetInlineBotResultsContext(ctx context.Context, params *MessagesGetInlineBotResultsParams) (*MessagesBotResults, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetInlineBotResults")
 	}
@@ -5758,7 +6399,11 @@ func (e *MessagesSetInlineBotResultsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSetInlineBotResults(params *MessagesSetInlineBotResultsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSetInlineBotResultsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSetInlineBotResultsContext(ctx context.Context, params *MessagesSetInlineBotResultsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSetInlineBotResults")
 	}
@@ -5829,7 +6474,11 @@ func (e *MessagesSendInlineBotResultParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendInlineBotResult(params *MessagesSendInlineBotResultParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendInlineBotResultContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendInlineBotResultContext(ctx context.Context, params *MessagesSendInlineBotResultParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendInlineBotResult")
 	}
@@ -5863,7 +6512,11 @@ func (e *MessagesGetMessageEditDataParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetMessageEditData(params *MessagesGetMessageEditDataParams) (*MessagesMessageEditData, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetMessageEditDataContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetMessageEditDataContext(ctx context.Context, params *MessagesGetMessageEditDataParams) (*MessagesMessageEditData, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetMessageEditData")
 	}
@@ -5939,7 +6592,11 @@ func (e *MessagesEditMessageParams) Encode() []byte {
 }
 
 func (c *Client) MessagesEditMessage(params *MessagesEditMessageParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesEditMessageContext(context.Background(), params)
+}
+
+func (c *Client) MessagesEditMessageContext(ctx context.Context, params *MessagesEditMessageParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesEditMessage")
 	}
@@ -6006,7 +6663,11 @@ func (e *MessagesEditInlineBotMessageParams) Encode() []byte {
 }
 
 func (c *Client) MessagesEditInlineBotMessage(params *MessagesEditInlineBotMessageParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesEditInlineBotMessageContext(context.Background(), params)
+}
+
+func (c *Client) MessagesEditInlineBotMessageContext(ctx context.Context, params *MessagesEditInlineBotMessageParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesEditInlineBotMessage")
 	}
@@ -6054,7 +6715,11 @@ func (e *MessagesGetBotCallbackAnswerParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetBotCallbackAnswer(params *MessagesGetBotCallbackAnswerParams) (*MessagesBotCallbackAnswer, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetBotCallbackAnswerContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetBotCallbackAnswerContext(ctx context.Context, params *MessagesGetBotCallbackAnswerParams) (*MessagesBotCallbackAnswer, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetBotCallbackAnswer")
 	}
@@ -6109,7 +6774,11 @@ func (e *MessagesSetBotCallbackAnswerParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSetBotCallbackAnswer(params *MessagesSetBotCallbackAnswerParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSetBotCallbackAnswerContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSetBotCallbackAnswerContext(ctx context.Context, params *MessagesSetBotCallbackAnswerParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSetBotCallbackAnswer")
 	}
@@ -6141,7 +6810,11 @@ func (e *MessagesGetPeerDialogsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetPeerDialogs(params *MessagesGetPeerDialogsParams) (*MessagesPeerDialogs, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetPeerDialogsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetPeerDialogsContext(ctx context.Context, params *MessagesGetPeerDialogsParams) (*MessagesPeerDialogs, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetPeerDialogs")
 	}
@@ -6196,7 +6869,11 @@ func (e *MessagesSaveDraftParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSaveDraft(params *MessagesSaveDraftParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSaveDraftContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSaveDraftContext(ctx context.Context, params *MessagesSaveDraftParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSaveDraft")
 	}
@@ -6222,7 +6899,11 @@ func (e *MessagesGetAllDraftsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetAllDrafts() (Updates, error) {
-	data, err := c.MakeRequest(&MessagesGetAllDraftsParams{})
+	return c.MessagesGetAllDraftsContext(context.Background())
+}
+
+func (c *Client) MessagesGetAllDraftsContext(ctx context.Context) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, &MessagesGetAllDraftsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetAllDrafts")
 	}
@@ -6254,7 +6935,11 @@ func (e *MessagesGetFeaturedStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetFeaturedStickers(params *MessagesGetFeaturedStickersParams) (MessagesFeaturedStickers, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetFeaturedStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetFeaturedStickersContext(ctx context.Context, params *MessagesGetFeaturedStickersParams) (MessagesFeaturedStickers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetFeaturedStickers")
 	}
@@ -6286,7 +6971,11 @@ func (e *MessagesReadFeaturedStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReadFeaturedStickers(params *MessagesReadFeaturedStickersParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReadFeaturedStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReadFeaturedStickersContext(ctx context.Context, params *MessagesReadFeaturedStickersParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReadFeaturedStickers")
 	}
@@ -6325,7 +7014,11 @@ func (e *MessagesGetRecentStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetRecentStickers(params *MessagesGetRecentStickersParams) (MessagesRecentStickers, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetRecentStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetRecentStickersContext(ctx context.Context, params *MessagesGetRecentStickersParams) (MessagesRecentStickers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetRecentStickers")
 	}
@@ -6366,7 +7059,11 @@ func (e *MessagesSaveRecentStickerParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSaveRecentSticker(params *MessagesSaveRecentStickerParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSaveRecentStickerContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSaveRecentStickerContext(ctx context.Context, params *MessagesSaveRecentStickerParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSaveRecentSticker")
 	}
@@ -6403,7 +7100,11 @@ func (e *MessagesClearRecentStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesClearRecentStickers(params *MessagesClearRecentStickersParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesClearRecentStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesClearRecentStickersContext(ctx context.Context, params *MessagesClearRecentStickersParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesClearRecentStickers")
 	}
@@ -6444,7 +7145,11 @@ func (e *MessagesGetArchivedStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetArchivedStickers(params *MessagesGetArchivedStickersParams) (*MessagesArchivedStickers, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetArchivedStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetArchivedStickersContext(ctx context.Context, params *MessagesGetArchivedStickersParams) (*MessagesArchivedStickers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetArchivedStickers")
 	}
@@ -6476,7 +7181,11 @@ func (e *MessagesGetMaskStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetMaskStickers(params *MessagesGetMaskStickersParams) (MessagesAllStickers, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetMaskStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetMaskStickersContext(ctx context.Context, params *MessagesGetMaskStickersParams) (MessagesAllStickers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetMaskStickers")
 	}
@@ -6508,7 +7217,11 @@ func (e *MessagesGetAttachedStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetAttachedStickers(params *MessagesGetAttachedStickersParams) (StickerSetCovered, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetAttachedStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetAttachedStickersContext(ctx context.Context, params *MessagesGetAttachedStickersParams) (StickerSetCovered, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetAttachedStickers")
 	}
@@ -6557,7 +7270,11 @@ func (e *MessagesSetGameScoreParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSetGameScore(params *MessagesSetGameScoreParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSetGameScoreContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSetGameScoreContext(ctx context.Context, params *MessagesSetGameScoreParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSetGameScore")
 	}
@@ -6604,7 +7321,11 @@ func (e *MessagesSetInlineGameScoreParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSetInlineGameScore(params *MessagesSetInlineGameScoreParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSetInlineGameScoreContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSetInlineGameScoreContext(ctx context.Context, params *MessagesSetInlineGameScoreParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSetInlineGameScore")
 	}
@@ -6640,7 +7361,11 @@ func (e *MessagesGetGameHighScoresParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetGameHighScores(params *MessagesGetGameHighScoresParams) (*MessagesHighScores, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetGameHighScoresContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetGameHighScoresContext(ctx context.Context, params *MessagesGetGameHighScoresParams) (*MessagesHighScores, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetGameHighScores")
 	}
@@ -6674,7 +7399,11 @@ func (e *MessagesGetInlineGameHighScoresParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetInlineGameHighScores(params *MessagesGetInlineGameHighScoresParams) (*MessagesHighScores, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetInlineGameHighScoresContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetInlineGameHighScoresContext(ctx context.Context, params *MessagesGetInlineGameHighScoresParams) (*MessagesHighScores, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetInlineGameHighScores")
 	}
@@ -6710,7 +7439,11 @@ func (e *MessagesGetCommonChatsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetCommonChats(params *MessagesGetCommonChatsParams) (MessagesChats, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetCommonChatsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetCommonChatsContext(ctx context.Context, params *MessagesGetCommonChatsParams) (MessagesChats, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetCommonChats")
 	}
@@ -6742,7 +7475,11 @@ func (e *MessagesGetAllChatsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetAllChats(params *MessagesGetAllChatsParams) (MessagesChats, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetAllChatsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetAllChatsContext(ctx context.Context, params *MessagesGetAllChatsParams) (MessagesChats, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetAllChats")
 	}
@@ -6776,7 +7513,11 @@ func (e *MessagesGetWebPageParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetWebPage(params *MessagesGetWebPageParams) (WebPage, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetWebPageContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetWebPageContext(ctx context.Context, params *MessagesGetWebPageParams) (WebPage, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetWebPage")
 	}
@@ -6815,7 +7556,11 @@ func (e *MessagesToggleDialogPinParams) Encode() []byte {
 }
 
 func (c *Client) MessagesToggleDialogPin(params *MessagesToggleDialogPinParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesToggleDialogPinContext(context.Background(), params)
+}
+
+func (c *Client) MessagesToggleDialogPinContext(ctx context.Context, params *MessagesToggleDialogPinParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesToggleDialogPin")
 	}
@@ -6856,7 +7601,11 @@ func (e *MessagesReorderPinnedDialogsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReorderPinnedDialogs(params *MessagesReorderPinnedDialogsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReorderPinnedDialogsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReorderPinnedDialogsContext(ctx context.Context, params *MessagesReorderPinnedDialogsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReorderPinnedDialogs")
 	}
@@ -6888,7 +7637,11 @@ func (e *MessagesGetPinnedDialogsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetPinnedDialogs(params *MessagesGetPinnedDialogsParams) (*MessagesPeerDialogs, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetPinnedDialogsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetPinnedDialogsContext(ctx context.Context, params *MessagesGetPinnedDialogsParams) (*MessagesPeerDialogs, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetPinnedDialogs")
 	}
@@ -6937,7 +7690,11 @@ func (e *MessagesSetBotShippingResultsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSetBotShippingResults(params *MessagesSetBotShippingResultsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSetBotShippingResultsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSetBotShippingResultsContext(ctx context.Context, params *MessagesSetBotShippingResultsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSetBotShippingResults")
 	}
@@ -6983,7 +7740,11 @@ func (e *MessagesSetBotPrecheckoutResultsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSetBotPrecheckoutResults(params *MessagesSetBotPrecheckoutResultsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSetBotPrecheckoutResultsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSetBotPrecheckoutResultsContext(ctx context.Context, params *MessagesSetBotPrecheckoutResultsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSetBotPrecheckoutResults")
 	}
@@ -7017,7 +7778,11 @@ func (e *MessagesUploadMediaParams) Encode() []byte {
 }
 
 func (c *Client) MessagesUploadMedia(params *MessagesUploadMediaParams) (MessageMedia, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesUploadMediaContext(context.Background(), params)
+}
+
+func (c *Client) MessagesUploadMediaContext(ctx context.Context, params *MessagesUploadMediaParams) (MessageMedia, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesUploadMedia")
 	}
@@ -7053,7 +7818,11 @@ func (e *MessagesSendScreenshotNotificationParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendScreenshotNotification(params *MessagesSendScreenshotNotificationParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendScreenshotNotificationContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendScreenshotNotificationContext(ctx context.Context, params *MessagesSendScreenshotNotificationParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendScreenshotNotification")
 	}
@@ -7085,7 +7854,11 @@ func (e *MessagesGetFavedStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetFavedStickers(params *MessagesGetFavedStickersParams) (MessagesFavedStickers, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetFavedStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetFavedStickersContext(ctx context.Context, params *MessagesGetFavedStickersParams) (MessagesFavedStickers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetFavedStickers")
 	}
@@ -7119,7 +7892,11 @@ func (e *MessagesFaveStickerParams) Encode() []byte {
 }
 
 func (c *Client) MessagesFaveSticker(params *MessagesFaveStickerParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesFaveStickerContext(context.Background(), params)
+}
+
+func (c *Client) MessagesFaveStickerContext(ctx context.Context, params *MessagesFaveStickerParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesFaveSticker")
 	}
@@ -7161,7 +7938,11 @@ func (e *MessagesGetUnreadMentionsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetUnreadMentions(params *MessagesGetUnreadMentionsParams) (MessagesMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetUnreadMentionsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetUnreadMentionsContext(ctx context.Context, params *MessagesGetUnreadMentionsParams) (MessagesMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetUnreadMentions")
 	}
@@ -7193,7 +7974,11 @@ func (e *MessagesReadMentionsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesReadMentions(params *MessagesReadMentionsParams) (*MessagesAffectedHistory, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesReadMentionsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesReadMentionsContext(ctx context.Context, params *MessagesReadMentionsParams) (*MessagesAffectedHistory, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesReadMentions")
 	}
@@ -7229,7 +8014,11 @@ func (e *MessagesGetRecentLocationsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetRecentLocations(params *MessagesGetRecentLocationsParams) (MessagesMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetRecentLocationsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetRecentLocationsContext(ctx context.Context, params *MessagesGetRecentLocationsParams) (MessagesMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetRecentLocations")
 	}
@@ -7292,7 +8081,11 @@ func (e *MessagesSendMultiMediaParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendMultiMedia(params *MessagesSendMultiMediaParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendMultiMediaContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendMultiMediaContext(ctx context.Context, params *MessagesSendMultiMediaParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendMultiMedia")
 	}
@@ -7326,7 +8119,11 @@ func (e *MessagesUploadEncryptedFileParams) Encode() []byte {
 }
 
 func (c *Client) MessagesUploadEncryptedFile(params *MessagesUploadEncryptedFileParams) (EncryptedFile, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesUploadEncryptedFileContext(context.Background(), params)
+}
+
+func (c *Client) MessagesUploadEncryptedFileContext(ctx context.Context, params *MessagesUploadEncryptedFileParams) (EncryptedFile, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesUploadEncryptedFile")
 	}
@@ -7367,7 +8164,11 @@ func (e *MessagesSearchStickerSetsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSearchStickerSets(params *MessagesSearchStickerSetsParams) (MessagesFoundStickerSets, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSearchStickerSetsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSearchStickerSetsContext(ctx context.Context, params *MessagesSearchStickerSetsParams) (MessagesFoundStickerSets, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSearchStickerSets")
 	}
@@ -7393,7 +8194,11 @@ func (e *MessagesGetSplitRangesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetSplitRanges() (*MessageRange, error) {
-	data, err := c.MakeRequest(&MessagesGetSplitRangesParams{})
+	return c.MessagesGetSplitRangesContext(context.Background())
+}
+
+func (c *Client) MessagesGetSplitRangesContext(ctx context.Context) (*MessageRange, error) {
+	data, err := c.MakeRequestContext(ctx, &MessagesGetSplitRangesParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetSplitRanges")
 	}
@@ -7432,7 +8237,11 @@ func (e *MessagesMarkDialogUnreadParams) Encode() []byte {
 }
 
 func (c *Client) MessagesMarkDialogUnread(params *MessagesMarkDialogUnreadParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesMarkDialogUnreadContext(context.Background(), params)
+}
+
+func (c *Client) MessagesMarkDialogUnreadContext(ctx context.Context, params *MessagesMarkDialogUnreadParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesMarkDialogUnread")
 	}
@@ -7458,7 +8267,11 @@ func (e *MessagesGetDialogUnreadMarksParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetDialogUnreadMarks() (DialogPeer, error) {
-	data, err := c.MakeRequest(&MessagesGetDialogUnreadMarksParams{})
+	return c.MessagesGetDialogUnreadMarksContext(context.Background())
+}
+
+func (c *Client) MessagesGetDialogUnreadMarksContext(ctx context.Context) (DialogPeer, error) {
+	data, err := c.MakeRequestContext(ctx, &MessagesGetDialogUnreadMarksParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetDialogUnreadMarks")
 	}
@@ -7484,7 +8297,11 @@ func (e *MessagesClearAllDraftsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesClearAllDrafts() (*serialize.Bool, error) {
-	data, err := c.MakeRequest(&MessagesClearAllDraftsParams{})
+	return c.MessagesClearAllDraftsContext(context.Background())
+}
+
+func (c *Client) MessagesClearAllDraftsContext(ctx context.Context) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, &MessagesClearAllDraftsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesClearAllDrafts")
 	}
@@ -7525,7 +8342,11 @@ func (e *MessagesUpdatePinnedMessageParams) Encode() []byte {
 }
 
 func (c *Client) MessagesUpdatePinnedMessage(params *MessagesUpdatePinnedMessageParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesUpdatePinnedMessageContext(context.Background(), params)
+}
+
+func (c *Client) MessagesUpdatePinnedMessageContext(ctx context.Context, params *MessagesUpdatePinnedMessageParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesUpdatePinnedMessage")
 	}
@@ -7561,7 +8382,11 @@ func (e *MessagesSendVoteParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendVote(params *MessagesSendVoteParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendVoteContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendVoteContext(ctx context.Context, params *MessagesSendVoteParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendVote")
 	}
@@ -7595,7 +8420,11 @@ func (e *MessagesGetPollResultsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetPollResults(params *MessagesGetPollResultsParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetPollResultsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetPollResultsContext(ctx context.Context, params *MessagesGetPollResultsParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetPollResults")
 	}
@@ -7627,7 +8456,11 @@ func (e *MessagesGetOnlinesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetOnlines(params *MessagesGetOnlinesParams) (*ChatOnlines, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetOnlinesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetOnlinesContext(ctx context.Context, params *MessagesGetOnlinesParams) (*ChatOnlines, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetOnlines")
 	}
@@ -7668,7 +8501,11 @@ func (e *MessagesGetStatsURLParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetStatsURL(params *MessagesGetStatsURLParams) (*StatsURL, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetStatsURLContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetStatsURLContext(ctx context.Context, params *MessagesGetStatsURLParams) (*StatsURL, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetStatsURL")
 	}
@@ -7702,7 +8539,11 @@ func (e *MessagesEditChatAboutParams) Encode() []byte {
 }
 
 func (c *Client) MessagesEditChatAbout(params *MessagesEditChatAboutParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesEditChatAboutContext(context.Background(), params)
+}
+
+func (c *Client) MessagesEditChatAboutContext(ctx context.Context, params *MessagesEditChatAboutParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesEditChatAbout")
 	}
@@ -7736,7 +8577,11 @@ func (e *MessagesEditChatDefaultBannedRightsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesEditChatDefaultBannedRights(params *MessagesEditChatDefaultBannedRightsParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesEditChatDefaultBannedRightsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesEditChatDefaultBannedRightsContext(ctx context.Context, params *MessagesEditChatDefaultBannedRightsParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesEditChatDefaultBannedRights")
 	}
@@ -7768,7 +8613,11 @@ func (e *MessagesGetEmojiKeywordsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetEmojiKeywords(params *MessagesGetEmojiKeywordsParams) (*EmojiKeywordsDifference, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetEmojiKeywordsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetEmojiKeywordsContext(ctx context.Context, params *MessagesGetEmojiKeywordsParams) (*EmojiKeywordsDifference, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetEmojiKeywords")
 	}
@@ -7802,7 +8651,11 @@ func (e *MessagesGetEmojiKeywordsDifferenceParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetEmojiKeywordsDifference(params *MessagesGetEmojiKeywordsDifferenceParams) (*EmojiKeywordsDifference, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetEmojiKeywordsDifferenceContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetEmojiKeywordsDifferenceContext(ctx context.Context, params *MessagesGetEmojiKeywordsDifferenceParams) (*EmojiKeywordsDifference, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetEmojiKeywordsDifference")
 	}
@@ -7834,7 +8687,11 @@ func (e *MessagesGetEmojiKeywordsLanguagesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetEmojiKeywordsLanguages(params *MessagesGetEmojiKeywordsLanguagesParams) (*EmojiLanguage, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetEmojiKeywordsLanguagesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetEmojiKeywordsLanguagesContext(ctx context.Context, params *MessagesGetEmojiKeywordsLanguagesParams) (*EmojiLanguage, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetEmojiKeywordsLanguages")
 	}
@@ -7866,7 +8723,11 @@ func (e *MessagesGetEmojiURLParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetEmojiURL(params *MessagesGetEmojiURLParams) (*EmojiURL, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetEmojiURLContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetEmojiURLContext(ctx context.Context, params *MessagesGetEmojiURLParams) (*EmojiURL, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetEmojiURL")
 	}
@@ -7900,7 +8761,11 @@ func (e *MessagesGetSearchCountersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetSearchCounters(params *MessagesGetSearchCountersParams) (*MessagesSearchCounter, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetSearchCountersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetSearchCountersContext(ctx context.Context, params *MessagesGetSearchCountersParams) (*MessagesSearchCounter, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetSearchCounters")
 	}
@@ -7936,7 +8801,11 @@ func (e *MessagesRequestUrlAuthParams) Encode() []byte {
 }
 
 func (c *Client) MessagesRequestUrlAuth(params *MessagesRequestUrlAuthParams) (UrlAuthResult, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesRequestUrlAuthContext(context.Background(), params)
+}
+
+func (c *Client) MessagesRequestUrlAuthContext(ctx context.Context, params *MessagesRequestUrlAuthParams) (UrlAuthResult, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesRequestUrlAuth")
 	}
@@ -7979,7 +8848,11 @@ func (e *MessagesAcceptUrlAuthParams) Encode() []byte {
 }
 
 func (c *Client) MessagesAcceptUrlAuth(params *MessagesAcceptUrlAuthParams) (UrlAuthResult, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesAcceptUrlAuthContext(context.Background(), params)
+}
+
+func (c *Client) MessagesAcceptUrlAuthContext(ctx context.Context, params *MessagesAcceptUrlAuthParams) (UrlAuthResult, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesAcceptUrlAuth")
 	}
@@ -8011,7 +8884,11 @@ func (e *MessagesHidePeerSettingsBarParams) Encode() []byte {
 }
 
 func (c *Client) MessagesHidePeerSettingsBar(params *MessagesHidePeerSettingsBarParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesHidePeerSettingsBarContext(context.Background(), params)
+}
+
+func (c *Client) MessagesHidePeerSettingsBarContext(ctx context.Context, params *MessagesHidePeerSettingsBarParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesHidePeerSettingsBar")
 	}
@@ -8045,7 +8922,11 @@ func (e *MessagesGetScheduledHistoryParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetScheduledHistory(params *MessagesGetScheduledHistoryParams) (MessagesMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetScheduledHistoryContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetScheduledHistoryContext(ctx context.Context, params *MessagesGetScheduledHistoryParams) (MessagesMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetScheduledHistory")
 	}
@@ -8079,7 +8960,11 @@ func (e *MessagesGetScheduledMessagesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetScheduledMessages(params *MessagesGetScheduledMessagesParams) (MessagesMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetScheduledMessagesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetScheduledMessagesContext(ctx context.Context, params *MessagesGetScheduledMessagesParams) (MessagesMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetScheduledMessages")
 	}
@@ -8113,7 +8998,11 @@ func (e *MessagesSendScheduledMessagesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesSendScheduledMessages(params *MessagesSendScheduledMessagesParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesSendScheduledMessagesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesSendScheduledMessagesContext(ctx context.Context, params *MessagesSendScheduledMessagesParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesSendScheduledMessages")
 	}
@@ -8147,7 +9036,11 @@ func (e *MessagesDeleteScheduledMessagesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesDeleteScheduledMessages(params *MessagesDeleteScheduledMessagesParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesDeleteScheduledMessagesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesDeleteScheduledMessagesContext(ctx context.Context, params *MessagesDeleteScheduledMessagesParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesDeleteScheduledMessages")
 	}
@@ -8200,7 +9093,11 @@ func (e *MessagesGetPollVotesParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetPollVotes(params *MessagesGetPollVotesParams) (*MessagesVotesList, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetPollVotesContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetPollVotesContext(ctx context.Context, params *MessagesGetPollVotesParams) (*MessagesVotesList, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetPollVotes")
 	}
@@ -8247,7 +9144,11 @@ func (e *MessagesToggleStickerSetsParams) Encode() []byte {
 }
 
 func (c *Client) MessagesToggleStickerSets(params *MessagesToggleStickerSetsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesToggleStickerSetsContext(context.Background(), params)
+}
+
+func (c *Client) MessagesToggleStickerSetsContext(ctx context.Context, params *MessagesToggleStickerSetsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesToggleStickerSets")
 	}
@@ -8273,7 +9174,11 @@ func (e *MessagesGetDialogFiltersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetDialogFilters() (*DialogFilter, error) {
-	data, err := c.MakeRequest(&MessagesGetDialogFiltersParams{})
+	return c.MessagesGetDialogFiltersContext(context.Background())
+}
+
+func (c *Client) MessagesGetDialogFiltersContext(ctx context.Context) (*DialogFilter, error) {
+	data, err := c.MakeRequestContext(ctx, &MessagesGetDialogFiltersParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetDialogFilters")
 	}
@@ -8299,7 +9204,11 @@ func (e *MessagesGetSuggestedDialogFiltersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetSuggestedDialogFilters() (*DialogFilterSuggested, error) {
-	data, err := c.MakeRequest(&MessagesGetSuggestedDialogFiltersParams{})
+	return c.MessagesGetSuggestedDialogFiltersContext(context.Background())
+}
+
+func (c *Client) MessagesGetSuggestedDialogFiltersContext(ctx context.Context) (*DialogFilterSuggested, error) {
+	data, err := c.MakeRequestContext(ctx, &MessagesGetSuggestedDialogFiltersParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetSuggestedDialogFilters")
 	}
@@ -8341,7 +9250,11 @@ func (e *MessagesUpdateDialogFilterParams) Encode() []byte {
 }
 
 func (c *Client) MessagesUpdateDialogFilter(params *MessagesUpdateDialogFilterParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesUpdateDialogFilterContext(context.Background(), params)
+}
+
+func (c *Client) MessagesUpdateDialogFilterContext(ctx context.Context, params *MessagesUpdateDialogFilterParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesUpdateDialogFilter")
 	}
@@ -8373,7 +9286,11 @@ func (e *MessagesUpdateDialogFiltersOrderParams) Encode() []byte {
 }
 
 func (c *Client) MessagesUpdateDialogFiltersOrder(params *MessagesUpdateDialogFiltersOrderParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesUpdateDialogFiltersOrderContext(context.Background(), params)
+}
+
+func (c *Client) MessagesUpdateDialogFiltersOrderContext(ctx context.Context, params *MessagesUpdateDialogFiltersOrderParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesUpdateDialogFiltersOrder")
 	}
@@ -8409,7 +9326,11 @@ func (e *MessagesGetOldFeaturedStickersParams) Encode() []byte {
 }
 
 func (c *Client) MessagesGetOldFeaturedStickers(params *MessagesGetOldFeaturedStickersParams) (MessagesFeaturedStickers, error) {
-	data, err := c.MakeRequest(params)
+	return c.MessagesGetOldFeaturedStickersContext(context.Background(), params)
+}
+
+func (c *Client) MessagesGetOldFeaturedStickersContext(ctx context.Context, params *MessagesGetOldFeaturedStickersParams) (MessagesFeaturedStickers, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning MessagesGetOldFeaturedStickers")
 	}
@@ -8435,7 +9356,11 @@ func (e *UpdatesGetStateParams) Encode() []byte {
 }
 
 func (c *Client) UpdatesGetState() (*UpdatesState, error) {
-	data, err := c.MakeRequest(&UpdatesGetStateParams{})
+	return c.UpdatesGetStateContext(context.Background())
+}
+
+func (c *Client) UpdatesGetStateContext(ctx context.Context) (*UpdatesState, error) {
+	data, err := c.MakeRequestContext(ctx, &UpdatesGetStateParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UpdatesGetState")
 	}
@@ -8481,7 +9406,11 @@ func (e *UpdatesGetDifferenceParams) Encode() []byte {
 }
 
 func (c *Client) UpdatesGetDifference(params *UpdatesGetDifferenceParams) (UpdatesDifference, error) {
-	data, err := c.MakeRequest(params)
+	return c.UpdatesGetDifferenceContext(context.Background(), params)
+}
+
+func (c *Client) UpdatesGetDifferenceContext(ctx context.Context, params *UpdatesGetDifferenceParams) (UpdatesDifference, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UpdatesGetDifference")
 	}
@@ -8526,7 +9455,11 @@ func (e *UpdatesGetChannelDifferenceParams) Encode() []byte {
 }
 
 func (c *Client) UpdatesGetChannelDifference(params *UpdatesGetChannelDifferenceParams) (UpdatesChannelDifference, error) {
-	data, err := c.MakeRequest(params)
+	return c.UpdatesGetChannelDifferenceContext(context.Background(), params)
+}
+
+func (c *Client) UpdatesGetChannelDifferenceContext(ctx context.Context, params *UpdatesGetChannelDifferenceParams) (UpdatesChannelDifference, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UpdatesGetChannelDifference")
 	}
@@ -8558,7 +9491,11 @@ func (e *PhotosUpdateProfilePhotoParams) Encode() []byte {
 }
 
 func (c *Client) PhotosUpdateProfilePhoto(params *PhotosUpdateProfilePhotoParams) (*PhotosPhoto, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhotosUpdateProfilePhotoContext(context.Background(), params)
+}
+
+func (c *Client) PhotosUpdateProfilePhotoContext(ctx context.Context, params *PhotosUpdateProfilePhotoParams) (*PhotosPhoto, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhotosUpdateProfilePhoto")
 	}
@@ -8612,7 +9549,11 @@ func (e *PhotosUploadProfilePhotoParams) Encode() []byte {
 }
 
 func (c *Client) PhotosUploadProfilePhoto(params *PhotosUploadProfilePhotoParams) (*PhotosPhoto, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhotosUploadProfilePhotoContext(context.Background(), params)
+}
+
+func (c *Client) PhotosUploadProfilePhotoContext(ctx context.Context, params *PhotosUploadProfilePhotoParams) (*PhotosPhoto, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhotosUploadProfilePhoto")
 	}
@@ -8644,7 +9585,11 @@ func (e *PhotosDeletePhotosParams) Encode() []byte {
 }
 
 func (c *Client) PhotosDeletePhotos(params *PhotosDeletePhotosParams) (*serialize.Long, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhotosDeletePhotosContext(context.Background(), params)
+}
+
+func (c *Client) PhotosDeletePhotosContext(ctx context.Context, params *PhotosDeletePhotosParams) (*serialize.Long, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhotosDeletePhotos")
 	}
@@ -8682,7 +9627,11 @@ func (e *PhotosGetUserPhotosParams) Encode() []byte {
 }
 
 func (c *Client) PhotosGetUserPhotos(params *PhotosGetUserPhotosParams) (PhotosPhotos, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhotosGetUserPhotosContext(context.Background(), params)
+}
+
+func (c *Client) PhotosGetUserPhotosContext(ctx context.Context, params *PhotosGetUserPhotosParams) (PhotosPhotos, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhotosGetUserPhotos")
 	}
@@ -8718,7 +9667,11 @@ func (e *UploadSaveFilePartParams) Encode() []byte {
 }
 
 func (c *Client) UploadSaveFilePart(params *UploadSaveFilePartParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.UploadSaveFilePartContext(context.Background(), params)
+}
+
+func (c *Client) UploadSaveFilePartContext(ctx context.Context, params *UploadSaveFilePartParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UploadSaveFilePart")
 	}
@@ -8765,7 +9718,11 @@ func (e *UploadGetFileParams) Encode() []byte {
 }
 
 func (c *Client) UploadGetFile(params *UploadGetFileParams) (UploadFile, error) {
-	data, err := c.MakeRequest(params)
+	return c.UploadGetFileContext(context.Background(), params)
+}
+
+func (c *Client) UploadGetFileContext(ctx context.Context, params *UploadGetFileParams) (UploadFile, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UploadGetFile")
 	}
@@ -8803,7 +9760,11 @@ func (e *UploadSaveBigFilePartParams) Encode() []byte {
 }
 
 func (c *Client) UploadSaveBigFilePart(params *UploadSaveBigFilePartParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.UploadSaveBigFilePartContext(context.Background(), params)
+}
+
+func (c *Client) UploadSaveBigFilePartContext(ctx context.Context, params *UploadSaveBigFilePartParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UploadSaveBigFilePart")
 	}
@@ -8839,7 +9800,11 @@ func (e *UploadGetWebFileParams) Encode() []byte {
 }
 
 func (c *Client) UploadGetWebFile(params *UploadGetWebFileParams) (*UploadWebFile, error) {
-	data, err := c.MakeRequest(params)
+	return c.UploadGetWebFileContext(context.Background(), params)
+}
+
+func (c *Client) UploadGetWebFileContext(ctx context.Context, params *UploadGetWebFileParams) (*UploadWebFile, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UploadGetWebFile")
 	}
@@ -8875,7 +9840,11 @@ func (e *UploadGetCdnFileParams) Encode() []byte {
 }
 
 func (c *Client) UploadGetCdnFile(params *UploadGetCdnFileParams) (UploadCdnFile, error) {
-	data, err := c.MakeRequest(params)
+	return c.UploadGetCdnFileContext(context.Background(), params)
+}
+
+func (c *Client) UploadGetCdnFileContext(ctx context.Context, params *UploadGetCdnFileParams) (UploadCdnFile, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UploadGetCdnFile")
 	}
@@ -8909,7 +9878,11 @@ func (e *UploadReuploadCdnFileParams) Encode() []byte {
 }
 
 func (c *Client) UploadReuploadCdnFile(params *UploadReuploadCdnFileParams) (*FileHash, error) {
-	data, err := c.MakeRequest(params)
+	return c.UploadReuploadCdnFileContext(context.Background(), params)
+}
+
+func (c *Client) UploadReuploadCdnFileContext(ctx context.Context, params *UploadReuploadCdnFileParams) (*FileHash, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UploadReuploadCdnFile")
 	}
@@ -8943,7 +9916,11 @@ func (e *UploadGetCdnFileHashesParams) Encode() []byte {
 }
 
 func (c *Client) UploadGetCdnFileHashes(params *UploadGetCdnFileHashesParams) (*FileHash, error) {
-	data, err := c.MakeRequest(params)
+	return c.UploadGetCdnFileHashesContext(context.Background(), params)
+}
+
+func (c *Client) UploadGetCdnFileHashesContext(ctx context.Context, params *UploadGetCdnFileHashesParams) (*FileHash, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UploadGetCdnFileHashes")
 	}
@@ -8977,7 +9954,11 @@ func (e *UploadGetFileHashesParams) Encode() []byte {
 }
 
 func (c *Client) UploadGetFileHashes(params *UploadGetFileHashesParams) (*FileHash, error) {
-	data, err := c.MakeRequest(params)
+	return c.UploadGetFileHashesContext(context.Background(), params)
+}
+
+func (c *Client) UploadGetFileHashesContext(ctx context.Context, params *UploadGetFileHashesParams) (*FileHash, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning UploadGetFileHashes")
 	}
@@ -9003,7 +9984,11 @@ func (e *HelpGetConfigParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetConfig() (*Config, error) {
-	data, err := c.MakeRequest(&HelpGetConfigParams{})
+	return c.HelpGetConfigContext(context.Background())
+}
+
+func (c *Client) HelpGetConfigContext(ctx context.Context) (*Config, error) {
+	data, err := c.MakeRequestContext(ctx, &HelpGetConfigParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetConfig")
 	}
@@ -9029,7 +10014,11 @@ func (e *HelpGetNearestDcParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetNearestDc() (*NearestDc, error) {
-	data, err := c.MakeRequest(&HelpGetNearestDcParams{})
+	return c.HelpGetNearestDcContext(context.Background())
+}
+
+func (c *Client) HelpGetNearestDcContext(ctx context.Context) (*NearestDc, error) {
+	data, err := c.MakeRequestContext(ctx, &HelpGetNearestDcParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetNearestDc")
 	}
@@ -9061,7 +10050,11 @@ func (e *HelpGetAppUpdateParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetAppUpdate(params *HelpGetAppUpdateParams) (HelpAppUpdate, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpGetAppUpdateContext(context.Background(), params)
+}
+
+func (c *Client) HelpGetAppUpdateContext(ctx context.Context, params *HelpGetAppUpdateParams) (HelpAppUpdate, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetAppUpdate")
 	}
@@ -9087,7 +10080,11 @@ func (e *HelpGetInviteTextParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetInviteText() (*HelpInviteText, error) {
-	data, err := c.MakeRequest(&HelpGetInviteTextParams{})
+	return c.HelpGetInviteTextContext(context.Background())
+}
+
+func (c *Client) HelpGetInviteTextContext(ctx context.Context) (*HelpInviteText, error) {
+	data, err := c.MakeRequestContext(ctx, &HelpGetInviteTextParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetInviteText")
 	}
@@ -9113,7 +10110,11 @@ func (e *HelpGetSupportParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetSupport() (*HelpSupport, error) {
-	data, err := c.MakeRequest(&HelpGetSupportParams{})
+	return c.HelpGetSupportContext(context.Background())
+}
+
+func (c *Client) HelpGetSupportContext(ctx context.Context) (*HelpSupport, error) {
+	data, err := c.MakeRequestContext(ctx, &HelpGetSupportParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetSupport")
 	}
@@ -9145,7 +10146,11 @@ func (e *HelpGetAppChangelogParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetAppChangelog(params *HelpGetAppChangelogParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpGetAppChangelogContext(context.Background(), params)
+}
+
+func (c *Client) HelpGetAppChangelogContext(ctx context.Context, params *HelpGetAppChangelogParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetAppChangelog")
 	}
@@ -9179,7 +10184,11 @@ func (e *HelpSetBotUpdatesStatusParams) Encode() []byte {
 }
 
 func (c *Client) HelpSetBotUpdatesStatus(params *HelpSetBotUpdatesStatusParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpSetBotUpdatesStatusContext(context.Background(), params)
+}
+
+func (c *Client) HelpSetBotUpdatesStatusContext(ctx context.Context, params *HelpSetBotUpdatesStatusParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpSetBotUpdatesStatus")
 	}
@@ -9205,7 +10214,11 @@ func (e *HelpGetCdnConfigParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetCdnConfig() (*CdnConfig, error) {
-	data, err := c.MakeRequest(&HelpGetCdnConfigParams{})
+	return c.HelpGetCdnConfigContext(context.Background())
+}
+
+func (c *Client) HelpGetCdnConfigContext(ctx context.Context) (*CdnConfig, error) {
+	data, err := c.MakeRequestContext(ctx, &HelpGetCdnConfigParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetCdnConfig")
 	}
@@ -9237,7 +10250,11 @@ func (e *HelpGetRecentMeUrlsParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetRecentMeUrls(params *HelpGetRecentMeUrlsParams) (*HelpRecentMeUrls, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpGetRecentMeUrlsContext(context.Background(), params)
+}
+
+func (c *Client) HelpGetRecentMeUrlsContext(ctx context.Context, params *HelpGetRecentMeUrlsParams) (*HelpRecentMeUrls, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetRecentMeUrls")
 	}
@@ -9263,7 +10280,11 @@ func (e *HelpGetTermsOfServiceUpdateParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetTermsOfServiceUpdate() (HelpTermsOfServiceUpdate, error) {
-	data, err := c.MakeRequest(&HelpGetTermsOfServiceUpdateParams{})
+	return c.HelpGetTermsOfServiceUpdateContext(context.Background())
+}
+
+func (c *Client) HelpGetTermsOfServiceUpdateContext(ctx context.Context) (HelpTermsOfServiceUpdate, error) {
+	data, err := c.MakeRequestContext(ctx, &HelpGetTermsOfServiceUpdateParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetTermsOfServiceUpdate")
 	}
@@ -9295,7 +10316,11 @@ func (e *HelpAcceptTermsOfServiceParams) Encode() []byte {
 }
 
 func (c *Client) HelpAcceptTermsOfService(params *HelpAcceptTermsOfServiceParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpAcceptTermsOfServiceContext(context.Background(), params)
+}
+
+func (c *Client) HelpAcceptTermsOfServiceContext(ctx context.Context, params *HelpAcceptTermsOfServiceParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpAcceptTermsOfService")
 	}
@@ -9327,7 +10352,11 @@ func (e *HelpGetDeepLinkInfoParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetDeepLinkInfo(params *HelpGetDeepLinkInfoParams) (HelpDeepLinkInfo, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpGetDeepLinkInfoContext(context.Background(), params)
+}
+
+func (c *Client) HelpGetDeepLinkInfoContext(ctx context.Context, params *HelpGetDeepLinkInfoParams) (HelpDeepLinkInfo, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetDeepLinkInfo")
 	}
@@ -9353,7 +10382,11 @@ func (e *HelpGetAppConfigParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetAppConfig() (JSONValue, error) {
-	data, err := c.MakeRequest(&HelpGetAppConfigParams{})
+	return c.HelpGetAppConfigContext(context.Background())
+}
+
+func (c *Client) HelpGetAppConfigContext(ctx context.Context) (JSONValue, error) {
+	data, err := c.MakeRequestContext(ctx, &HelpGetAppConfigParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetAppConfig")
 	}
@@ -9385,7 +10418,11 @@ func (e *HelpSaveAppLogParams) Encode() []byte {
 }
 
 func (c *Client) HelpSaveAppLog(params *HelpSaveAppLogParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpSaveAppLogContext(context.Background(), params)
+}
+
+func (c *Client) HelpSaveAppLogContext(ctx context.Context, params *HelpSaveAppLogParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpSaveAppLog")
 	}
@@ -9417,7 +10454,11 @@ func (e *HelpGetPassportConfigParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetPassportConfig(params *HelpGetPassportConfigParams) (HelpPassportConfig, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpGetPassportConfigContext(context.Background(), params)
+}
+
+func (c *Client) HelpGetPassportConfigContext(ctx context.Context, params *HelpGetPassportConfigParams) (HelpPassportConfig, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetPassportConfig")
 	}
@@ -9443,7 +10484,11 @@ func (e *HelpGetSupportNameParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetSupportName() (*HelpSupportName, error) {
-	data, err := c.MakeRequest(&HelpGetSupportNameParams{})
+	return c.HelpGetSupportNameContext(context.Background())
+}
+
+func (c *Client) HelpGetSupportNameContext(ctx context.Context) (*HelpSupportName, error) {
+	data, err := c.MakeRequestContext(ctx, &HelpGetSupportNameParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetSupportName")
 	}
@@ -9475,7 +10520,11 @@ func (e *HelpGetUserInfoParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetUserInfo(params *HelpGetUserInfoParams) (HelpUserInfo, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpGetUserInfoContext(context.Background(), params)
+}
+
+func (c *Client) HelpGetUserInfoContext(ctx context.Context, params *HelpGetUserInfoParams) (HelpUserInfo, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetUserInfo")
 	}
@@ -9511,7 +10560,11 @@ func (e *HelpEditUserInfoParams) Encode() []byte {
 }
 
 func (c *Client) HelpEditUserInfo(params *HelpEditUserInfoParams) (HelpUserInfo, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpEditUserInfoContext(context.Background(), params)
+}
+
+func (c *Client) HelpEditUserInfoContext(ctx context.Context, params *HelpEditUserInfoParams) (HelpUserInfo, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpEditUserInfo")
 	}
@@ -9537,7 +10590,11 @@ func (e *HelpGetPromoDataParams) Encode() []byte {
 }
 
 func (c *Client) HelpGetPromoData() (HelpPromoData, error) {
-	data, err := c.MakeRequest(&HelpGetPromoDataParams{})
+	return c.HelpGetPromoDataContext(context.Background())
+}
+
+func (c *Client) HelpGetPromoDataContext(ctx context.Context) (HelpPromoData, error) {
+	data, err := c.MakeRequestContext(ctx, &HelpGetPromoDataParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpGetPromoData")
 	}
@@ -9569,7 +10626,11 @@ func (e *HelpHidePromoDataParams) Encode() []byte {
 }
 
 func (c *Client) HelpHidePromoData(params *HelpHidePromoDataParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpHidePromoDataContext(context.Background(), params)
+}
+
+func (c *Client) HelpHidePromoDataContext(ctx context.Context, params *HelpHidePromoDataParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpHidePromoData")
 	}
@@ -9601,7 +10662,11 @@ func (e *HelpDismissSuggestionParams) Encode() []byte {
 }
 
 func (c *Client) HelpDismissSuggestion(params *HelpDismissSuggestionParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.HelpDismissSuggestionContext(context.Background(), params)
+}
+
+func (c *Client) HelpDismissSuggestionContext(ctx context.Context, params *HelpDismissSuggestionParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning HelpDismissSuggestion")
 	}
@@ -9635,7 +10700,11 @@ func (e *ChannelsReadHistoryParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsReadHistory(params *ChannelsReadHistoryParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsReadHistoryContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsReadHistoryContext(ctx context.Context, params *ChannelsReadHistoryParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsReadHistory")
 	}
@@ -9669,7 +10738,11 @@ func (e *ChannelsDeleteMessagesParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsDeleteMessages(params *ChannelsDeleteMessagesParams) (*MessagesAffectedMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsDeleteMessagesContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsDeleteMessagesContext(ctx context.Context, params *ChannelsDeleteMessagesParams) (*MessagesAffectedMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsDeleteMessages")
 	}
@@ -9703,7 +10776,11 @@ func (e *ChannelsDeleteUserHistoryParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsDeleteUserHistory(params *ChannelsDeleteUserHistoryParams) (*MessagesAffectedHistory, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsDeleteUserHistoryContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsDeleteUserHistoryContext(ctx context.Context, params *ChannelsDeleteUserHistoryParams) (*MessagesAffectedHistory, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsDeleteUserHistory")
 	}
@@ -9739,7 +10816,11 @@ func (e *ChannelsReportSpamParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsReportSpam(params *ChannelsReportSpamParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsReportSpamContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsReportSpamContext(ctx context.Context, params *ChannelsReportSpamParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsReportSpam")
 	}
@@ -9773,7 +10854,11 @@ func (e *ChannelsGetMessagesParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetMessages(params *ChannelsGetMessagesParams) (MessagesMessages, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsGetMessagesContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsGetMessagesContext(ctx context.Context, params *ChannelsGetMessagesParams) (MessagesMessages, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetMessages")
 	}
@@ -9813,7 +10898,11 @@ func (e *ChannelsGetParticipantsParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetParticipants(params *ChannelsGetParticipantsParams) (ChannelsChannelParticipants, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsGetParticipantsContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsGetParticipantsContext(ctx context.Context, params *ChannelsGetParticipantsParams) (ChannelsChannelParticipants, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetParticipants")
 	}
@@ -9847,7 +10936,11 @@ func (e *ChannelsGetParticipantParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetParticipant(params *ChannelsGetParticipantParams) (*ChannelsChannelParticipant, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsGetParticipantContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsGetParticipantContext(ctx context.Context, params *ChannelsGetParticipantParams) (*ChannelsChannelParticipant, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetParticipant")
 	}
@@ -9879,7 +10972,11 @@ func (e *ChannelsGetChannelsParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetChannels(params *ChannelsGetChannelsParams) (MessagesChats, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsGetChannelsContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsGetChannelsContext(ctx context.Context, params *ChannelsGetChannelsParams) (MessagesChats, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetChannels")
 	}
@@ -9911,7 +11008,11 @@ func (e *ChannelsGetFullChannelParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetFullChannel(params *ChannelsGetFullChannelParams) (*MessagesChatFull, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsGetFullChannelContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsGetFullChannelContext(ctx context.Context, params *ChannelsGetFullChannelParams) (*MessagesChatFull, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetFullChannel")
 	}
@@ -9967,7 +11068,11 @@ func (e *ChannelsCreateChannelParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsCreateChannel(params *ChannelsCreateChannelParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsCreateChannelContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsCreateChannelContext(ctx context.Context, params *ChannelsCreateChannelParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsCreateChannel")
 	}
@@ -10005,7 +11110,11 @@ func (e *ChannelsEditAdminParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsEditAdmin(params *ChannelsEditAdminParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsEditAdminContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsEditAdminContext(ctx context.Context, params *ChannelsEditAdminParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsEditAdmin")
 	}
@@ -10039,7 +11148,11 @@ func (e *ChannelsEditTitleParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsEditTitle(params *ChannelsEditTitleParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsEditTitleContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsEditTitleContext(ctx context.Context, params *ChannelsEditTitleParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsEditTitle")
 	}
@@ -10073,7 +11186,11 @@ func (e *ChannelsEditPhotoParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsEditPhoto(params *ChannelsEditPhotoParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsEditPhotoContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsEditPhotoContext(ctx context.Context, params *ChannelsEditPhotoParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsEditPhoto")
 	}
@@ -10107,7 +11224,11 @@ func (e *ChannelsCheckUsernameParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsCheckUsername(params *ChannelsCheckUsernameParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsCheckUsernameContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsCheckUsernameContext(ctx context.Context, params *ChannelsCheckUsernameParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsCheckUsername")
 	}
@@ -10141,7 +11262,11 @@ func (e *ChannelsUpdateUsernameParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsUpdateUsername(params *ChannelsUpdateUsernameParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsUpdateUsernameContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsUpdateUsernameContext(ctx context.Context, params *ChannelsUpdateUsernameParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsUpdateUsername")
 	}
@@ -10173,7 +11298,11 @@ func (e *ChannelsJoinChannelParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsJoinChannel(params *ChannelsJoinChannelParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsJoinChannelContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsJoinChannelContext(ctx context.Context, params *ChannelsJoinChannelParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsJoinChannel")
 	}
@@ -10205,7 +11334,11 @@ func (e *ChannelsLeaveChannelParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsLeaveChannel(params *ChannelsLeaveChannelParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsLeaveChannelContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsLeaveChannelContext(ctx context.Context, params *ChannelsLeaveChannelParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsLeaveChannel")
 	}
@@ -10239,7 +11372,11 @@ func (e *ChannelsInviteToChannelParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsInviteToChannel(params *ChannelsInviteToChannelParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsInviteToChannelContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsInviteToChannelContext(ctx context.Context, params *ChannelsInviteToChannelParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsInviteToChannel")
 	}
@@ -10271,7 +11408,11 @@ func (e *ChannelsDeleteChannelParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsDeleteChannel(params *ChannelsDeleteChannelParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsDeleteChannelContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsDeleteChannelContext(ctx context.Context, params *ChannelsDeleteChannelParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsDeleteChannel")
 	}
@@ -10307,7 +11448,11 @@ func (e *ChannelsExportMessageLinkParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsExportMessageLink(params *ChannelsExportMessageLinkParams) (*ExportedMessageLink, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsExportMessageLinkContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsExportMessageLinkContext(ctx context.Context, params *ChannelsExportMessageLinkParams) (*ExportedMessageLink, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsExportMessageLink")
 	}
@@ -10341,7 +11486,11 @@ func (e *ChannelsToggleSignaturesParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsToggleSignatures(params *ChannelsToggleSignaturesParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsToggleSignaturesContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsToggleSignaturesContext(ctx context.Context, params *ChannelsToggleSignaturesParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsToggleSignatures")
 	}
@@ -10382,7 +11531,11 @@ func (e *ChannelsGetAdminedPublicChannelsParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetAdminedPublicChannels(params *ChannelsGetAdminedPublicChannelsParams) (MessagesChats, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsGetAdminedPublicChannelsContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsGetAdminedPublicChannelsContext(ctx context.Context, params *ChannelsGetAdminedPublicChannelsParams) (MessagesChats, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetAdminedPublicChannels")
 	}
@@ -10418,7 +11571,11 @@ func (e *ChannelsEditBannedParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsEditBanned(params *ChannelsEditBannedParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsEditBannedContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsEditBannedContext(ctx context.Context, params *ChannelsEditBannedParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsEditBanned")
 	}
@@ -10475,7 +11632,11 @@ func (e *ChannelsGetAdminLogParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetAdminLog(params *ChannelsGetAdminLogParams) (*ChannelsAdminLogResults, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsGetAdminLogContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsGetAdminLogContext(ctx context.Context, params *ChannelsGetAdminLogParams) (*ChannelsAdminLogResults, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetAdminLog")
 	}
@@ -10509,7 +11670,11 @@ func (e *ChannelsSetStickersParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsSetStickers(params *ChannelsSetStickersParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsSetStickersContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsSetStickersContext(ctx context.Context, params *ChannelsSetStickersParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsSetStickers")
 	}
@@ -10543,7 +11708,11 @@ func (e *ChannelsReadMessageContentsParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsReadMessageContents(params *ChannelsReadMessageContentsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsReadMessageContentsContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsReadMessageContentsContext(ctx context.Context, params *ChannelsReadMessageContentsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsReadMessageContents")
 	}
@@ -10577,7 +11746,11 @@ func (e *ChannelsDeleteHistoryParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsDeleteHistory(params *ChannelsDeleteHistoryParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsDeleteHistoryContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsDeleteHistoryContext(ctx context.Context, params *ChannelsDeleteHistoryParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsDeleteHistory")
 	}
@@ -10611,7 +11784,11 @@ func (e *ChannelsTogglePreHistoryHiddenParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsTogglePreHistoryHidden(params *ChannelsTogglePreHistoryHiddenParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsTogglePreHistoryHiddenContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsTogglePreHistoryHiddenContext(ctx context.Context, params *ChannelsTogglePreHistoryHiddenParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsTogglePreHistoryHidden")
 	}
@@ -10643,7 +11820,11 @@ func (e *ChannelsGetLeftChannelsParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetLeftChannels(params *ChannelsGetLeftChannelsParams) (MessagesChats, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsGetLeftChannelsContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsGetLeftChannelsContext(ctx context.Context, params *ChannelsGetLeftChannelsParams) (MessagesChats, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetLeftChannels")
 	}
@@ -10669,7 +11850,11 @@ func (e *ChannelsGetGroupsForDiscussionParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetGroupsForDiscussion() (MessagesChats, error) {
-	data, err := c.MakeRequest(&ChannelsGetGroupsForDiscussionParams{})
+	return c.ChannelsGetGroupsForDiscussionContext(context.Background())
+}
+
+func (c *Client) ChannelsGetGroupsForDiscussionContext(ctx context.Context) (MessagesChats, error) {
+	data, err := c.MakeRequestContext(ctx, &ChannelsGetGroupsForDiscussionParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetGroupsForDiscussion")
 	}
@@ -10703,7 +11888,11 @@ func (e *ChannelsSetDiscussionGroupParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsSetDiscussionGroup(params *ChannelsSetDiscussionGroupParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsSetDiscussionGroupContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsSetDiscussionGroupContext(ctx context.Context, params *ChannelsSetDiscussionGroupParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsSetDiscussionGroup")
 	}
@@ -10739,7 +11928,11 @@ func (e *ChannelsEditCreatorParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsEditCreator(params *ChannelsEditCreatorParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsEditCreatorContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsEditCreatorContext(ctx context.Context, params *ChannelsEditCreatorParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsEditCreator")
 	}
@@ -10775,7 +11968,11 @@ func (e *ChannelsEditLocationParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsEditLocation(params *ChannelsEditLocationParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsEditLocationContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsEditLocationContext(ctx context.Context, params *ChannelsEditLocationParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsEditLocation")
 	}
@@ -10809,7 +12006,11 @@ func (e *ChannelsToggleSlowModeParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsToggleSlowMode(params *ChannelsToggleSlowModeParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.ChannelsToggleSlowModeContext(context.Background(), params)
+}
+
+func (c *Client) ChannelsToggleSlowModeContext(ctx context.Context, params *ChannelsToggleSlowModeParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsToggleSlowMode")
 	}
@@ -10835,7 +12036,11 @@ func (e *ChannelsGetInactiveChannelsParams) Encode() []byte {
 }
 
 func (c *Client) ChannelsGetInactiveChannels() (*MessagesInactiveChats, error) {
-	data, err := c.MakeRequest(&ChannelsGetInactiveChannelsParams{})
+	return c.ChannelsGetInactiveChannelsContext(context.Background())
+}
+
+func (c *Client) ChannelsGetInactiveChannelsContext(ctx context.Context) (*MessagesInactiveChats, error) {
+	data, err := c.MakeRequestContext(ctx, &ChannelsGetInactiveChannelsParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning ChannelsGetInactiveChannels")
 	}
@@ -10869,7 +12074,11 @@ func (e *BotsSendCustomRequestParams) Encode() []byte {
 }
 
 func (c *Client) BotsSendCustomRequest(params *BotsSendCustomRequestParams) (*DataJSON, error) {
-	data, err := c.MakeRequest(params)
+	return c.BotsSendCustomRequestContext(context.Background(), params)
+}
+
+func (c *Client) BotsSendCustomRequestContext(ctx context.Context, params *BotsSendCustomRequestParams) (*DataJSON, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning BotsSendCustomRequest")
 	}
@@ -10903,7 +12112,11 @@ func (e *BotsAnswerWebhookJSONQueryParams) Encode() []byte {
 }
 
 func (c *Client) BotsAnswerWebhookJSONQuery(params *BotsAnswerWebhookJSONQueryParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.BotsAnswerWebhookJSONQueryContext(context.Background(), params)
+}
+
+func (c *Client) BotsAnswerWebhookJSONQueryContext(ctx context.Context, params *BotsAnswerWebhookJSONQueryParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning BotsAnswerWebhookJSONQuery")
 	}
@@ -10935,7 +12148,11 @@ func (e *BotsSetBotCommandsParams) Encode() []byte {
 }
 
 func (c *Client) BotsSetBotCommands(params *BotsSetBotCommandsParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.BotsSetBotCommandsContext(context.Background(), params)
+}
+
+func (c *Client) BotsSetBotCommandsContext(ctx context.Context, params *BotsSetBotCommandsParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning BotsSetBotCommands")
 	}
@@ -10967,7 +12184,11 @@ func (e *PaymentsGetPaymentFormParams) Encode() []byte {
 }
 
 func (c *Client) PaymentsGetPaymentForm(params *PaymentsGetPaymentFormParams) (*PaymentsPaymentForm, error) {
-	data, err := c.MakeRequest(params)
+	return c.PaymentsGetPaymentFormContext(context.Background(), params)
+}
+
+func (c *Client) PaymentsGetPaymentFormContext(ctx context.Context, params *PaymentsGetPaymentFormParams) (*PaymentsPaymentForm, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PaymentsGetPaymentForm")
 	}
@@ -10999,7 +12220,11 @@ func (e *PaymentsGetPaymentReceiptParams) Encode() []byte {
 }
 
 func (c *Client) PaymentsGetPaymentReceipt(params *PaymentsGetPaymentReceiptParams) (*PaymentsPaymentReceipt, error) {
-	data, err := c.MakeRequest(params)
+	return c.PaymentsGetPaymentReceiptContext(context.Background(), params)
+}
+
+func (c *Client) PaymentsGetPaymentReceiptContext(ctx context.Context, params *PaymentsGetPaymentReceiptParams) (*PaymentsPaymentReceipt, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PaymentsGetPaymentReceipt")
 	}
@@ -11040,7 +12265,11 @@ func (e *PaymentsValidateRequestedInfoParams) Encode() []byte {
 }
 
 func (c *Client) PaymentsValidateRequestedInfo(params *PaymentsValidateRequestedInfoParams) (*PaymentsValidatedRequestedInfo, error) {
-	data, err := c.MakeRequest(params)
+	return c.PaymentsValidateRequestedInfoContext(context.Background(), params)
+}
+
+func (c *Client) PaymentsValidateRequestedInfoContext(ctx context.Context, params *PaymentsValidateRequestedInfoParams) (*PaymentsValidatedRequestedInfo, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PaymentsValidateRequestedInfo")
 	}
@@ -11091,7 +12320,11 @@ func (e *PaymentsSendPaymentFormParams) Encode() []byte {
 }
 
 func (c *Client) PaymentsSendPaymentForm(params *PaymentsSendPaymentFormParams) (PaymentsPaymentResult, error) {
-	data, err := c.MakeRequest(params)
+	return c.PaymentsSendPaymentFormContext(context.Background(), params)
+}
+
+func (c *Client) PaymentsSendPaymentFormContext(ctx context.Context, params *PaymentsSendPaymentFormParams) (PaymentsPaymentResult, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PaymentsSendPaymentForm")
 	}
@@ -11117,7 +12350,11 @@ func (e *PaymentsGetSavedInfoParams) Encode() []byte {
 }
 
 func (c *Client) PaymentsGetSavedInfo() (*PaymentsSavedInfo, error) {
-	data, err := c.MakeRequest(&PaymentsGetSavedInfoParams{})
+	return c.PaymentsGetSavedInfoContext(context.Background())
+}
+
+func (c *Client) PaymentsGetSavedInfoContext(ctx context.Context) (*PaymentsSavedInfo, error) {
+	data, err := c.MakeRequestContext(ctx, &PaymentsGetSavedInfoParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PaymentsGetSavedInfo")
 	}
@@ -11158,7 +12395,11 @@ func (e *PaymentsClearSavedInfoParams) Encode() []byte {
 }
 
 func (c *Client) PaymentsClearSavedInfo(params *PaymentsClearSavedInfoParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.PaymentsClearSavedInfoContext(context.Background(), params)
+}
+
+func (c *Client) PaymentsClearSavedInfoContext(ctx context.Context, params *PaymentsClearSavedInfoParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PaymentsClearSavedInfo")
 	}
@@ -11190,7 +12431,11 @@ func (e *PaymentsGetBankCardDataParams) Encode() []byte {
 }
 
 func (c *Client) PaymentsGetBankCardData(params *PaymentsGetBankCardDataParams) (*PaymentsBankCardData, error) {
-	data, err := c.MakeRequest(params)
+	return c.PaymentsGetBankCardDataContext(context.Background(), params)
+}
+
+func (c *Client) PaymentsGetBankCardDataContext(ctx context.Context, params *PaymentsGetBankCardDataParams) (*PaymentsBankCardData, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PaymentsGetBankCardData")
 	}
@@ -11246,7 +12491,11 @@ func (e *StickersCreateStickerSetParams) Encode() []byte {
 }
 
 func (c *Client) StickersCreateStickerSet(params *StickersCreateStickerSetParams) (*MessagesStickerSet, error) {
-	data, err := c.MakeRequest(params)
+	return c.StickersCreateStickerSetContext(context.Background(), params)
+}
+
+func (c *Client) StickersCreateStickerSetContext(ctx context.Context, params *StickersCreateStickerSetParams) (*MessagesStickerSet, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning StickersCreateStickerSet")
 	}
@@ -11278,7 +12527,11 @@ func (e *StickersRemoveStickerFromSetParams) Encode() []byte {
 }
 
 func (c *Client) StickersRemoveStickerFromSet(params *StickersRemoveStickerFromSetParams) (*MessagesStickerSet, error) {
-	data, err := c.MakeRequest(params)
+	return c.StickersRemoveStickerFromSetContext(context.Background(), params)
+}
+
+func (c *Client) StickersRemoveStickerFromSetContext(ctx context.Context, params *StickersRemoveStickerFromSetParams) (*MessagesStickerSet, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning StickersRemoveStickerFromSet")
 	}
@@ -11312,7 +12565,11 @@ func (e *StickersChangeStickerPositionParams) Encode() []byte {
 }
 
 func (c *Client) StickersChangeStickerPosition(params *StickersChangeStickerPositionParams) (*MessagesStickerSet, error) {
-	data, err := c.MakeRequest(params)
+	return c.StickersChangeStickerPositionContext(context.Background(), params)
+}
+
+func (c *Client) StickersChangeStickerPositionContext(ctx context.Context, params *StickersChangeStickerPositionParams) (*MessagesStickerSet, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning StickersChangeStickerPosition")
 	}
@@ -11346,7 +12603,11 @@ func (e *StickersAddStickerToSetParams) Encode() []byte {
 }
 
 func (c *Client) StickersAddStickerToSet(params *StickersAddStickerToSetParams) (*MessagesStickerSet, error) {
-	data, err := c.MakeRequest(params)
+	return c.StickersAddStickerToSetContext(context.Background(), params)
+}
+
+func (c *Client) StickersAddStickerToSetContext(ctx context.Context, params *StickersAddStickerToSetParams) (*MessagesStickerSet, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning StickersAddStickerToSet")
 	}
@@ -11380,7 +12641,11 @@ func (e *StickersSetStickerSetThumbParams) Encode() []byte {
 }
 
 func (c *Client) StickersSetStickerSetThumb(params *StickersSetStickerSetThumbParams) (*MessagesStickerSet, error) {
-	data, err := c.MakeRequest(params)
+	return c.StickersSetStickerSetThumbContext(context.Background(), params)
+}
+
+func (c *Client) StickersSetStickerSetThumbContext(ctx context.Context, params *StickersSetStickerSetThumbParams) (*MessagesStickerSet, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning StickersSetStickerSetThumb")
 	}
@@ -11406,7 +12671,11 @@ func (e *PhoneGetCallConfigParams) Encode() []byte {
 }
 
 func (c *Client) PhoneGetCallConfig() (*DataJSON, error) {
-	data, err := c.MakeRequest(&PhoneGetCallConfigParams{})
+	return c.PhoneGetCallConfigContext(context.Background())
+}
+
+func (c *Client) PhoneGetCallConfigContext(ctx context.Context) (*DataJSON, error) {
+	data, err := c.MakeRequestContext(ctx, &PhoneGetCallConfigParams{})
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhoneGetCallConfig")
 	}
@@ -11451,7 +12720,11 @@ func (e *PhoneRequestCallParams) Encode() []byte {
 }
 
 func (c *Client) PhoneRequestCall(params *PhoneRequestCallParams) (*PhonePhoneCall, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhoneRequestCallContext(context.Background(), params)
+}
+
+func (c *Client) PhoneRequestCallContext(ctx context.Context, params *PhoneRequestCallParams) (*PhonePhoneCall, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhoneRequestCall")
 	}
@@ -11487,7 +12760,11 @@ func (e *PhoneAcceptCallParams) Encode() []byte {
 }
 
 func (c *Client) PhoneAcceptCall(params *PhoneAcceptCallParams) (*PhonePhoneCall, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhoneAcceptCallContext(context.Background(), params)
+}
+
+func (c *Client) PhoneAcceptCallContext(ctx context.Context, params *PhoneAcceptCallParams) (*PhonePhoneCall, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhoneAcceptCall")
 	}
@@ -11525,7 +12802,11 @@ func (e *PhoneConfirmCallParams) Encode() []byte {
 }
 
 func (c *Client) PhoneConfirmCall(params *PhoneConfirmCallParams) (*PhonePhoneCall, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhoneConfirmCallContext(context.Background(), params)
+}
+
+func (c *Client) PhoneConfirmCallContext(ctx context.Context, params *PhoneConfirmCallParams) (*PhonePhoneCall, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhoneConfirmCall")
 	}
@@ -11557,7 +12838,11 @@ func (e *PhoneReceivedCallParams) Encode() []byte {
 }
 
 func (c *Client) PhoneReceivedCall(params *PhoneReceivedCallParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhoneReceivedCallContext(context.Background(), params)
+}
+
+func (c *Client) PhoneReceivedCallContext(ctx context.Context, params *PhoneReceivedCallParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhoneReceivedCall")
 	}
@@ -11602,7 +12887,11 @@ func (e *PhoneDiscardCallParams) Encode() []byte {
 }
 
 func (c *Client) PhoneDiscardCall(params *PhoneDiscardCallParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhoneDiscardCallContext(context.Background(), params)
+}
+
+func (c *Client) PhoneDiscardCallContext(ctx context.Context, params *PhoneDiscardCallParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhoneDiscardCall")
 	}
@@ -11645,7 +12934,11 @@ func (e *PhoneSetCallRatingParams) Encode() []byte {
 }
 
 func (c *Client) PhoneSetCallRating(params *PhoneSetCallRatingParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhoneSetCallRatingContext(context.Background(), params)
+}
+
+func (c *Client) PhoneSetCallRatingContext(ctx context.Context, params *PhoneSetCallRatingParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhoneSetCallRating")
 	}
@@ -11679,7 +12972,11 @@ func (e *PhoneSaveCallDebugParams) Encode() []byte {
 }
 
 func (c *Client) PhoneSaveCallDebug(params *PhoneSaveCallDebugParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhoneSaveCallDebugContext(context.Background(), params)
+}
+
+func (c *Client) PhoneSaveCallDebugContext(ctx context.Context, params *PhoneSaveCallDebugParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhoneSaveCallDebug")
 	}
@@ -11713,7 +13010,11 @@ func (e *PhoneSendSignalingDataParams) Encode() []byte {
 }
 
 func (c *Client) PhoneSendSignalingData(params *PhoneSendSignalingDataParams) (*serialize.Bool, error) {
-	data, err := c.MakeRequest(params)
+	return c.PhoneSendSignalingDataContext(context.Background(), params)
+}
+
+func (c *Client) PhoneSendSignalingDataContext(ctx context.Context, params *PhoneSendSignalingDataParams) (*serialize.Bool, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning PhoneSendSignalingData")
 	}
@@ -11747,7 +13048,11 @@ func (e *LangpackGetLangPackParams) Encode() []byte {
 }
 
 func (c *Client) LangpackGetLangPack(params *LangpackGetLangPackParams) (*LangPackDifference, error) {
-	data, err := c.MakeRequest(params)
+	return c.LangpackGetLangPackContext(context.Background(), params)
+}
+
+func (c *Client) LangpackGetLangPackContext(ctx context.Context, params *LangpackGetLangPackParams) (*LangPackDifference, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning LangpackGetLangPack")
 	}
@@ -11783,7 +13088,11 @@ func (e *LangpackGetStringsParams) Encode() []byte {
 }
 
 func (c *Client) LangpackGetStrings(params *LangpackGetStringsParams) (LangPackString, error) {
-	data, err := c.MakeRequest(params)
+	return c.LangpackGetStringsContext(context.Background(), params)
+}
+
+func (c *Client) LangpackGetStringsContext(ctx context.Context, params *LangpackGetStringsParams) (LangPackString, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning LangpackGetStrings")
 	}
@@ -11819,7 +13128,11 @@ func (e *LangpackGetDifferenceParams) Encode() []byte {
 }
 
 func (c *Client) LangpackGetDifference(params *LangpackGetDifferenceParams) (*LangPackDifference, error) {
-	data, err := c.MakeRequest(params)
+	return c.LangpackGetDifferenceContext(context.Background(), params)
+}
+
+func (c *Client) LangpackGetDifferenceContext(ctx context.Context, params *LangpackGetDifferenceParams) (*LangPackDifference, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning LangpackGetDifference")
 	}
@@ -11851,7 +13164,11 @@ func (e *LangpackGetLanguagesParams) Encode() []byte {
 }
 
 func (c *Client) LangpackGetLanguages(params *LangpackGetLanguagesParams) (*LangPackLanguage, error) {
-	data, err := c.MakeRequest(params)
+	return c.LangpackGetLanguagesContext(context.Background(), params)
+}
+
+func (c *Client) LangpackGetLanguagesContext(ctx context.Context, params *LangpackGetLanguagesParams) (*LangPackLanguage, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning LangpackGetLanguages")
 	}
@@ -11885,7 +13202,11 @@ func (e *LangpackGetLanguageParams) Encode() []byte {
 }
 
 func (c *Client) LangpackGetLanguage(params *LangpackGetLanguageParams) (*LangPackLanguage, error) {
-	data, err := c.MakeRequest(params)
+	return c.LangpackGetLanguageContext(context.Background(), params)
+}
+
+func (c *Client) LangpackGetLanguageContext(ctx context.Context, params *LangpackGetLanguageParams) (*LangPackLanguage, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning LangpackGetLanguage")
 	}
@@ -11917,7 +13238,11 @@ func (e *FoldersEditPeerFoldersParams) Encode() []byte {
 }
 
 func (c *Client) FoldersEditPeerFolders(params *FoldersEditPeerFoldersParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.FoldersEditPeerFoldersContext(context.Background(), params)
+}
+
+func (c *Client) FoldersEditPeerFoldersContext(ctx context.Context, params *FoldersEditPeerFoldersParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning FoldersEditPeerFolders")
 	}
@@ -11949,7 +13274,11 @@ func (e *FoldersDeleteFolderParams) Encode() []byte {
 }
 
 func (c *Client) FoldersDeleteFolder(params *FoldersDeleteFolderParams) (Updates, error) {
-	data, err := c.MakeRequest(params)
+	return c.FoldersDeleteFolderContext(context.Background(), params)
+}
+
+func (c *Client) FoldersDeleteFolderContext(ctx context.Context, params *FoldersDeleteFolderParams) (Updates, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning FoldersDeleteFolder")
 	}
@@ -11988,7 +13317,11 @@ func (e *StatsGetBroadcastStatsParams) Encode() []byte {
 }
 
 func (c *Client) StatsGetBroadcastStats(params *StatsGetBroadcastStatsParams) (*StatsBroadcastStats, error) {
-	data, err := c.MakeRequest(params)
+	return c.StatsGetBroadcastStatsContext(context.Background(), params)
+}
+
+func (c *Client) StatsGetBroadcastStatsContext(ctx context.Context, params *StatsGetBroadcastStatsParams) (*StatsBroadcastStats, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning StatsGetBroadcastStats")
 	}
@@ -12030,7 +13363,11 @@ func (e *StatsLoadAsyncGraphParams) Encode() []byte {
 }
 
 func (c *Client) StatsLoadAsyncGraph(params *StatsLoadAsyncGraphParams) (StatsGraph, error) {
-	data, err := c.MakeRequest(params)
+	return c.StatsLoadAsyncGraphContext(context.Background(), params)
+}
+
+func (c *Client) StatsLoadAsyncGraphContext(ctx context.Context, params *StatsLoadAsyncGraphParams) (StatsGraph, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning StatsLoadAsyncGraph")
 	}
@@ -12069,7 +13406,11 @@ func (e *StatsGetMegagroupStatsParams) Encode() []byte {
 }
 
 func (c *Client) StatsGetMegagroupStats(params *StatsGetMegagroupStatsParams) (*StatsMegagroupStats, error) {
-	data, err := c.MakeRequest(params)
+	return c.StatsGetMegagroupStatsContext(context.Background(), params)
+}
+
+func (c *Client) StatsGetMegagroupStatsContext(ctx context.Context, params *StatsGetMegagroupStatsParams) (*StatsMegagroupStats, error) {
+	data, err := c.MakeRequestContext(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "sedning StatsGetMegagroupStats")
 	}
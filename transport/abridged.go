@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	wordLen = 4
+
+	// если длина пакета больше или равна 127 слов, то кодируем 4 байтами, 1 это магическое число,
+	// оставшиеся 3 — длина
+	abridgedLongLenMarker = 0x7f
+	// больше трех байт под длину в abridged не влезает
+	abridgedMaxWords = 1<<24 - 1
+
+	// длина приходит от сервера (или от того, кто за него выдает себя), поэтому больше этого под
+	// пакет не выделяем. самые большие ответы, вроде upload.file, заметно меньше
+	maxPacketLen = 4 << 20
+)
+
+// https://core.telegram.org/mtproto/mtproto-transports#abridged
+type abridged struct{}
+
+// NewAbridged возвращает самый компактный режим: длина пакета кодируется в словах одним
+// байтом (или четырьмя, если пакет большой)
+func NewAbridged() Transport {
+	return abridged{}
+}
+
+func (abridged) Tag() []byte {
+	return []byte{0xef}
+}
+
+func (abridged) NewCodec() Codec {
+	return abridgedCodec{}
+}
+
+// ProtocolID нужен для obfuscated2, в заголовке тег всегда занимает 4 байта
+func (abridged) ProtocolID() [4]byte {
+	return [4]byte{0xef, 0xef, 0xef, 0xef}
+}
+
+type abridgedCodec struct{}
+
+func (abridgedCodec) WritePacket(w io.Writer, data []byte) error {
+	if len(data)%wordLen != 0 {
+		return ErrPacketNotAligned
+	}
+	words := len(data) / wordLen
+	if words > abridgedMaxWords {
+		return ErrPacketTooBig
+	}
+
+	var header []byte
+	if words < abridgedLongLenMarker {
+		header = []byte{byte(words)}
+	} else {
+		header = make([]byte, wordLen)
+		binary.LittleEndian.PutUint32(header, uint32(words)<<8|abridgedLongLenMarker)
+	}
+
+	_, err := w.Write(append(header, data...))
+	return err
+}
+
+func (abridgedCodec) ReadPacket(r io.Reader) ([]byte, error) {
+	header := make([]byte, wordLen)
+	if _, err := io.ReadFull(r, header[:1]); err != nil {
+		return nil, errors.Wrap(err, "reading length")
+	}
+
+	words := int(header[0])
+	if header[0] == abridgedLongLenMarker {
+		if _, err := io.ReadFull(r, header[1:]); err != nil {
+			return nil, errors.Wrap(err, "reading length")
+		}
+		words = int(binary.LittleEndian.Uint32(header) >> 8)
+	}
+	if words*wordLen > maxPacketLen {
+		return nil, errors.Wrapf(ErrPacketTooBig, "%d bytes", words*wordLen)
+	}
+
+	data := make([]byte, words*wordLen)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errors.Wrap(err, "reading packet")
+	}
+
+	return data, nil
+}
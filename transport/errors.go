@@ -0,0 +1,25 @@
+package transport
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrPacketTooBig       = errors.New("packet is too big for this transport")
+	ErrPacketNotAligned   = errors.New("packet length is not divisible by 4")
+	ErrChecksumMismatched = errors.New("packet checksum mismatched")
+	ErrNotObfuscatable    = errors.New("transport can't be used inside obfuscated2")
+	ErrInvalidSecret      = errors.New("invalid proxy secret")
+)
+
+// ErrSeqNoMismatched возвращается транспортом full, если сервер прислал пакет не с тем номером
+type ErrSeqNoMismatched struct {
+	Expected int32
+	Got      int32
+}
+
+func (e *ErrSeqNoMismatched) Error() string {
+	return fmt.Sprintf("wrong packet seqno: expected %d, got %d", e.Expected, e.Got)
+}
@@ -0,0 +1,287 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// fake-TLS режим MTProxy: соединение начинается с поддельного ClientHello, в random которого
+// лежит hmac от секрета, а дальше весь поток obfuscated2 передается внутри tls записей
+// application data. со стороны это выглядит как обычное tls соединение с домена из секрета.
+
+const (
+	tlsRecordHandshake        = 0x16
+	tlsRecordChangeCipherSpec = 0x14
+	tlsRecordApplicationData  = 0x17
+
+	tlsRecordHeaderLen = 5
+	tlsMaxRecordLen    = 16384
+
+	// размер ClientHello, который отправляют настоящие браузеры, прокси ожидает примерно его же
+	tlsClientHelloLen = 517
+	// где в ClientHello и ServerHello лежит random
+	tlsRandomOffset = 11
+	tlsRandomLen    = 32
+)
+
+var (
+	tlsVersion          = []byte{0x03, 0x03}
+	tlsChangeCipherSpec = []byte{tlsRecordChangeCipherSpec, 0x03, 0x03, 0x00, 0x01, 0x01}
+)
+
+// fakeTLSConn это поток байт внутри tls записей. запись и чтение делаются без шифрования, все
+// шифрование на себя берет obfuscated2 уровнем выше
+type fakeTLSConn struct {
+	conn io.ReadWriteCloser
+
+	// остаток данных из последней прочитанной записи
+	readBuf []byte
+	// перед первой записью клиент должен отправить ChangeCipherSpec
+	sentChangeCipherSpec bool
+}
+
+// fakeTLSHandshake отправляет ClientHello, проверяет, что ответил именно прокси с этим секретом,
+// и возвращает поток, который заворачивает данные в tls записи
+func fakeTLSHandshake(conn io.ReadWriteCloser, secret *ProxySecret) (io.ReadWriteCloser, error) {
+	hello := clientHello(secret.Domain)
+	random := signClientHello(hello, secret.Key, time.Now())
+
+	if _, err := conn.Write(hello); err != nil {
+		return nil, errors.Wrap(err, "writing client hello")
+	}
+
+	// сервер отвечает тремя записями: ServerHello, ChangeCipherSpec и application data со
+	// случайным мусором
+	response := bytes.NewBuffer(nil)
+	for _, want := range []byte{tlsRecordHandshake, tlsRecordChangeCipherSpec, tlsRecordApplicationData} {
+		header, payload, err := readTLSRecord(conn)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading server hello")
+		}
+		if header[0] != want {
+			return nil, errors.Errorf("unexpected tls record type %#x, want %#x", header[0], want)
+		}
+		response.Write(header)
+		response.Write(payload)
+	}
+
+	data := response.Bytes()
+	if len(data) < tlsRandomOffset+tlsRandomLen {
+		return nil, errors.New("server hello is too small")
+	}
+	serverRandom := make([]byte, tlsRandomLen)
+	copy(serverRandom, data[tlsRandomOffset:])
+	copy(data[tlsRandomOffset:tlsRandomOffset+tlsRandomLen], make([]byte, tlsRandomLen))
+
+	mac := hmac.New(sha256.New, secret.Key)
+	mac.Write(random)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), serverRandom) {
+		return nil, errors.New("server hello digest mismatched, proxy doesn't know the secret")
+	}
+
+	return &fakeTLSConn{conn: conn}, nil
+}
+
+func (c *fakeTLSConn) Write(p []byte) (int, error) {
+	buf := bytes.NewBuffer(nil)
+	if !c.sentChangeCipherSpec {
+		buf.Write(tlsChangeCipherSpec)
+	}
+
+	for rest := p; len(rest) > 0; {
+		n := len(rest)
+		if n > tlsMaxRecordLen {
+			n = tlsMaxRecordLen
+		}
+		writeTLSRecordHeader(buf, tlsRecordApplicationData, n)
+		buf.Write(rest[:n])
+		rest = rest[n:]
+	}
+
+	if _, err := c.conn.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	c.sentChangeCipherSpec = true
+	return len(p), nil
+}
+
+func (c *fakeTLSConn) Read(p []byte) (int, error) {
+	for len(c.readBuf) == 0 {
+		header, payload, err := readTLSRecord(c.conn)
+		if err != nil {
+			return 0, err
+		}
+
+		switch header[0] {
+		case tlsRecordApplicationData:
+			c.readBuf = payload
+		case tlsRecordChangeCipherSpec:
+			// игнорим, сервер может прислать его в любой момент
+		default:
+			return 0, errors.Errorf("unexpected tls record type %#x", header[0])
+		}
+	}
+
+	n := copy(p, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+func (c *fakeTLSConn) SetReadDeadline(t time.Time) error {
+	if d, ok := c.conn.(interface{ SetReadDeadline(time.Time) error }); ok {
+		return d.SetReadDeadline(t)
+	}
+	return nil
+}
+
+func (c *fakeTLSConn) Close() error {
+	return c.conn.Close()
+}
+
+func readTLSRecord(r io.Reader) (header, payload []byte, err error) {
+	header = make([]byte, tlsRecordHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, err
+	}
+	if header[1] != tlsVersion[0] {
+		return nil, nil, errors.Errorf("invalid tls record version %#x%02x", header[1], header[2])
+	}
+
+	payload = make([]byte, int(binary.BigEndian.Uint16(header[3:])))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, err
+	}
+
+	return header, payload, nil
+}
+
+func writeTLSRecordHeader(buf *bytes.Buffer, typ byte, size int) {
+	buf.WriteByte(typ)
+	buf.Write(tlsVersion)
+	_ = binary.Write(buf, binary.BigEndian, uint16(size))
+}
+
+// signClientHello записывает в random hmac от всего ClientHello, а последние 4 байта ксорит с
+// текущим временем, что бы прокси мог отсечь повторы. возвращает получившийся random
+func signClientHello(hello, key []byte, now time.Time) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(hello)
+	random := mac.Sum(nil)
+
+	timestamp := make([]byte, 4)
+	binary.LittleEndian.PutUint32(timestamp, uint32(now.Unix()))
+	xor(random[tlsRandomLen-4:], timestamp)
+
+	copy(hello[tlsRandomOffset:], random)
+	return random
+}
+
+// clientHello собирает ClientHello, похожий на тот, что отправляет chrome. random пока заполнен
+// нулями, его записывает signClientHello
+func clientHello(domain string) []byte {
+	grease := randomGrease()
+
+	body := bytes.NewBuffer(nil)
+	body.Write(tlsVersion)
+	body.Write(make([]byte, tlsRandomLen))
+	body.WriteByte(0x20) // session id
+	body.Write(randomBytes(32))
+	putUint16List(body, grease, 0x1301, 0x1302, 0x1303, 0xc02b, 0xc02f, 0xc02c, 0xc030, 0xcca9, 0xcca8,
+		0xc013, 0xc014, 0x009c, 0x009d, 0x002f, 0x0035)
+	body.Write([]byte{0x01, 0x00}) // compression methods: только null
+
+	ext := bytes.NewBuffer(nil)
+	putExtension(ext, grease, nil)
+	serverName := bytes.NewBuffer(nil)
+	_ = binary.Write(serverName, binary.BigEndian, uint16(len(domain)+3))
+	serverName.WriteByte(0x00) // host_name
+	_ = binary.Write(serverName, binary.BigEndian, uint16(len(domain)))
+	serverName.WriteString(domain)
+	putExtension(ext, 0x0000, serverName.Bytes())
+	putExtension(ext, 0x0017, nil)                                        // extended_master_secret
+	putExtension(ext, 0xff01, []byte{0x00})                               // renegotiation_info
+	putExtension(ext, 0x000a, uint16List(grease, 0x001d, 0x0017, 0x0018)) // supported_groups
+	putExtension(ext, 0x000b, []byte{0x01, 0x00})                         // ec_point_formats
+	putExtension(ext, 0x0023, nil)                                        // session_ticket
+	putExtension(ext, 0x0010, []byte{0x00, 0x0c, 0x02, 'h', '2', 0x08, 'h', 't', 't', 'p', '/', '1', '.', '1'})
+	putExtension(ext, 0x0005, []byte{0x01, 0x00, 0x00, 0x00, 0x00}) // status_request
+	putExtension(ext, 0x000d, uint16List(0x0403, 0x0804, 0x0401, 0x0503, 0x0805, 0x0501, 0x0806, 0x0601))
+	putExtension(ext, 0x0012, nil) // signed_certificate_timestamp
+	keyShare := bytes.NewBuffer(nil)
+	_ = binary.Write(keyShare, binary.BigEndian, uint16(4+1+4+32))
+	_ = binary.Write(keyShare, binary.BigEndian, []uint16{grease, 1})
+	keyShare.WriteByte(0x00)
+	_ = binary.Write(keyShare, binary.BigEndian, []uint16{0x001d, 32})
+	keyShare.Write(randomBytes(32))
+	putExtension(ext, 0x0033, keyShare.Bytes())
+	putExtension(ext, 0x002d, []byte{0x01, 0x01}) // psk_key_exchange_modes
+	versions := uint16List(grease, 0x0304, 0x0303, 0x0302, 0x0301)
+	putExtension(ext, 0x002b, append([]byte{byte(len(versions) - 2)}, versions[2:]...))
+	putExtension(ext, 0x001b, []byte{0x02, 0x00, 0x02}) // compress_certificate
+	putExtension(ext, grease^0x1010, []byte{0x00})
+
+	// добиваем padding расширением до размера, как у браузера
+	const headersLen = tlsRecordHeaderLen + 4 + 2 + 4 // запись, заголовок handshake, длина расширений, заголовок padding
+	if padding := tlsClientHelloLen - headersLen - body.Len() - ext.Len(); padding >= 0 {
+		putExtension(ext, 0x0015, make([]byte, padding))
+	}
+
+	_ = binary.Write(body, binary.BigEndian, uint16(ext.Len()))
+	body.Write(ext.Bytes())
+
+	hello := bytes.NewBuffer(nil)
+	writeTLSRecordHeader(hello, tlsRecordHandshake, body.Len()+4)
+	hello.Bytes()[2] = 0x01 // ClientHello всегда приходит в записи версии TLS 1.0
+	hello.WriteByte(0x01)   // client_hello
+	hello.Write([]byte{byte(body.Len() >> 16), byte(body.Len() >> 8), byte(body.Len())})
+	hello.Write(body.Bytes())
+
+	return hello.Bytes()
+}
+
+func putExtension(buf *bytes.Buffer, typ uint16, data []byte) {
+	_ = binary.Write(buf, binary.BigEndian, typ)
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(data)))
+	buf.Write(data)
+}
+
+func putUint16List(buf *bytes.Buffer, items ...uint16) {
+	buf.Write(uint16List(items...))
+}
+
+// uint16List кодирует список как в tls: длина списка в байтах, потом сами значения
+func uint16List(items ...uint16) []byte {
+	buf := bytes.NewBuffer(nil)
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(items)*2))
+	_ = binary.Write(buf, binary.BigEndian, items)
+	return buf.Bytes()
+}
+
+// randomGrease возвращает одно из зарезервированных значений GREASE (RFC 8701)
+func randomGrease() uint16 {
+	b := randomBytes(1)[0]&0xf0 | 0x0a
+	return uint16(b)<<8 | uint16(b)
+}
+
+func randomBytes(size int) []byte {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return buf
+}
+
+func xor(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
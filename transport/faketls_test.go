@@ -0,0 +1,168 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTLSProxy ведет себя как MTProxy в режиме fake-TLS: проверяет hmac в ClientHello, отвечает
+// ServerHello и возвращает поток внутри записей application data
+func fakeTLSProxy(conn io.ReadWriteCloser, key []byte) (io.ReadWriter, error) {
+	header, payload, err := readTLSRecord(conn)
+	if err != nil {
+		return nil, err
+	}
+	hello := append(header, payload...)
+	if len(hello) != tlsClientHelloLen {
+		return nil, errors.Errorf("client hello length is %d", len(hello))
+	}
+
+	clientRandom := make([]byte, tlsRandomLen)
+	copy(clientRandom, hello[tlsRandomOffset:])
+	copy(hello[tlsRandomOffset:tlsRandomOffset+tlsRandomLen], make([]byte, tlsRandomLen))
+	mac := hmac.New(sha256.New, key)
+	mac.Write(hello)
+	digest := mac.Sum(nil)
+	if !bytes.Equal(digest[:tlsRandomLen-4], clientRandom[:tlsRandomLen-4]) {
+		return nil, errors.New("client hello digest mismatched")
+	}
+	xor(digest[tlsRandomLen-4:], clientRandom[tlsRandomLen-4:])
+	timestamp := int64(binary.LittleEndian.Uint32(digest[tlsRandomLen-4:]))
+	if d := time.Now().Unix() - timestamp; d > 120 || d < -120 {
+		return nil, errors.New("client hello timestamp is too old")
+	}
+	sessionID := hello[tlsRandomOffset+tlsRandomLen+1 : tlsRandomOffset+tlsRandomLen+1+32]
+
+	serverHello := bytes.NewBuffer(nil)
+	serverHello.Write([]byte{0x02, 0x00, 0x00, 0x00}) // server_hello, длину запишем потом
+	serverHello.Write(tlsVersion)
+	serverHello.Write(make([]byte, tlsRandomLen))
+	serverHello.WriteByte(0x20)
+	serverHello.Write(sessionID)
+	serverHello.Write([]byte{0x13, 0x01, 0x00})
+	ext := bytes.NewBuffer(nil)
+	putExtension(ext, 0x0033, append([]byte{0x00, 0x1d, 0x00, 0x20}, randomBytes(32)...))
+	putExtension(ext, 0x002b, []byte{0x03, 0x04})
+	_ = binary.Write(serverHello, binary.BigEndian, uint16(ext.Len()))
+	serverHello.Write(ext.Bytes())
+	serverHello.Bytes()[3] = byte(serverHello.Len() - 4)
+
+	response := bytes.NewBuffer(nil)
+	writeTLSRecordHeader(response, tlsRecordHandshake, serverHello.Len())
+	response.Write(serverHello.Bytes())
+	response.Write(tlsChangeCipherSpec)
+	writeTLSRecordHeader(response, tlsRecordApplicationData, 100)
+	response.Write(randomBytes(100))
+
+	data := response.Bytes()
+	mac = hmac.New(sha256.New, key)
+	mac.Write(clientRandom)
+	mac.Write(data)
+	copy(data[tlsRandomOffset:], mac.Sum(nil))
+	if _, err := conn.Write(data); err != nil {
+		return nil, err
+	}
+
+	return &fakeTLSConn{conn: conn, sentChangeCipherSpec: true}, nil
+}
+
+func TestFakeTLS(t *testing.T) {
+	link := "tg://proxy?server=127.0.0.1&port=443&secret=ee00112233445566778899aabbccddeeff" +
+		"676f6f676c652e636f6d"
+	addr, secret, err := ParseProxyLink(link)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "127.0.0.1:443", addr)
+	assert.Equal(t, "google.com", secret.Domain)
+
+	data := Hexed("0000000000000000" + "0100000000000000" + "04000000" + "78974660")
+
+	client, server := net.Pipe()
+	defer client.Close()
+	proxyErr := make(chan error, 1)
+	go func() {
+		defer server.Close()
+		proxyErr <- func() error {
+			stream, err := fakeTLSProxy(server, secret.Key)
+			if err != nil {
+				return err
+			}
+
+			header := make([]byte, obfuscatedHeaderLen)
+			if _, err := io.ReadFull(stream, header); err != nil {
+				return err
+			}
+			encryptor, decryptor, err := obfuscatedStreams(header, secret.Key, true)
+			if err != nil {
+				return err
+			}
+			decryptor.XORKeyStream(header, header)
+			if !bytes.Equal(header[obfuscatedProtocolIDStart:obfuscatedDCStart], Hexed("dddddddd")) {
+				return errors.New("wrong protocol id")
+			}
+
+			// эхо-сервер
+			codec := NewPaddedIntermediate().NewCodec()
+			packet, err := codec.ReadPacket(cipher.StreamReader{S: decryptor, R: stream})
+			if err != nil {
+				return err
+			}
+			return codec.WritePacket(cipher.StreamWriter{S: encryptor, W: stream}, packet)
+		}()
+	}()
+
+	tr, err := NewObfuscated2(nil, 2, secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+	conn, err := NewConn(client, tr)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, conn.WritePacket(data))
+	got, err := conn.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, data, got)
+	assert.NoError(t, <-proxyErr)
+}
+
+func TestFakeTLSWrongSecret(t *testing.T) {
+	_, secret, err := ParseProxyLink("https://t.me/proxy?server=localhost&port=443" +
+		"&secret=ee00112233445566778899aabbccddeeff676f6f676c652e636f6d")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		_, _ = fakeTLSProxy(server, Hexed("ffeeddccbbaa99887766554433221100"))
+	}()
+
+	tr, err := NewObfuscated2(nil, 2, secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = NewConn(client, tr)
+	assert.Error(t, err)
+}
+
+func TestClientHello(t *testing.T) {
+	hello := clientHello("example.com")
+	assert.Len(t, hello, tlsClientHelloLen)
+	assert.Equal(t, Hexed("160301"), hello[:3])
+	assert.Equal(t, tlsClientHelloLen-tlsRecordHeaderLen, int(binary.BigEndian.Uint16(hello[3:5])))
+	assert.True(t, bytes.Contains(hello, []byte("example.com")))
+}
@@ -0,0 +1,90 @@
+package transport
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// длина + номер пакета + crc32
+	fullOverhead = wordLen * 3
+)
+
+// https://core.telegram.org/mtproto/mtproto-transports#full
+type full struct{}
+
+// NewFull возвращает режим, в котором к каждому пакету добавляется его номер и crc32. тега у
+// режима нет, сервер узнает его по первому пакету.
+func NewFull() Transport {
+	return full{}
+}
+
+func (full) Tag() []byte {
+	return nil
+}
+
+func (full) NewCodec() Codec {
+	return &fullCodec{}
+}
+
+type fullCodec struct {
+	// номера пакетов считаются отдельно в каждую сторону и начинаются с 0
+	writeSeqNo int32
+	readSeqNo  int32
+}
+
+func (c *fullCodec) WritePacket(w io.Writer, data []byte) error {
+	buf := make([]byte, wordLen*2, len(data)+fullOverhead)
+	binary.LittleEndian.PutUint32(buf, uint32(len(data)+fullOverhead))
+	binary.LittleEndian.PutUint32(buf[wordLen:], uint32(c.writeSeqNo))
+	buf = append(buf, data...)
+
+	checksum := make([]byte, wordLen)
+	binary.LittleEndian.PutUint32(checksum, crc32.ChecksumIEEE(buf))
+	buf = append(buf, checksum...)
+
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+
+	c.writeSeqNo++
+	return nil
+}
+
+func (c *fullCodec) ReadPacket(r io.Reader) ([]byte, error) {
+	size := make([]byte, wordLen)
+	if _, err := io.ReadFull(r, size); err != nil {
+		return nil, errors.Wrap(err, "reading length")
+	}
+
+	n := binary.LittleEndian.Uint32(size)
+	if n > maxPacketLen {
+		return nil, errors.Wrapf(ErrPacketTooBig, "%d bytes", n)
+	}
+	total := int(n)
+	if total < fullOverhead {
+		return nil, errors.Errorf("packet length is too small: %d", total)
+	}
+
+	buf := make([]byte, total)
+	copy(buf, size)
+	if _, err := io.ReadFull(r, buf[wordLen:]); err != nil {
+		return nil, errors.Wrap(err, "reading packet")
+	}
+
+	body := buf[:total-wordLen]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(buf[total-wordLen:]) {
+		return nil, ErrChecksumMismatched
+	}
+
+	seqNo := int32(binary.LittleEndian.Uint32(buf[wordLen : wordLen*2]))
+	if seqNo != c.readSeqNo {
+		return nil, &ErrSeqNoMismatched{Expected: c.readSeqNo, Got: seqNo}
+	}
+	c.readSeqNo++
+
+	return body[wordLen*2:], nil
+}
@@ -0,0 +1,184 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// http транспорт: каждый пакет отправляется отдельным POST запросом на /api, а ответ сервера
+// приходит в теле ответа. без запроса сервер ничего прислать не может, поэтому клиент должен
+// держать открытым хотя бы один запрос с http_wait внутри (см. Conn.Idle)
+// https://core.telegram.org/mtproto/transports#http
+
+const (
+	// сколько ответов можем держать, пока их никто не читает
+	httpResponsesBuffer = 16
+)
+
+type httpTransport struct {
+	url    func(addr string) string
+	client *http.Client
+}
+
+// NewHTTP возвращает транспорт, который отправляет пакеты POST запросами. url строит адрес по
+// адресу датацентра, по умолчанию http://host:80/api. если client nil, то запросы отправляются
+// через Dialer, переданный в Dial.
+func NewHTTP(url func(addr string) string, client *http.Client) Transport {
+	if url == nil {
+		url = defaultHTTPURL
+	}
+	return &httpTransport{url: url, client: client}
+}
+
+func defaultHTTPURL(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return "http://" + net.JoinHostPort(host, "80") + "/api"
+}
+
+// у http нет ни тега, ни кодека, пакеты уходят как есть
+func (*httpTransport) Tag() []byte {
+	return nil
+}
+
+func (*httpTransport) NewCodec() Codec {
+	return &brokenCodec{err: errors.New("http transport can't be used over stream connection")}
+}
+
+func (t *httpTransport) dial(ctx context.Context, d Dialer, addr string) (Conn, error) {
+	client := t.client
+	if client == nil {
+		client = &http.Client{Transport: &http.Transport{DialContext: d.DialContext}}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c := &httpConn{
+		url:       t.url(addr),
+		client:    client,
+		ctx:       ctx,
+		cancel:    cancel,
+		responses: make(chan httpResponse, httpResponsesBuffer),
+		idle:      make(chan struct{}, 1),
+	}
+	c.signalIdle()
+
+	return c, nil
+}
+
+type httpResponse struct {
+	data []byte
+	err  error
+}
+
+type httpConn struct {
+	url    string
+	client *http.Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	responses chan httpResponse
+	idle      chan struct{}
+
+	// сколько запросов сейчас ждут ответа сервера
+	pending      int
+	pendingMutex sync.Mutex
+}
+
+// WritePacket отправляет пакет, но не ждет ответа: сервер может держать запрос открытым долго,
+// а ответ попадет в ReadPacket
+func (c *httpConn) WritePacket(data []byte) error {
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req = req.WithContext(c.ctx)
+
+	c.pendingMutex.Lock()
+	c.pending++
+	c.pendingMutex.Unlock()
+
+	go func() {
+		data, err := c.do(req)
+		if err == nil && len(data) == 0 {
+			// http_wait истек, а сказать серверу нечего
+			c.finish()
+			return
+		}
+
+		select {
+		case c.responses <- httpResponse{data: data, err: err}:
+		case <-c.ctx.Done():
+		}
+		c.finish()
+	}()
+
+	return nil
+}
+
+func (c *httpConn) do(req *http.Request) ([]byte, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response")
+	}
+
+	// код ошибки mtproto сервер отдает вместе с http ошибкой, его надо отдать как пакет
+	if resp.StatusCode != http.StatusOK && len(data) != wordLen {
+		return nil, errors.New("server responded with '" + resp.Status + "'")
+	}
+
+	return data, nil
+}
+
+func (c *httpConn) finish() {
+	c.pendingMutex.Lock()
+	c.pending--
+	idle := c.pending == 0
+	c.pendingMutex.Unlock()
+
+	if idle {
+		c.signalIdle()
+	}
+}
+
+func (c *httpConn) signalIdle() {
+	select {
+	case c.idle <- struct{}{}:
+	default:
+	}
+}
+
+func (c *httpConn) ReadPacket() ([]byte, error) {
+	select {
+	case resp := <-c.responses:
+		return resp.data, resp.err
+	case <-c.ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// Idle срабатывает, когда у сервера не осталось ни одного открытого запроса, через который он
+// может прислать сообщение. в этот момент клиент должен отправить http_wait
+func (c *httpConn) Idle() <-chan struct{} {
+	return c.idle
+}
+
+func (c *httpConn) Close() error {
+	c.cancel()
+	return nil
+}
@@ -0,0 +1,80 @@
+package transport
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTP(t *testing.T) {
+	// сервер отвечает на запрос тем же пакетом, а пустой запрос держит, пока его не отпустят
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		switch string(body) {
+		case "wait":
+			<-release
+		case "fail":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write(Hexed("6cfeffff"))
+		default:
+			_, _ = w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	tr := NewHTTP(func(string) string { return server.URL + "/api" }, nil)
+	conn, err := Dial(context.Background(), nil, tr, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	idle := conn.(interface{ Idle() <-chan struct{} }).Idle()
+
+	// сразу после подключения запросов нет
+	assertSignaled(t, idle)
+
+	data := Hexed("0102030405060708")
+	assert.NoError(t, conn.WritePacket(data))
+	got, err := conn.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, data, got)
+	assertSignaled(t, idle)
+
+	// пока сервер держит запрос, соединение не простаивает
+	assert.NoError(t, conn.WritePacket([]byte("wait")))
+	select {
+	case <-idle:
+		t.Fatal("connection is idle while request is pending")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	assertSignaled(t, idle)
+
+	assert.NoError(t, conn.WritePacket([]byte("fail")))
+	got, err = conn.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, Hexed("6cfeffff"), got)
+}
+
+func TestDefaultHTTPURL(t *testing.T) {
+	assert.Equal(t, "http://149.154.167.50:80/api", defaultHTTPURL("149.154.167.50:443"))
+}
+
+func assertSignaled(t *testing.T, c <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Fatal("channel wasn't signaled")
+	}
+}
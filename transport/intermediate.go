@@ -0,0 +1,139 @@
+package transport
+
+import (
+	"encoding/binary"
+	"io"
+	"math/rand"
+
+	"github.com/pkg/errors"
+	"github.com/xelaj/go-dry"
+)
+
+const (
+	// авторизационный ключ + msg_key у зашифрованных сообщений
+	encryptedHeaderLen = 8 + 16
+	// auth_key_id + message_id + message_data_length у открытых сообщений
+	unencryptedHeaderLen = 8 + 8 + 4
+	aesBlockLen          = 16
+)
+
+// https://core.telegram.org/mtproto/mtproto-transports#intermediate
+type intermediate struct{}
+
+// NewIntermediate возвращает режим, в котором перед каждым пакетом пишется длина 4 байтами.
+// режим по умолчанию.
+func NewIntermediate() Transport {
+	return intermediate{}
+}
+
+func (intermediate) Tag() []byte {
+	return []byte{0xee, 0xee, 0xee, 0xee}
+}
+
+func (intermediate) NewCodec() Codec {
+	return intermediateCodec{}
+}
+
+func (intermediate) ProtocolID() [4]byte {
+	return [4]byte{0xee, 0xee, 0xee, 0xee}
+}
+
+type intermediateCodec struct{}
+
+func (intermediateCodec) WritePacket(w io.Writer, data []byte) error {
+	return writeWithLen(w, data)
+}
+
+func (intermediateCodec) ReadPacket(r io.Reader) ([]byte, error) {
+	return readWithLen(r)
+}
+
+// https://core.telegram.org/mtproto/mtproto-transports#padded-intermediate
+type paddedIntermediate struct{}
+
+// NewPaddedIntermediate это intermediate, но к каждому пакету добавляется от 0 до 15 случайных
+// байт, что бы размер пакетов нельзя было угадать
+func NewPaddedIntermediate() Transport {
+	return paddedIntermediate{}
+}
+
+func (paddedIntermediate) Tag() []byte {
+	return []byte{0xdd, 0xdd, 0xdd, 0xdd}
+}
+
+func (paddedIntermediate) NewCodec() Codec {
+	return paddedIntermediateCodec{}
+}
+
+func (paddedIntermediate) ProtocolID() [4]byte {
+	return [4]byte{0xdd, 0xdd, 0xdd, 0xdd}
+}
+
+type paddedIntermediateCodec struct{}
+
+func (paddedIntermediateCodec) WritePacket(w io.Writer, data []byte) error {
+	padding := dry.RandomBytes(rand.Intn(aesBlockLen)) //nolint: gosec паддингу криптостойкость не нужна
+	return writeWithLen(w, append(data[:len(data):len(data)], padding...))
+}
+
+func (paddedIntermediateCodec) ReadPacket(r io.Reader) ([]byte, error) {
+	data, err := readWithLen(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimPadding(data), nil
+}
+
+// trimPadding отрезает случайные байты, которые сервер добавил к пакету. сам транспорт не
+// знает, сколько байт добавлено, поэтому смотрим на сам пакет: у открытых сообщений длина
+// записана в заголовке, а зашифрованные всегда кратны блоку aes.
+func trimPadding(data []byte) []byte {
+	if len(data) <= wordLen {
+		// код ошибки, паддинга у него нет
+		return data
+	}
+	if len(data) < encryptedHeaderLen {
+		return data
+	}
+
+	if binary.LittleEndian.Uint64(data[:8]) == 0 {
+		if len(data) < unencryptedHeaderLen {
+			return data
+		}
+		size := unencryptedHeaderLen + int(binary.LittleEndian.Uint32(data[16:unencryptedHeaderLen]))
+		if size > len(data) {
+			return data
+		}
+		return data[:size]
+	}
+
+	body := len(data) - encryptedHeaderLen
+	return data[:encryptedHeaderLen+body-body%aesBlockLen]
+}
+
+func writeWithLen(w io.Writer, data []byte) error {
+	buf := make([]byte, wordLen, wordLen+len(data))
+	binary.LittleEndian.PutUint32(buf, uint32(len(data)))
+	_, err := w.Write(append(buf, data...))
+	return err
+}
+
+func readWithLen(r io.Reader) ([]byte, error) {
+	size := make([]byte, wordLen)
+	if _, err := io.ReadFull(r, size); err != nil {
+		return nil, errors.Wrap(err, "reading length")
+	}
+
+	n := binary.LittleEndian.Uint32(size)
+	if n > maxPacketLen {
+		return nil, errors.Wrapf(ErrPacketTooBig, "%d bytes", n)
+	}
+
+	data := make([]byte, int(n))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errors.Wrap(err, "reading packet")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,287 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	obfuscatedHeaderLen = 64
+
+	// куски заголовка obfuscated2
+	obfuscatedKeyStart        = 8
+	obfuscatedKeyEnd          = 40
+	obfuscatedIVEnd           = 56
+	obfuscatedProtocolIDStart = 56
+	obfuscatedDCStart         = 60
+)
+
+// obfuscatable это транспорты, у которых есть 4 байтный тег для заголовка obfuscated2. full
+// так обернуть нельзя.
+type obfuscatable interface {
+	Transport
+	ProtocolID() [4]byte
+}
+
+// https://core.telegram.org/mtproto/mtproto-transports#transport-obfuscation
+type obfuscated2 struct {
+	inner  obfuscatable
+	dc     int16
+	secret *ProxySecret
+}
+
+// NewObfuscated2 оборачивает транспорт в obfuscated2: перед первым пакетом отправляется случайный
+// 64 байтный заголовок, а весь поток шифруется AES-256-CTR в обе стороны, так что DPI не видит в
+// трафике ничего похожего на mtproto. secret нужен только при подключении к MTProxy, dc это номер
+// датацентра, в который прокси должен отправить соединение.
+func NewObfuscated2(inner Transport, dc int, secret *ProxySecret) (Transport, error) {
+	if secret != nil && secret.Padded {
+		// dd секрет значит, что прокси принимает только padded intermediate
+		inner = NewPaddedIntermediate()
+	}
+	if inner == nil {
+		inner = NewIntermediate()
+	}
+
+	o, ok := inner.(obfuscatable)
+	if !ok {
+		return nil, ErrNotObfuscatable
+	}
+
+	return &obfuscated2{
+		inner:  o,
+		dc:     int16(dc),
+		secret: secret,
+	}, nil
+}
+
+// тега у obfuscated2 нет, заголовок случайный для каждого соединения и его отправляет кодек
+func (*obfuscated2) Tag() []byte {
+	return nil
+}
+
+func (t *obfuscated2) NewCodec() Codec {
+	header, encryptor, decryptor, err := t.handshake()
+	if err != nil {
+		return &brokenCodec{err: err}
+	}
+
+	return &obfuscated2Codec{
+		inner:     t.inner.NewCodec(),
+		header:    header,
+		encryptor: encryptor,
+		decryptor: decryptor,
+	}
+}
+
+// wrapConn нужен для fake-TLS: до заголовка obfuscated2 надо пройти поддельное tls рукопожатие
+func (t *obfuscated2) wrapConn(conn io.ReadWriteCloser) (io.ReadWriteCloser, error) {
+	if t.secret == nil || t.secret.Domain == "" {
+		return conn, nil
+	}
+	return fakeTLSHandshake(conn, t.secret)
+}
+
+func (t *obfuscated2) withDC(dc int) Transport {
+	c := *t
+	c.dc = int16(dc)
+	return &c
+}
+
+func (t *obfuscated2) targetDC() int {
+	return int(t.dc)
+}
+
+func (t *obfuscated2) viaProxy() bool {
+	return t.secret != nil
+}
+
+// handshake генерирует заголовок и ключи шифрования для нового соединения
+func (t *obfuscated2) handshake() (header []byte, encryptor, decryptor cipher.Stream, err error) {
+	plain := randomObfuscatedHeader()
+	protocolID := t.inner.ProtocolID()
+	copy(plain[obfuscatedProtocolIDStart:], protocolID[:])
+	binary.LittleEndian.PutUint16(plain[obfuscatedDCStart:], uint16(t.dc))
+
+	var secret []byte
+	if t.secret != nil {
+		secret = t.secret.Key
+	}
+
+	encryptor, decryptor, err = obfuscatedStreams(plain, secret, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// шифруем заголовок целиком, что бы шифр сдвинулся на 64 байта, но отправляем открытым все,
+	// кроме последних 8 байт (тег и номер датацентра)
+	encrypted := make([]byte, obfuscatedHeaderLen)
+	encryptor.XORKeyStream(encrypted, plain)
+	copy(plain[obfuscatedProtocolIDStart:], encrypted[obfuscatedProtocolIDStart:])
+
+	return plain, encryptor, decryptor, nil
+}
+
+// obfuscatedStreams создает шифры для обоих направлений из заголовка. ключи для чтения это
+// перевернутые ключи для записи. если server true, то направления меняются местами (нужно
+// только для тестов и фейковых серверов)
+func obfuscatedStreams(header, secret []byte, server bool) (encryptor, decryptor cipher.Stream, err error) {
+	keyIV := header[obfuscatedKeyStart:obfuscatedIVEnd]
+	reversed := make([]byte, len(keyIV))
+	for i := range keyIV {
+		reversed[len(keyIV)-1-i] = keyIV[i]
+	}
+
+	const keyLen = obfuscatedKeyEnd - obfuscatedKeyStart
+	encryptor, err = newCTR(keyIV[:keyLen], keyIV[keyLen:], secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	decryptor, err = newCTR(reversed[:keyLen], reversed[keyLen:], secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if server {
+		encryptor, decryptor = decryptor, encryptor
+	}
+	return encryptor, decryptor, nil
+}
+
+func newCTR(key, iv, secret []byte) (cipher.Stream, error) {
+	if secret != nil {
+		sum := sha256.Sum256(append(key[:len(key):len(key)], secret...))
+		key = sum[:]
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating aes cipher")
+	}
+
+	return cipher.NewCTR(block, iv), nil
+}
+
+// randomObfuscatedHeader генерирует заголовок, который нельзя спутать с другими протоколами:
+// он не должен начинаться как abridged, http запрос, tls или теги других транспортов
+func randomObfuscatedHeader() []byte {
+	forbidden := [][]byte{
+		[]byte("HEAD"),
+		[]byte("POST"),
+		[]byte("GET "),
+		[]byte("OPTI"),
+		{0x16, 0x03, 0x01, 0x02},
+		{0xdd, 0xdd, 0xdd, 0xdd},
+		{0xee, 0xee, 0xee, 0xee},
+	}
+
+	header := make([]byte, obfuscatedHeaderLen)
+	for {
+		if _, err := rand.Read(header); err != nil {
+			panic(err)
+		}
+
+		if header[0] == 0xef {
+			continue
+		}
+		if binary.LittleEndian.Uint32(header[4:8]) == 0 {
+			continue
+		}
+
+		ok := true
+		for _, f := range forbidden {
+			if bytes.Equal(header[:4], f) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return header
+		}
+	}
+}
+
+type obfuscated2Codec struct {
+	inner     Codec
+	header    []byte
+	encryptor cipher.Stream
+	decryptor cipher.Stream
+}
+
+func (c *obfuscated2Codec) WritePacket(w io.Writer, data []byte) error {
+	buf := bytes.NewBuffer(nil)
+	if err := c.inner.WritePacket(buf, data); err != nil {
+		return err
+	}
+
+	payload := buf.Bytes()
+	c.encryptor.XORKeyStream(payload, payload)
+
+	// заголовок отправляется вместе с первым пакетом
+	if c.header != nil {
+		payload = append(c.header, payload...)
+	}
+
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+
+	c.header = nil
+	return nil
+}
+
+func (c *obfuscated2Codec) ReadPacket(r io.Reader) ([]byte, error) {
+	return c.inner.ReadPacket(cipher.StreamReader{S: c.decryptor, R: r})
+}
+
+// brokenCodec отдается, если кодек не получилось создать. NewCodec не возвращает ошибок, поэтому
+// ошибка всплывет при первой же попытке что-то отправить
+type brokenCodec struct {
+	err error
+}
+
+func (c *brokenCodec) WritePacket(io.Writer, []byte) error {
+	return c.err
+}
+
+func (c *brokenCodec) ReadPacket(io.Reader) ([]byte, error) {
+	return nil, c.err
+}
+
+// WithDC возвращает копию транспорта, которая будет подключаться к датацентру dc. это важно для
+// MTProxy: прокси сам решает, куда отправить соединение, и смотрит только на номер в заголовке.
+// транспорты, которым номер датацентра не нужен, возвращаются как есть.
+func WithDC(t Transport, dc int) Transport {
+	if d, ok := t.(interface{ withDC(int) Transport }); ok {
+		return d.withDC(dc)
+	}
+	return t
+}
+
+// ProxyDC возвращает номер датацентра, в который MTProxy отправляет соединение, или 0, если
+// транспорт подключается не через прокси
+func ProxyDC(t Transport) int {
+	if !ViaProxy(t) {
+		return 0
+	}
+	if d, ok := t.(interface{ targetDC() int }); ok {
+		return d.targetDC()
+	}
+	return 0
+}
+
+// ViaProxy показывает, что транспорт подключается через MTProxy. в этом случае при миграции
+// между датацентрами меняется не адрес, а только номер датацентра (см. WithDC)
+func ViaProxy(t Transport) bool {
+	if p, ok := t.(interface{ viaProxy() bool }); ok {
+		return p.viaProxy()
+	}
+	return false
+}
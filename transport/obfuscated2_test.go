@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeObfuscatedServer разбирает заголовок obfuscated2 так же, как это делает сервер
+func fakeObfuscatedServer(t *testing.T, stream []byte, secret []byte) (protocolID []byte, dc int16, rest []byte, encrypt func([]byte) []byte) {
+	t.Helper()
+	if !assert.True(t, len(stream) >= obfuscatedHeaderLen) {
+		t.FailNow()
+	}
+
+	header := stream[:obfuscatedHeaderLen]
+	encryptor, decryptor, err := obfuscatedStreams(header, secret, true)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	decrypted := make([]byte, len(stream))
+	decryptor.XORKeyStream(decrypted, stream)
+
+	encrypt = func(in []byte) []byte {
+		out := make([]byte, len(in))
+		encryptor.XORKeyStream(out, in)
+		return out
+	}
+
+	return decrypted[obfuscatedProtocolIDStart:obfuscatedDCStart],
+		int16(binary.LittleEndian.Uint16(decrypted[obfuscatedDCStart:])),
+		decrypted[obfuscatedHeaderLen:],
+		encrypt
+}
+
+func TestObfuscated2(t *testing.T) {
+	secret, err := ParseProxySecret("dd00112233445566778899aabbccddeeff")
+	assert.NoError(t, err)
+
+	for _, tcase := range []struct {
+		name       string
+		inner      Transport
+		secret     *ProxySecret
+		dc         int
+		protocolID []byte
+		framed     []byte
+	}{
+		{
+			name:       "intermediate",
+			inner:      NewIntermediate(),
+			dc:         2,
+			protocolID: Hexed("eeeeeeee"),
+			framed:     Hexed("08000000" + "0102030405060708"),
+		},
+		{
+			name:       "abridged",
+			inner:      NewAbridged(),
+			dc:         -4,
+			protocolID: Hexed("efefefef"),
+			framed:     Hexed("02" + "0102030405060708"),
+		},
+		{
+			name:       "proxy with dd secret",
+			inner:      NewIntermediate(),
+			secret:     secret,
+			dc:         5,
+			protocolID: Hexed("dddddddd"),
+		},
+	} {
+		t.Run(tcase.name, func(t *testing.T) {
+			tr, err := NewObfuscated2(tcase.inner, tcase.dc, tcase.secret)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Nil(t, tr.Tag())
+
+			data := Hexed("0102030405060708")
+			codec := tr.NewCodec()
+			buf := bytes.NewBuffer(nil)
+			assert.NoError(t, codec.WritePacket(buf, data))
+
+			var key []byte
+			if tcase.secret != nil {
+				key = tcase.secret.Key
+			}
+			protocolID, dc, rest, encrypt := fakeObfuscatedServer(t, buf.Bytes(), key)
+			assert.Equal(t, tcase.protocolID, protocolID)
+			assert.Equal(t, int16(tcase.dc), dc)
+			if tcase.framed != nil {
+				assert.Equal(t, tcase.framed, rest)
+			} else {
+				assert.Equal(t, data, rest[4:4+len(data)])
+			}
+
+			// ответ сервера
+			response := bytes.NewBuffer(nil)
+			assert.NoError(t, tcase.inner.NewCodec().WritePacket(response, data))
+			got, err := codec.ReadPacket(bytes.NewBuffer(encrypt(response.Bytes())))
+			assert.NoError(t, err)
+			assert.Equal(t, data, got)
+		})
+	}
+
+	_, err = NewObfuscated2(NewFull(), 2, nil)
+	assert.Equal(t, ErrNotObfuscatable, err)
+}
+
+func TestRandomObfuscatedHeader(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		header := randomObfuscatedHeader()
+		assert.Len(t, header, obfuscatedHeaderLen)
+		assert.NotEqual(t, byte(0xef), header[0])
+		assert.NotEqual(t, Hexed("eeeeeeee"), header[:4])
+		assert.NotEqual(t, Hexed("00000000"), header[4:8])
+	}
+}
+
+func TestParseProxySecret(t *testing.T) {
+	for _, tcase := range []struct {
+		secret  string
+		want    *ProxySecret
+		wantErr bool
+	}{
+		{
+			secret: "00112233445566778899aabbccddeeff",
+			want:   &ProxySecret{Key: Hexed("00112233445566778899aabbccddeeff")},
+		},
+		{
+			secret: "dd00112233445566778899aabbccddeeff",
+			want:   &ProxySecret{Key: Hexed("00112233445566778899aabbccddeeff"), Padded: true},
+		},
+		{
+			secret: "3QARIjNEVWZ3iJmqu8zd7v8",
+			want:   &ProxySecret{Key: Hexed("00112233445566778899aabbccddeeff"), Padded: true},
+		},
+		{
+			secret:  "0011",
+			wantErr: true,
+		},
+	} {
+		t.Run(tcase.secret, func(t *testing.T) {
+			got, err := ParseProxySecret(tcase.secret)
+			if tcase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tcase.want, got)
+		})
+	}
+}
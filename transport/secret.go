@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	proxySecretLen = 16
+
+	secretPrefixPadded  = 0xdd
+	secretPrefixFakeTLS = 0xee
+)
+
+// ProxySecret это секрет MTProxy. секрет может быть просто 16 байтами, а может иметь префикс,
+// который говорит, в каком режиме работает прокси.
+type ProxySecret struct {
+	Key []byte
+	// Padded значит, что у секрета префикс dd или ee, и прокси принимает только padded intermediate
+	Padded bool
+	// Domain не пустой, если у секрета префикс ee: прокси работает в режиме fake-TLS и
+	// притворяется этим доменом
+	Domain string
+}
+
+// ParseProxySecret разбирает секрет в том виде, в котором его отдают прокси: hex строкой, либо
+// base64 (url-safe или обычный)
+func ParseProxySecret(secret string) (*ProxySecret, error) {
+	raw, err := hex.DecodeString(secret)
+	if err != nil {
+		raw, err = decodeBase64(secret)
+		if err != nil {
+			return nil, ErrInvalidSecret
+		}
+	}
+
+	switch {
+	case len(raw) == proxySecretLen:
+		return &ProxySecret{Key: raw}, nil
+	case len(raw) == proxySecretLen+1 && raw[0] == secretPrefixPadded:
+		return &ProxySecret{Key: raw[1:], Padded: true}, nil
+	case len(raw) > proxySecretLen+1 && raw[0] == secretPrefixFakeTLS:
+		return &ProxySecret{Key: raw[1 : proxySecretLen+1], Padded: true, Domain: string(raw[proxySecretLen+1:])}, nil
+	default:
+		return nil, ErrInvalidSecret
+	}
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+// ParseProxyLink разбирает ссылку на MTProxy вида tg://proxy?server=...&port=...&secret=... (или
+// такую же https://t.me/proxy?...) и возвращает адрес прокси и его секрет
+func ParseProxyLink(link string) (addr string, secret *ProxySecret, err error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "parsing link")
+	}
+
+	switch {
+	case u.Scheme == "tg" && u.Host == "proxy":
+	case (u.Scheme == "https" || u.Scheme == "http") && u.Host == "t.me" && u.Path == "/proxy":
+	default:
+		return "", nil, errors.New("'" + link + "': not a proxy link")
+	}
+
+	query := u.Query()
+	server, port := query.Get("server"), query.Get("port")
+	if server == "" || port == "" {
+		return "", nil, errors.New("'" + link + "': server and port are required")
+	}
+
+	secret, err = ParseProxySecret(query.Get("secret"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return net.JoinHostPort(server, port), secret, nil
+}
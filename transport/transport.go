@@ -0,0 +1,125 @@
+// transport отвечает за то, как пакеты mtproto упаковываются в поток байт. сами по себе
+// сообщения mtproto не знают ни о длине, ни о границах пакетов, поэтому перед отправкой
+// каждый пакет оборачивается одним из режимов транспорта
+// https://core.telegram.org/mtproto/mtproto-transports
+
+package transport
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Transport это режим упаковки пакетов. сам по себе режим не хранит состояния, поэтому один и тот
+// же объект можно использовать для сколько угодно соединений, состояние хранит Codec.
+type Transport interface {
+	// Tag это байты, которые клиент отправляет сразу после подключения, что бы сервер понял, какой
+	// транспорт используется. может быть пустым (как у full)
+	Tag() []byte
+	// NewCodec создает кодек для нового соединения
+	NewCodec() Codec
+}
+
+// Codec пишет и читает целые пакеты из потока байт. кодек привязан к одному соединению, т.к.
+// некоторые режимы (например full) считают отправленные и принятые пакеты.
+type Codec interface {
+	WritePacket(w io.Writer, data []byte) error
+	ReadPacket(r io.Reader) ([]byte, error)
+}
+
+// Conn это соединение с сервером, которое принимает и отдает пакеты mtproto целиком
+type Conn interface {
+	WritePacket(data []byte) error
+	ReadPacket() ([]byte, error)
+	Close() error
+}
+
+// Dial подключается к addr через d и готовит соединение для транспорта t. большинство транспортов
+// работают поверх потока байт, но некоторым (http) нужно свое соединение. если d nil, то
+// подключение прямое
+func Dial(ctx context.Context, d Dialer, t Transport, addr string) (Conn, error) {
+	d = defaultDialer(d)
+	if pd, ok := t.(interface {
+		dial(context.Context, Dialer, string) (Conn, error)
+	}); ok {
+		return pd.dial(ctx, d, addr)
+	}
+
+	raw, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "dialing")
+	}
+
+	conn, err := NewConn(raw, t)
+	if err != nil {
+		raw.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// streamConn это Conn поверх обычного потока байт (tcp соединения например)
+type streamConn struct {
+	conn  io.ReadWriteCloser
+	codec Codec
+
+	// пакеты надо писать и читать целиком, иначе байты разных пакетов перемешаются
+	readMutex  sync.Mutex
+	writeMutex sync.Mutex
+}
+
+// NewConn отправляет в conn тег транспорта и возвращает соединение, которое пишет пакеты через
+// кодек этого транспорта
+func NewConn(conn io.ReadWriteCloser, t Transport) (Conn, error) {
+	// некоторым транспортам (fake-TLS) нужно сначала договориться с сервером и
+	// только потом отправлять пакеты
+	if w, ok := t.(interface {
+		wrapConn(io.ReadWriteCloser) (io.ReadWriteCloser, error)
+	}); ok {
+		var err error
+		conn, err = w.wrapConn(conn)
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing connection")
+		}
+	}
+
+	if tag := t.Tag(); len(tag) > 0 {
+		if _, err := conn.Write(tag); err != nil {
+			return nil, errors.Wrap(err, "writing transport tag")
+		}
+	}
+
+	return &streamConn{
+		conn:  conn,
+		codec: t.NewCodec(),
+	}, nil
+}
+
+func (c *streamConn) WritePacket(data []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	return c.codec.WritePacket(c.conn, data)
+}
+
+func (c *streamConn) ReadPacket() ([]byte, error) {
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+	return c.codec.ReadPacket(c.conn)
+}
+
+// SetReadDeadline пробрасывает дедлайн в нижележащее соединение, если оно это умеет
+func (c *streamConn) SetReadDeadline(t time.Time) error {
+	if d, ok := c.conn.(interface{ SetReadDeadline(time.Time) error }); ok {
+		return d.SetReadDeadline(t)
+	}
+	return nil
+}
+
+func (c *streamConn) Close() error {
+	return c.conn.Close()
+}
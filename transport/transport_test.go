@@ -0,0 +1,178 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbridged(t *testing.T) {
+	long := bytes.Repeat([]byte{0xab}, 127*4)
+
+	for _, tcase := range []struct {
+		name    string
+		data    []byte
+		encoded []byte
+	}{
+		{
+			name:    "short",
+			data:    Hexed("0102030405060708"),
+			encoded: Hexed("02" + "0102030405060708"),
+		},
+		{
+			name:    "long",
+			data:    long,
+			encoded: append(Hexed("7f7f0000"), long...),
+		},
+	} {
+		t.Run(tcase.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			codec := NewAbridged().NewCodec()
+			assert.NoError(t, codec.WritePacket(buf, tcase.data))
+			assert.Equal(t, tcase.encoded, buf.Bytes())
+
+			got, err := codec.ReadPacket(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tcase.data, got)
+		})
+	}
+
+	err := NewAbridged().NewCodec().WritePacket(bytes.NewBuffer(nil), []byte{1, 2, 3})
+	assert.Equal(t, ErrPacketNotAligned, err)
+}
+
+func TestIntermediate(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	codec := NewIntermediate().NewCodec()
+	data := Hexed("0102030405060708")
+
+	assert.NoError(t, codec.WritePacket(buf, data))
+	assert.Equal(t, Hexed("08000000"+"0102030405060708"), buf.Bytes())
+
+	got, err := codec.ReadPacket(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestPaddedIntermediate(t *testing.T) {
+	for _, tcase := range []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "unencrypted",
+			data: Hexed("0000000000000000" + "0100000000000000" + "04000000" + "78974660"),
+		},
+		{
+			name: "encrypted",
+			data: Hexed("1122334455667788" + strings.Repeat("aa", 16) + strings.Repeat("bb", 32)),
+		},
+		{
+			name: "error code",
+			data: Hexed("6cfeffff"),
+		},
+	} {
+		t.Run(tcase.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			codec := NewPaddedIntermediate().NewCodec()
+			assert.NoError(t, codec.WritePacket(buf, tcase.data))
+
+			written := buf.Bytes()
+			size := int(written[0]) | int(written[1])<<8
+			assert.Equal(t, len(written)-4, size)
+			assert.True(t, size >= len(tcase.data) && size < len(tcase.data)+16)
+			assert.Equal(t, tcase.data, written[4:4+len(tcase.data)])
+
+			if len(tcase.data) == 4 {
+				// паддинг у кода ошибки не отрезать, поэтому проверяем только запись
+				return
+			}
+			got, err := codec.ReadPacket(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tcase.data, got)
+		})
+	}
+}
+
+func TestFull(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	codec := NewFull().NewCodec()
+	data := Hexed("0102030405060708")
+
+	assert.NoError(t, codec.WritePacket(buf, data))
+	assert.Equal(t, Hexed("14000000"+"00000000"+"0102030405060708"+"e06e4584"), buf.Bytes())
+	assert.NoError(t, codec.WritePacket(buf, data))
+
+	reader := NewFull().NewCodec()
+	for i := 0; i < 2; i++ {
+		got, err := reader.ReadPacket(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, data, got)
+	}
+
+	// портим контрольную сумму
+	assert.NoError(t, codec.WritePacket(buf, data))
+	corrupted := buf.Bytes()
+	corrupted[len(corrupted)-1] ^= 0xff
+	_, err := reader.ReadPacket(buf)
+	assert.Equal(t, ErrChecksumMismatched, err)
+
+	// пакет с неожиданным номером
+	buf.Reset()
+	assert.NoError(t, NewFull().NewCodec().WritePacket(buf, data))
+	_, err = reader.ReadPacket(buf)
+	assert.Equal(t, &ErrSeqNoMismatched{Expected: 2, Got: 0}, err)
+}
+
+func TestPacketTooBig(t *testing.T) {
+	// длина пакета берется из сети, огромный буфер под нее выделять нельзя
+	for _, tcase := range []struct {
+		name   string
+		codec  Codec
+		header string
+	}{
+		{name: "abridged", codec: NewAbridged().NewCodec(), header: "7fffffff"},
+		{name: "intermediate", codec: NewIntermediate().NewCodec(), header: "ffffffff"},
+		{name: "full", codec: NewFull().NewCodec(), header: "00005000"},
+	} {
+		_, err := tcase.codec.ReadPacket(bytes.NewReader(Hexed(tcase.header)))
+		assert.Equal(t, ErrPacketTooBig, errors.Cause(err), tcase.name)
+	}
+}
+
+func TestNewConn(t *testing.T) {
+	for _, tcase := range []struct {
+		transport Transport
+		tag       []byte
+	}{
+		{NewAbridged(), Hexed("ef")},
+		{NewIntermediate(), Hexed("eeeeeeee")},
+		{NewPaddedIntermediate(), Hexed("dddddddd")},
+		{NewFull(), nil},
+	} {
+		rw := &nopCloser{Buffer: bytes.NewBuffer(nil)}
+		_, err := NewConn(rw, tcase.transport)
+		assert.NoError(t, err)
+		assert.Equal(t, tcase.tag, rw.Bytes())
+	}
+}
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (*nopCloser) Close() error {
+	return nil
+}
+
+func Hexed(in string) []byte {
+	res, err := hex.DecodeString(in)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
@@ -0,0 +1,308 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1" //nolint: gosec так требует rfc 6455
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// mtproto поверх websocket: в бинарных сообщениях идет тот же поток байт, что и по tcp, только
+// телеграм принимает его исключительно в обфусцированном виде, поэтому транспорт должен быть
+// обернут в obfuscated2
+// https://core.telegram.org/mtproto/transports#websocket
+
+const (
+	webSocketGUID     = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	webSocketProtocol = "binary"
+
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xa
+
+	wsFinalBit = 0x80
+	wsMaskBit  = 0x80
+)
+
+// адреса датацентров из списка по умолчанию и их websocket домены
+var webSocketHosts = map[string]string{
+	"149.154.175.58":  "pluto",
+	"149.154.167.50":  "venus",
+	"149.154.175.100": "aurora",
+	"149.154.167.91":  "vesta",
+	"91.108.56.151":   "flora",
+}
+
+// TelegramWebSocketURL отдает адрес websocket сервера для адреса датацентра, например
+// 149.154.167.50:443 превращается в wss://venus.web.telegram.org/apiws. неизвестные адреса
+// превращаются в wss://addr/apiws
+func TelegramWebSocketURL(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if name, ok := webSocketHosts[host]; ok {
+		return "wss://" + name + ".web.telegram.org/apiws"
+	}
+	return "wss://" + addr + "/apiws"
+}
+
+type webSocketDialer struct {
+	url     func(addr string) string
+	forward Dialer
+}
+
+// NewWebSocketDialer возвращает dialer, который открывает соединение по websocket. url строит
+// адрес сервера по адресу датацентра, если nil, то используется TelegramWebSocketURL. forward
+// нужен, что бы ходить через другой прокси (например HTTP CONNECT), если nil, то подключение
+// прямое. транспорт для такого соединения должен быть obfuscated2, см. ForDialer
+func NewWebSocketDialer(url func(addr string) string, forward Dialer) Dialer {
+	if url == nil {
+		url = TelegramWebSocketURL
+	}
+	return &webSocketDialer{url: url, forward: defaultDialer(forward)}
+}
+
+// ForDialer подгоняет транспорт под dialer. по websocket телеграм принимает только obfuscated2,
+// поэтому для NewWebSocketDialer транспорт, который еще не обфусцирован, оборачивается в него.
+// остальные транспорты и dialer'ы возвращаются как есть
+func ForDialer(t Transport, d Dialer) (Transport, error) {
+	if _, ok := d.(*webSocketDialer); !ok {
+		return t, nil
+	}
+	if _, ok := t.(*obfuscated2); ok {
+		return t, nil
+	}
+	o, err := NewObfuscated2(t, 0, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "websocket requires obfuscated2")
+	}
+	return o, nil
+}
+
+func (d *webSocketDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	u, err := url.Parse(d.url(addr))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing websocket url")
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "wss" {
+			port = "443"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	conn, err := d.forward.DialContext(ctx, network, host)
+	if err != nil {
+		return nil, errors.Wrap(err, "dialing websocket server")
+	}
+
+	if u.Scheme == "wss" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()}) //nolint: gosec версию выбирает go
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "tls handshake")
+		}
+		conn = tlsConn
+	}
+
+	ws, err := webSocketHandshake(conn, u)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "websocket handshake")
+	}
+
+	return ws, nil
+}
+
+func webSocketHandshake(conn net.Conn, u *url.URL) (net.Conn, error) {
+	key := base64.StdEncoding.EncodeToString(randomBytes(16))
+
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Path: u.Path, RawQuery: u.RawQuery},
+		Host:   u.Host,
+		Header: http.Header{
+			"Upgrade":                {"websocket"},
+			"Connection":             {"Upgrade"},
+			"Sec-WebSocket-Key":      {key},
+			"Sec-WebSocket-Version":  {"13"},
+			"Sec-WebSocket-Protocol": {webSocketProtocol},
+		},
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, errors.Wrap(err, "writing request")
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response")
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, errors.New("server responded with '" + resp.Status + "'")
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) {
+		return nil, errors.New("invalid Sec-WebSocket-Accept")
+	}
+
+	return &webSocketConn{Conn: conn, r: reader, client: true}, nil
+}
+
+func webSocketAccept(key string) string {
+	hash := sha1.Sum([]byte(key + webSocketGUID)) //nolint: gosec
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// webSocketConn это поток байт поверх бинарных сообщений websocket. адреса и дедлайны берутся
+// из нижележащего соединения
+type webSocketConn struct {
+	net.Conn
+	r *bufio.Reader
+	// клиент обязан маскировать все свои фреймы, сервер наоборот не должен
+	client bool
+
+	readBuf    []byte
+	writeMutex sync.Mutex
+}
+
+func (c *webSocketConn) Read(p []byte) (int, error) {
+	for len(c.readBuf) == 0 {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, err
+		}
+
+		switch opcode {
+		case wsOpBinary, wsOpText, wsOpContinuation:
+			c.readBuf = payload
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return 0, errors.Wrap(err, "writing pong")
+			}
+		case wsOpPong:
+			// игнорим, мы пинги не отправляем
+		case wsOpClose:
+			_ = c.writeFrame(wsOpClose, nil)
+			return 0, io.EOF
+		default:
+			return 0, errors.Errorf("unknown websocket opcode %#x", opcode)
+		}
+	}
+
+	n := copy(p, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+func (c *webSocketConn) Write(p []byte) (int, error) {
+	if err := c.writeFrame(wsOpBinary, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *webSocketConn) Close() error {
+	_ = c.writeFrame(wsOpClose, nil)
+	return c.Conn.Close()
+}
+
+func (c *webSocketConn) readFrame() (opcode byte, payload []byte, err error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.r, header); err != nil {
+		return 0, nil, err
+	}
+	opcode = header[0] & 0x0f
+
+	size := uint64(header[1] &^ wsMaskBit)
+	switch size {
+	case 126:
+		buf := make([]byte, 2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(buf))
+	case 127:
+		buf := make([]byte, 8)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(buf)
+	}
+	if size > 1<<24 {
+		return 0, nil, errors.Errorf("websocket frame is too big: %d bytes", size)
+	}
+
+	var mask []byte
+	if header[1]&wsMaskBit != 0 {
+		mask = make([]byte, 4)
+		if _, err := io.ReadFull(c.r, mask); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload = make([]byte, int(size))
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, err
+	}
+	if mask != nil {
+		maskBytes(payload, mask)
+	}
+
+	return opcode, payload, nil
+}
+
+func (c *webSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	frame := []byte{wsFinalBit | opcode, 0}
+	switch size := len(payload); {
+	case size < 126:
+		frame[1] = byte(size)
+	case size <= 0xffff:
+		frame[1] = 126
+		frame = append(frame, byte(size>>8), byte(size))
+	default:
+		frame[1] = 127
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, uint64(size))
+		frame = append(frame, buf...)
+	}
+
+	data := payload
+	if c.client {
+		frame[1] |= wsMaskBit
+		mask := randomBytes(4)
+		frame = append(frame, mask...)
+		data = make([]byte, len(payload))
+		copy(data, payload)
+		maskBytes(data, mask)
+	}
+
+	_, err := c.Conn.Write(append(frame, data...))
+	return err
+}
+
+func maskBytes(data, mask []byte) {
+	for i := range data {
+		data[i] ^= mask[i%4]
+	}
+}
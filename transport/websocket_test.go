@@ -0,0 +1,186 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeWebSocketServer принимает websocket соединение и отдает его в handle как поток байт
+func fakeWebSocketServer(t *testing.T, handle func(conn net.Conn) error) (server *httptest.Server, errs chan error) {
+	t.Helper()
+	errs = make(chan error, 1)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apiws" || r.Header.Get("Sec-WebSocket-Protocol") != webSocketProtocol {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer conn.Close()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Sec-WebSocket-Protocol: binary\r\n" +
+			"Sec-WebSocket-Accept: " + webSocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+
+		errs <- handle(&webSocketConn{Conn: conn, r: rw.Reader})
+	}))
+
+	return server, errs
+}
+
+func webSocketURL(server *httptest.Server) func(string) string {
+	return func(string) string {
+		return "ws" + strings.TrimPrefix(server.URL, "http") + "/apiws"
+	}
+}
+
+func TestWebSocketFrames(t *testing.T) {
+	big := bytes.Repeat([]byte{0xab}, 70000)
+	server, errs := fakeWebSocketServer(t, func(conn net.Conn) error {
+		ws := conn.(*webSocketConn)
+		// сервер пингует клиента, клиент должен ответить понгом, а данные прочитать
+		if err := ws.writeFrame(wsOpPing, []byte("ping")); err != nil {
+			return err
+		}
+
+		gotPong := false
+		for _, size := range []int{4, 200, len(big)} {
+			opcode, payload, err := ws.readFrame()
+			if err != nil {
+				return err
+			}
+			if opcode == wsOpPong && string(payload) == "ping" {
+				// понг может прийти между любыми фреймами
+				gotPong = true
+				opcode, payload, err = ws.readFrame()
+				if err != nil {
+					return err
+				}
+			}
+			if opcode != wsOpBinary || len(payload) != size {
+				return errors.Errorf("unexpected frame %#x with %d bytes", opcode, len(payload))
+			}
+			if err := ws.writeFrame(wsOpBinary, payload); err != nil {
+				return err
+			}
+		}
+		if !gotPong {
+			opcode, payload, err := ws.readFrame()
+			if err != nil {
+				return err
+			}
+			if opcode != wsOpPong || string(payload) != "ping" {
+				return errors.New("expected pong")
+			}
+		}
+		return ws.writeFrame(wsOpClose, nil)
+	})
+	defer server.Close()
+
+	conn, err := NewWebSocketDialer(webSocketURL(server), nil).DialContext(context.Background(), "tcp", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	// пинг прилетает до первых данных, поэтому запускаем чтение заранее
+	read := make(chan []byte)
+	go func() {
+		data, _ := ioutil.ReadAll(conn)
+		read <- data
+	}()
+
+	want := make([]byte, 0)
+	for _, data := range [][]byte{Hexed("01020304"), bytes.Repeat([]byte{0x01}, 200), big} {
+		_, err := conn.Write(data)
+		assert.NoError(t, err)
+		want = append(want, data...)
+	}
+
+	assert.Equal(t, want, <-read)
+	assert.NoError(t, <-errs)
+}
+
+func TestWebSocketObfuscated(t *testing.T) {
+	data := Hexed("0000000000000000" + "0100000000000000" + "04000000" + "78974660")
+
+	server, errs := fakeWebSocketServer(t, func(conn net.Conn) error {
+		header := make([]byte, obfuscatedHeaderLen)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return err
+		}
+		encryptor, decryptor, err := obfuscatedStreams(header, nil, true)
+		if err != nil {
+			return err
+		}
+		decryptor.XORKeyStream(header, header)
+
+		codec := NewIntermediate().NewCodec()
+		packet, err := codec.ReadPacket(cipher.StreamReader{S: decryptor, R: conn})
+		if err != nil {
+			return err
+		}
+		return codec.WritePacket(cipher.StreamWriter{S: encryptor, W: conn}, packet)
+	})
+	defer server.Close()
+
+	// транспорт по умолчанию сам оборачивается в obfuscated2
+	dialer := NewWebSocketDialer(webSocketURL(server), nil)
+	tr, err := ForDialer(NewIntermediate(), dialer)
+	if !assert.NoError(t, err) {
+		return
+	}
+	conn, err := Dial(context.Background(), dialer, tr, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	assert.NoError(t, conn.WritePacket(data))
+	got, err := conn.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, data, got)
+	assert.NoError(t, <-errs)
+}
+
+func TestForDialer(t *testing.T) {
+	dialer := NewWebSocketDialer(nil, nil)
+
+	obfuscated, err := NewObfuscated2(NewAbridged(), 2, nil)
+	assert.NoError(t, err)
+	got, err := ForDialer(obfuscated, dialer)
+	assert.NoError(t, err)
+	assert.Equal(t, obfuscated, got)
+
+	_, err = ForDialer(NewFull(), dialer)
+	assert.Equal(t, ErrNotObfuscatable, errors.Cause(err))
+
+	// без websocket транспорт не меняется
+	intermediate := NewIntermediate()
+	got, err = ForDialer(intermediate, &net.Dialer{})
+	assert.NoError(t, err)
+	assert.Equal(t, intermediate, got)
+}
+
+func TestTelegramWebSocketURL(t *testing.T) {
+	assert.Equal(t, "wss://venus.web.telegram.org/apiws", TelegramWebSocketURL("149.154.167.50:443"))
+	assert.Equal(t, "wss://example.com:8443/apiws", TelegramWebSocketURL("example.com:8443"))
+}
@@ -15,6 +15,12 @@ var defaultDCList = map[int]string{
 	5: "91.108.56.151:443",
 }
 
+// DefaultDCAddress возвращает адрес датацентра из встроенного списка, или пустую строку, если
+// датацентра с таким номером там нет
+func DefaultDCAddress(dc int) string {
+	return defaultDCList[dc]
+}
+
 func MessageRequireToAck(msg serialize.TL) bool {
 	switch msg.(type) {
 	case /**serialize.Ping,*/ *serialize.MsgsAck:
@@ -23,3 +29,16 @@ func MessageRequireToAck(msg serialize.TL) bool {
 		return true
 	}
 }
+
+// isServiceMessage показывает, что msg это служебное сообщение MTProto, а не запрос к API. сжимать
+// в gzip_packed можно только запросы к API
+// https://core.telegram.org/mtproto/service_messages
+func isServiceMessage(msg serialize.TL) bool {
+	switch msg.(type) {
+	case *serialize.MsgsAck, *serialize.MsgResendReq, *serialize.MsgsStateReq, *serialize.MsgsStateInfo,
+		*serialize.MsgsAllInfo, *PingParams, *RpcDropAnswerParams, *GetFutureSaltsParams, *HttpWaitParams:
+		return true
+	default:
+		return false
+	}
+}
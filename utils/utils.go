@@ -23,20 +23,36 @@ const (
 	magicValueSizeMoreThanSingleByte = 0x7f
 )
 
-// GenerateMessageId отдает по сути unix timestamp но ужасно специфическим образом
-// TODO: нахуя нужно битовое и на -4??
+// GenerateMessageId отдает msg_id для текущего момента по локальным часам. младшие два бита у
+// сообщений клиента всегда нулевые. два вызова подряд могут вернуть одно и то же, поэтому клиент
+// берет msg_id не отсюда, а у своей сессии (см. msgSequence в пакете mtproto)
 func GenerateMessageId() int64 {
+	return MessageIDAt(time.Now())
+}
+
+// MessageIDAt делает msg_id для момента t. клиент должен брать время сервера, а не свое, иначе
+// сервер отклонит сообщение, см. MTProto.serverTime
+func MessageIDAt(t time.Time) int64 {
 	const billion = 1000 * 1000 * 1000
-	unixnano := time.Now().UnixNano()
+	unixnano := t.UnixNano()
 	seconds := unixnano / billion
 	nanoseconds := unixnano % billion
 	return (seconds << 32) | (nanoseconds & -4)
 }
 
+// MessageIDTime достает из msg_id время, когда сообщение было создано. старшие 32 бита это
+// секунды, младшие примерно соответствуют долям секунды
+func MessageIDTime(msgID int64) time.Time {
+	return time.Unix(msgID>>32, int64(uint32(msgID))*1000*1000*1000>>32)
+}
+
 func AuthKeyHash(key []byte) []byte {
 	return dry.Sha1Byte(key)[12:20]
 }
 
+// PacketLengthMTProtoCompatible кодирует длину пакета для abridged транспорта.
+//
+// Deprecated: пакеты теперь упаковывает транспорт, см. transport.NewAbridged
 func PacketLengthMTProtoCompatible(data []byte) []byte {
 	packetSizeInWords := len(data) / wordLen
 	if packetSizeInWords < 127 {
@@ -50,10 +66,13 @@ func PacketLengthMTProtoCompatible(data []byte) []byte {
 }
 
 var (
+	// Deprecated: см. GetPacketLengthMTProtoCompatible
 	ErrPacketSizeIsBigger = errors.New("packet size is more than 127 bytes, require 4 bytes value")
 )
 
 // исходя из переданного числа в bytestoGetInfo считает количество СЛОВ и отдает количество БАЙТ которые нужно прочитать
+//
+// Deprecated: пакеты теперь читает транспорт, см. transport.NewAbridged
 func GetPacketLengthMTProtoCompatible(bytesToGetInfo []byte) (int, error) {
 	if len(bytesToGetInfo) != 1 && len(bytesToGetInfo) != 4 {
 		return 0, fmt.Errorf("invalid size of bytes. require only 1 or 4, got %v", len(bytesToGetInfo))